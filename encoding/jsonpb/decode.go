@@ -39,19 +39,24 @@ type UnmarshalOptions struct {
 	// using protoregistry.GlobalTypes.
 	Resolver *protoregistry.Types
 
+	// Merge merges the input into the given message.
+	// If Merge is false (the default), the message is reset before
+	// unmarshaling, as if by calling proto.Reset. Extension types
+	// registered with the message are retained.
+	Merge bool
+
 	decoder *json.Decoder
 }
 
 // Unmarshal reads the given []byte and populates the given proto.Message using
-// options in UnmarshalOptions object. It will clear the message first before
-// setting the fields. If it returns an error, the given message may be
-// partially set.
+// options in UnmarshalOptions object. Unless Merge is set, it will clear the
+// message first before setting the fields. If it returns an error, the given
+// message may be partially set.
 func (o UnmarshalOptions) Unmarshal(m proto.Message, b []byte) error {
 	mr := m.ProtoReflect()
-	// TODO: Determine if we would like to have an option for merging or only
-	// have merging behavior.  We should at least be consistent with textproto
-	// marshaling.
-	resetMessage(mr)
+	if !o.Merge {
+		proto.Reset(m)
+	}
 
 	if o.Resolver == nil {
 		o.Resolver = protoregistry.GlobalTypes
@@ -60,7 +65,7 @@ func (o UnmarshalOptions) Unmarshal(m proto.Message, b []byte) error {
 
 	var nerr errors.NonFatal
 	if err := o.unmarshalMessage(mr, false); !nerr.Merge(err) {
-		return err
+		return withOffset(err)
 	}
 
 	// Check for EOF.
@@ -69,7 +74,7 @@ func (o UnmarshalOptions) Unmarshal(m proto.Message, b []byte) error {
 		return err
 	}
 	if val.Type() != json.EOF {
-		return unexpectedJSONError{val}
+		return withOffset(unexpectedJSONError{val})
 	}
 
 	if !o.AllowPartial {
@@ -78,25 +83,6 @@ func (o UnmarshalOptions) Unmarshal(m proto.Message, b []byte) error {
 	return nerr.E
 }
 
-// resetMessage clears all fields of given protoreflect.Message.
-func resetMessage(m pref.Message) {
-	knownFields := m.KnownFields()
-	knownFields.Range(func(num pref.FieldNumber, _ pref.Value) bool {
-		knownFields.Clear(num)
-		return true
-	})
-	unknownFields := m.UnknownFields()
-	unknownFields.Range(func(num pref.FieldNumber, _ pref.RawFields) bool {
-		unknownFields.Set(num, nil)
-		return true
-	})
-	extTypes := knownFields.ExtensionTypes()
-	extTypes.Range(func(xt pref.ExtensionType) bool {
-		extTypes.Remove(xt)
-		return true
-	})
-}
-
 // unexpectedJSONError is an error that contains the unexpected json.Value. This
 // is returned by methods to provide callers the read json.Value that it did not
 // expect.
@@ -110,21 +96,44 @@ func (e unexpectedJSONError) Error() string {
 	return newError("unexpected value %s", e.value).Error()
 }
 
+// withOffset converts an unexpectedJSONError into an error which records
+// the offset of the unexpected value, so that it may be annotated with the
+// path to the field being unmarshaled.
+func withOffset(err error) error {
+	if e, ok := err.(unexpectedJSONError); ok {
+		return newError("unexpected value %s", e.value)
+	}
+	return err
+}
+
+// fieldName returns the name used for fd in the path of an error.
+// Extension fields are named by their full name in parentheses.
+func fieldName(fd pref.FieldDescriptor) string {
+	if fd.ExtendedType() != nil {
+		return "(" + string(fd.FullName()) + ")"
+	}
+	return string(fd.Name())
+}
+
 // newError returns an error object. If one of the values passed in is of
 // json.Value type, it produces an error with position info.
 func newError(f string, x ...interface{}) error {
 	var hasValue bool
-	var line, column int
+	var line, column, offset int
 	for i := 0; i < len(x); i++ {
 		if val, ok := x[i].(json.Value); ok {
 			line, column = val.Position()
+			offset = val.Offset()
 			hasValue = true
 			break
 		}
 	}
 	e := errors.New(f, x...)
 	if hasValue {
-		return errors.New("(line %d:%d): %v", line, column, e)
+		return &errors.ParseError{
+			Offset: offset,
+			Err:    errors.New("(line %d:%d): %v", line, column, e),
+		}
 	}
 	return e
 }
@@ -237,8 +246,9 @@ Loop:
 
 		if cardinality := fd.Cardinality(); cardinality == pref.Repeated {
 			// Map or list fields have cardinality of repeated.
-			if err := o.unmarshalRepeated(knownFields, fd); !nerr.Merge(err) {
-				return errors.New("%v|%q: %v", fd.FullName(), name, err)
+			err := errors.AddField(withOffset(o.unmarshalRepeated(knownFields, fd)), fieldName(fd))
+			if !nerr.Merge(err) {
+				return err
 			}
 		} else {
 			// If field is a oneof, check if it has already been set.
@@ -251,8 +261,9 @@ Loop:
 			}
 
 			// Required or optional fields.
-			if err := o.unmarshalSingular(knownFields, fd); !nerr.Merge(err) {
-				return errors.New("%v|%q: %v", fd.FullName(), name, err)
+			err := errors.AddField(withOffset(o.unmarshalSingular(knownFields, fd)), fieldName(fd))
+			if !nerr.Merge(err) {
+				return err
 			}
 		}
 	}
@@ -294,7 +305,14 @@ func (o UnmarshalOptions) unmarshalSingular(knownFields pref.KnownFields, fd pre
 
 	switch fd.Kind() {
 	case pref.MessageKind, pref.GroupKind:
-		m := knownFields.NewMessage(num)
+		// Messages are merged with any existing message value,
+		// including a oneof member which is already set.
+		var m pref.Message
+		if knownFields.Has(num) {
+			m = knownFields.Get(num).Message()
+		} else {
+			m = knownFields.NewMessage(num)
+		}
 		err = o.unmarshalMessage(m, false)
 		val = pref.ValueOf(m)
 	default:
@@ -509,7 +527,7 @@ func unmarshalEnum(jval json.Value, fd pref.FieldDescriptor) (pref.Value, error)
 		if enumVal := fd.EnumType().Values().ByName(pref.Name(s)); enumVal != nil {
 			return pref.ValueOf(enumVal.Number()), nil
 		}
-		return pref.Value{}, newError("invalid enum value %q", jval)
+		return pref.Value{}, &errors.UnknownEnumError{Offset: jval.Offset(), Value: s}
 
 	case json.Number:
 		n, err := jval.Int(32)
@@ -561,13 +579,14 @@ func (o UnmarshalOptions) unmarshalList(list pref.List, fd pref.FieldDescriptor)
 		for {
 			m := list.NewMessage()
 			err := o.unmarshalMessage(m, false)
-			if !nerr.Merge(err) {
-				if e, ok := err.(unexpectedJSONError); ok {
-					if e.value.Type() == json.EndArray {
-						// Done with list.
-						return nerr.E
-					}
+			if e, ok := err.(unexpectedJSONError); ok {
+				if e.value.Type() == json.EndArray {
+					// Done with list.
+					return nerr.E
 				}
+			}
+			err = errors.AddIndex(withOffset(err), strconv.Itoa(list.Len()))
+			if !nerr.Merge(err) {
 				return err
 			}
 			list.Append(pref.ValueOf(m))
@@ -575,13 +594,14 @@ func (o UnmarshalOptions) unmarshalList(list pref.List, fd pref.FieldDescriptor)
 	default:
 		for {
 			val, err := o.unmarshalScalar(fd)
-			if !nerr.Merge(err) {
-				if e, ok := err.(unexpectedJSONError); ok {
-					if e.value.Type() == json.EndArray {
-						// Done with list.
-						return nerr.E
-					}
+			if e, ok := err.(unexpectedJSONError); ok {
+				if e.value.Type() == json.EndArray {
+					// Done with list.
+					return nerr.E
 				}
+			}
+			err = errors.AddIndex(withOffset(err), strconv.Itoa(list.Len()))
+			if !nerr.Merge(err) {
 				return err
 			}
 			list.Append(val)
@@ -648,7 +668,7 @@ Loop:
 		// Unmarshal field name.
 		pkey, err := unmarshalMapKey(name, keyDesc)
 		if !nerr.Merge(err) {
-			return err
+			return errors.AddIndex(err, name)
 		}
 
 		// Check for duplicate field name.
@@ -658,7 +678,7 @@ Loop:
 
 		// Read and unmarshal field value.
 		pval, err := unmarshalMapValue()
-		if !nerr.Merge(err) {
+		if err := errors.AddIndex(withOffset(err), name); !nerr.Merge(err) {
 			return err
 		}
 
@@ -5,13 +5,16 @@
 package jsonpb_test
 
 import (
+	"errors"
 	"math"
+	"strings"
 	"testing"
 
 	protoV1 "github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/v2/encoding/jsonpb"
 	"github.com/golang/protobuf/v2/encoding/testprotos/pb2"
 	"github.com/golang/protobuf/v2/encoding/testprotos/pb3"
+	"github.com/golang/protobuf/v2/internal/encoding/pack"
 	"github.com/golang/protobuf/v2/internal/scalar"
 	"github.com/golang/protobuf/v2/proto"
 	preg "github.com/golang/protobuf/v2/reflect/protoregistry"
@@ -2130,11 +2133,10 @@ func TestUnmarshal(t *testing.T) {
   "value": "` + "abc\xff" + `"
 }`,
 		wantMessage: func() proto.Message {
-			m := &knownpb.StringValue{Value: "abc\xff"}
-			b, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
-			if err != nil {
-				t.Fatalf("error in binary marshaling message for Any.value: %v", err)
-			}
+			// Marshal would report the invalid UTF-8, so encode the value by hand.
+			b := pack.Message{
+				pack.Tag{1, pack.BytesType}, pack.String("abc\xff"),
+			}.Marshal()
 			return &knownpb.Any{
 				TypeUrl: "google.protobuf.StringValue",
 				Value:   b,
@@ -2216,11 +2218,10 @@ func TestUnmarshal(t *testing.T) {
   "value": "` + "abc\xff" + `"
 }`,
 		wantMessage: func() proto.Message {
-			m := &knownpb.Value{Kind: &knownpb.Value_StringValue{"abc\xff"}}
-			b, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
-			if err != nil {
-				t.Fatalf("error in binary marshaling message for Any.value: %v", err)
-			}
+			// Marshal would report the invalid UTF-8, so encode the value by hand.
+			b := pack.Message{
+				pack.Tag{3, pack.BytesType}, pack.String("abc\xff"),
+			}.Marshal()
 			return &knownpb.Any{
 				TypeUrl: "google.protobuf.Value",
 				Value:   b,
@@ -2369,17 +2370,16 @@ func TestUnmarshal(t *testing.T) {
   }
 }`,
 		wantMessage: func() proto.Message {
-			m1 := &knownpb.StringValue{Value: "abc\xff"}
-			b, err := proto.MarshalOptions{Deterministic: true}.Marshal(m1)
-			if err != nil {
-				t.Fatalf("error in binary marshaling message for Any.value: %v", err)
-			}
+			// Marshal would report the invalid UTF-8, so encode the value by hand.
+			b := pack.Message{
+				pack.Tag{1, pack.BytesType}, pack.String("abc\xff"),
+			}.Marshal()
 			m2 := &knownpb.Any{
 				TypeUrl: "google.protobuf.StringValue",
 				Value:   b,
 			}
 			m3 := &pb2.KnownTypes{OptAny: m2}
-			b, err = proto.MarshalOptions{Deterministic: true}.Marshal(m3)
+			b, err := proto.MarshalOptions{Deterministic: true}.Marshal(m3)
 			if err != nil {
 				t.Fatalf("error in binary marshaling message for Any.value: %v", err)
 			}
@@ -2468,6 +2468,59 @@ func TestUnmarshal(t *testing.T) {
 				Paths: []string{"foo_bar", "bar_foo"},
 			},
 		},
+	}, {
+		desc: "existing message is reset",
+		inputMessage: &pb2.Nests{
+			OptNested: &pb2.Nested{OptString: scalar.String("hello")},
+			RptNested: []*pb2.Nested{{}},
+		},
+		inputText: `{
+  "optNested": {
+    "optNested": {}
+  },
+  "rptNested": [{}]
+}`,
+		wantMessage: &pb2.Nests{
+			OptNested: &pb2.Nested{OptNested: &pb2.Nested{}},
+			RptNested: []*pb2.Nested{{}},
+		},
+	}, {
+		desc: "merge into existing message",
+		umo:  jsonpb.UnmarshalOptions{Merge: true},
+		inputMessage: &pb2.Nests{
+			OptNested: &pb2.Nested{OptString: scalar.String("hello")},
+			RptNested: []*pb2.Nested{{}},
+		},
+		inputText: `{
+  "optNested": {
+    "optNested": {}
+  },
+  "rptNested": [{}]
+}`,
+		wantMessage: &pb2.Nests{
+			OptNested: &pb2.Nested{
+				OptString: scalar.String("hello"),
+				OptNested: &pb2.Nested{},
+			},
+			RptNested: []*pb2.Nested{{}, {}},
+		},
+	}, {
+		desc: "merge into existing oneof message",
+		umo:  jsonpb.UnmarshalOptions{Merge: true},
+		inputMessage: &pb3.Oneofs{
+			Union: &pb3.Oneofs_OneofNested{&pb3.Nested{SString: "hello"}},
+		},
+		inputText: `{
+  "oneofNested": {
+    "sNested": {}
+  }
+}`,
+		wantMessage: &pb3.Oneofs{
+			Union: &pb3.Oneofs_OneofNested{&pb3.Nested{
+				SString: "hello",
+				SNested: &pb3.Nested{},
+			}},
+		},
 	}}
 
 	for _, tt := range tests {
@@ -2486,3 +2539,48 @@ func TestUnmarshal(t *testing.T) {
 		})
 	}
 }
+
+func TestUnmarshalResetExtensionTypes(t *testing.T) {
+	m := &pb2.Extensions{}
+	xt := pb2.E_OptExtString.Type
+	proto.SetExtension(m, xt, "x")
+	if err := jsonpb.Unmarshal(m, []byte(`{"optString": "hello"}`)); err != nil {
+		t.Fatalf("Unmarshal() returned error: %v", err)
+	}
+	if proto.HasExtension(m, xt) {
+		t.Errorf("Unmarshal() did not clear extension field %v", xt.FullName())
+	}
+	// The message is reset as if by proto.Reset,
+	// which retains the extension types registered with it.
+	if m.ProtoReflect().KnownFields().ExtensionTypes().ByNumber(xt.Number()) == nil {
+		t.Errorf("Unmarshal() removed registered extension type %v", xt.FullName())
+	}
+}
+
+func TestUnmarshalErrorPath(t *testing.T) {
+	input := `{"rptEnum": ["ONE", "BOGUS"]}`
+	err := jsonpb.Unmarshal(&pb2.Enums{}, []byte(input))
+	var eerr *proto.UnknownEnumError
+	if !errors.As(err, &eerr) {
+		t.Fatalf("Unmarshal() = %v, want UnknownEnumError", err)
+	}
+	if got, want := eerr.Path, "rpt_enum[1]"; got != want {
+		t.Errorf("UnknownEnumError.Path = %q, want %q", got, want)
+	}
+	if got, want := eerr.Offset, strings.Index(input, `"BOGUS"`); got != want {
+		t.Errorf("UnknownEnumError.Offset = %v, want %v", got, want)
+	}
+
+	input = `{"rptNested": [{}, {"optNested": {"optString": 1}}]}`
+	err = jsonpb.Unmarshal(&pb2.Nests{}, []byte(input))
+	var perr *proto.ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("Unmarshal() = %v, want ParseError", err)
+	}
+	if got, want := perr.Path, "rpt_nested[1].opt_nested.opt_string"; got != want {
+		t.Errorf("ParseError.Path = %q, want %q", got, want)
+	}
+	if got, want := perr.Offset, strings.Index(input, "1}"); got != want {
+		t.Errorf("ParseError.Offset = %v, want %v", got, want)
+	}
+}
@@ -11,8 +11,8 @@ import (
 	"testing"
 
 	"github.com/golang/protobuf/v2/encoding/jsonpb"
+	"github.com/golang/protobuf/v2/encoding/protowire"
 	"github.com/golang/protobuf/v2/internal/encoding/pack"
-	"github.com/golang/protobuf/v2/internal/encoding/wire"
 	"github.com/golang/protobuf/v2/internal/scalar"
 	"github.com/golang/protobuf/v2/proto"
 	preg "github.com/golang/protobuf/v2/reflect/protoregistry"
@@ -53,7 +53,7 @@ func setExtension(m proto.Message, xd *protoiface.ExtensionDescV1, val interface
 		return
 	}
 	pval := xd.Type.ValueOf(val)
-	knownFields.Set(wire.Number(xd.Field), pval)
+	knownFields.Set(protowire.Number(xd.Field), pval)
 }
 
 // dhex decodes a hex-string and returns the bytes and panics if s is invalid.
@@ -1550,6 +1550,51 @@ func TestMarshal(t *testing.T) {
 		input: &knownpb.Any{TypeUrl: "foo/pb2.Nested"},
 		want: `{
   "@type": "foo/pb2.Nested"
+}`,
+	}, {
+		desc: "Any with extension in embedded message",
+		mo: jsonpb.MarshalOptions{
+			Resolver: preg.NewTypes(
+				(&pb2.Extensions{}).ProtoReflect().Type(),
+				pb2.E_OptExtString.Type,
+			),
+		},
+		input: func() proto.Message {
+			m := &pb2.Extensions{}
+			setExtension(m, pb2.E_OptExtString, "extension field")
+			b, err := proto.Marshal(m)
+			if err != nil {
+				t.Fatalf("error in binary marshaling message for Any.value: %v", err)
+			}
+			return &knownpb.Any{
+				TypeUrl: "pb2.Extensions",
+				Value:   b,
+			}
+		}(),
+		want: `{
+  "@type": "pb2.Extensions",
+  "[pb2.opt_ext_string]": "extension field"
+}`,
+	}, {
+		desc: "Any with extension not in resolver",
+		mo: jsonpb.MarshalOptions{
+			Resolver: preg.NewTypes((&pb2.Extensions{}).ProtoReflect().Type()),
+		},
+		input: func() proto.Message {
+			m := &pb2.Extensions{OptBool: scalar.Bool(true)}
+			setExtension(m, pb2.E_OptExtString, "extension field")
+			b, err := proto.Marshal(m)
+			if err != nil {
+				t.Fatalf("error in binary marshaling message for Any.value: %v", err)
+			}
+			return &knownpb.Any{
+				TypeUrl: "pb2.Extensions",
+				Value:   b,
+			}
+		}(),
+		want: `{
+  "@type": "pb2.Extensions",
+  "optBool": true
 }`,
 	}, {
 		desc:    "Any without registered type",
@@ -1687,11 +1732,10 @@ func TestMarshal(t *testing.T) {
 			Resolver: preg.NewTypes((&knownpb.StringValue{}).ProtoReflect().Type()),
 		},
 		input: func() proto.Message {
-			m := &knownpb.StringValue{Value: "abc\xff"}
-			b, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
-			if err != nil {
-				t.Fatalf("error in binary marshaling message for Any.value: %v", err)
-			}
+			// Marshal would report the invalid UTF-8, so encode the value by hand.
+			b := pack.Message{
+				pack.Tag{1, pack.BytesType}, pack.String("abc\xff"),
+			}.Marshal()
 			return &knownpb.Any{
 				TypeUrl: "google.protobuf.StringValue",
 				Value:   b,
@@ -1765,11 +1809,10 @@ func TestMarshal(t *testing.T) {
 			Resolver: preg.NewTypes((&knownpb.Value{}).ProtoReflect().Type()),
 		},
 		input: func() proto.Message {
-			m := &knownpb.Value{Kind: &knownpb.Value_StringValue{"abc\xff"}}
-			b, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
-			if err != nil {
-				t.Fatalf("error in binary marshaling message for Any.value: %v", err)
-			}
+			// Marshal would report the invalid UTF-8, so encode the value by hand.
+			b := pack.Message{
+				pack.Tag{3, pack.BytesType}, pack.String("abc\xff"),
+			}.Marshal()
 			return &knownpb.Any{
 				TypeUrl: "type.googleapis.com/google.protobuf.Value",
 				Value:   b,
@@ -177,9 +177,9 @@ func (o MarshalOptions) marshalAny(m pref.Message) error {
 	}
 
 	em := emt.New()
-	// TODO: Need to set types registry in binary unmarshaling.
 	err = proto.UnmarshalOptions{
 		AllowPartial: o.AllowPartial,
+		Resolver:     o.Resolver,
 	}.Unmarshal(valueVal.Bytes(), em.Interface())
 	if !nerr.Merge(err) {
 		return errors.New("%s: unable to unmarshal %q: %v", msgType.FullName(), typeURL, err)
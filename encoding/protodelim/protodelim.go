@@ -0,0 +1,155 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protodelim reads and writes streams of size-delimited messages.
+//
+// Each message in a stream is encoded in the wire format, preceded by its
+// size in bytes encoded as a varint. This is the format used by the
+// writeDelimitedTo and parseDelimitedFrom methods in the C++ and Java
+// protobuf implementations.
+package protodelim
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+
+	"github.com/golang/protobuf/v2/encoding/protowire"
+	"github.com/golang/protobuf/v2/internal/errors"
+	"github.com/golang/protobuf/v2/proto"
+)
+
+// DefaultMaxSize is the maximum size of a message accepted by a Reader
+// if the MaxSize of its UnmarshalOptions is zero.
+const DefaultMaxSize = 64 << 20
+
+// Writer writes size-delimited messages to an io.Writer.
+type Writer struct {
+	w    io.Writer
+	opts proto.MarshalOptions
+	buf  []byte
+}
+
+// NewWriter returns a Writer which marshals messages with opts
+// and writes them to w.
+func NewWriter(w io.Writer, opts proto.MarshalOptions) *Writer {
+	return &Writer{w: w, opts: opts}
+}
+
+// Write writes the size-delimited encoding of m to the underlying writer,
+// with a single call to its Write method.
+//
+// If marshaling m reports a non-fatal error (such as a missing required
+// field), the message is written and the error is returned.
+func (w *Writer) Write(m proto.Message) error {
+	size := w.opts.Size(m)
+	b := protowire.AppendVarint(w.buf[:0], uint64(size))
+	hdrLen := len(b)
+	// Reuse the size computed above rather than computing it again.
+	opts := w.opts
+	opts.UseCachedSize = true
+	b, err := opts.MarshalAppend(b, m)
+	var nerr errors.NonFatal
+	if !nerr.Merge(err) {
+		return err
+	}
+	if len(b)-hdrLen != size {
+		return errors.New("message size changed during marshaling")
+	}
+	w.buf = b
+	if _, err := w.w.Write(b); err != nil {
+		return err
+	}
+	return nerr.E
+}
+
+// Reader reads size-delimited messages from an io.Reader.
+type Reader struct {
+	r    *bufio.Reader
+	opts proto.UnmarshalOptions
+	buf  []byte
+}
+
+// NewReader returns a Reader which reads messages from r
+// and unmarshals them with opts.
+//
+// Messages larger than the MaxSize of opts (or DefaultMaxSize, if zero or
+// negative) are rejected before any memory is allocated for them.
+//
+// Unless r is a *bufio.Reader, the Reader buffers its input and may read
+// from r beyond the end of the last message read. Callers which continue
+// to read from r after the stream of messages should pass a *bufio.Reader,
+// which is used directly.
+func NewReader(r io.Reader, opts proto.UnmarshalOptions) *Reader {
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = DefaultMaxSize
+	}
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &Reader{r: br, opts: opts}
+}
+
+// Read reads the next message from the stream into m.
+//
+// It returns io.EOF if the stream ends cleanly before the next message,
+// and io.ErrUnexpectedEOF if it ends in the middle of a message.
+// If the message exceeds the maximum size, Read skips it and returns
+// proto.ErrMaxSize, so that subsequent calls read the following messages.
+// The Reader is positioned after the message for any error reported by
+// unmarshaling it, but is left in an undefined position by I/O errors.
+func (r *Reader) Read(m proto.Message) error {
+	size, err := r.readSize()
+	if err != nil {
+		return err
+	}
+	if size > uint64(r.opts.MaxSize) {
+		if _, err := io.CopyN(ioutil.Discard, r.r, int64(size)); err != nil {
+			return unexpectedEOF(err)
+		}
+		return proto.ErrMaxSize
+	}
+	// The buffer is reused across messages unless the message may alias it.
+	if uint64(cap(r.buf)) < size || r.opts.AliasBuffer {
+		r.buf = make([]byte, size)
+	}
+	b := r.buf[:size]
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		return unexpectedEOF(err)
+	}
+	return r.opts.Unmarshal(b, m)
+}
+
+// readSize reads the varint size prefix of a message.
+func (r *Reader) readSize() (uint64, error) {
+	var b [binary.MaxVarintLen64]byte
+	for i := range b {
+		c, err := r.r.ReadByte()
+		if err != nil {
+			if i == 0 {
+				return 0, err // io.EOF at a message boundary
+			}
+			return 0, unexpectedEOF(err)
+		}
+		b[i] = c
+		if c < 0x80 {
+			v, n := protowire.ConsumeVarint(b[:i+1])
+			if n < 0 {
+				return 0, protowire.ParseError(n)
+			}
+			return v, nil
+		}
+	}
+	_, n := protowire.ConsumeVarint(b[:])
+	return 0, protowire.ParseError(n)
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
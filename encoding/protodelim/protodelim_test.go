@@ -0,0 +1,125 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protodelim_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/golang/protobuf/v2/encoding/protodelim"
+	"github.com/golang/protobuf/v2/encoding/protowire"
+	"github.com/golang/protobuf/v2/internal/scalar"
+	"github.com/golang/protobuf/v2/proto"
+
+	testpb "github.com/golang/protobuf/v2/internal/testprotos/test"
+)
+
+func TestRoundTrip(t *testing.T) {
+	msgs := []*testpb.TestAllTypes{
+		{},
+		{OptionalInt32: scalar.Int32(1)},
+		{OptionalString: scalar.String(string(make([]byte, 1000)))},
+		{RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{{A: scalar.Int32(2)}}},
+		{RepeatedInt32: []int32{1, 2, 3, 4, 5}},
+	}
+	for _, opts := range []proto.MarshalOptions{
+		{Deterministic: true},
+		// Canonical serialization packs repeated scalar fields.
+		{Canonical: true},
+	} {
+		var buf bytes.Buffer
+		w := protodelim.NewWriter(&buf, opts)
+		for _, m := range msgs {
+			if err := w.Write(m); err != nil {
+				t.Fatalf("%+v: Write(%v) error: %v", opts, m, err)
+			}
+		}
+
+		r := protodelim.NewReader(&buf, proto.UnmarshalOptions{})
+		for _, want := range msgs {
+			got := &testpb.TestAllTypes{}
+			if err := r.Read(got); err != nil {
+				t.Fatalf("%+v: Read() error: %v", opts, err)
+			}
+			if !proto.Equal(got, want) {
+				t.Errorf("%+v: Read() = %v, want %v", opts, got, want)
+			}
+		}
+		if err := r.Read(&testpb.TestAllTypes{}); err != io.EOF {
+			t.Errorf("%+v: Read() at end of stream = %v, want io.EOF", opts, err)
+		}
+	}
+}
+
+func TestReadMaxSize(t *testing.T) {
+	small := &testpb.TestAllTypes{OptionalInt32: scalar.Int32(1)}
+	large := &testpb.TestAllTypes{OptionalBytes: make([]byte, 100)}
+	var buf bytes.Buffer
+	w := protodelim.NewWriter(&buf, proto.MarshalOptions{})
+	for _, m := range []proto.Message{large, small} {
+		if err := w.Write(m); err != nil {
+			t.Fatalf("Write(%v) error: %v", m, err)
+		}
+	}
+
+	r := protodelim.NewReader(&buf, proto.UnmarshalOptions{MaxSize: 50})
+	if err := r.Read(&testpb.TestAllTypes{}); err != proto.ErrMaxSize {
+		t.Errorf("Read() of large message = %v, want ErrMaxSize", err)
+	}
+	got := &testpb.TestAllTypes{}
+	if err := r.Read(got); err != nil {
+		t.Fatalf("Read() after large message error: %v", err)
+	}
+	if !proto.Equal(got, small) {
+		t.Errorf("Read() after large message = %v, want %v", got, small)
+	}
+}
+
+func TestReadNegativeMaxSize(t *testing.T) {
+	// A negative MaxSize is treated as zero, rather than as no limit.
+	size := uint64(protodelim.DefaultMaxSize + 1)
+	stream := io.MultiReader(
+		bytes.NewReader(protowire.AppendVarint(nil, size)),
+		io.LimitReader(zeros{}, int64(size)),
+	)
+	r := protodelim.NewReader(stream, proto.UnmarshalOptions{MaxSize: -1})
+	if err := r.Read(&testpb.TestAllTypes{}); err != proto.ErrMaxSize {
+		t.Errorf("Read() of message larger than DefaultMaxSize = %v, want ErrMaxSize", err)
+	}
+}
+
+// zeros is an io.Reader of an endless stream of zero bytes.
+type zeros struct{}
+
+func (zeros) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = 0
+	}
+	return len(b), nil
+}
+
+func TestReadTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	w := protodelim.NewWriter(&buf, proto.MarshalOptions{})
+	if err := w.Write(&testpb.TestAllTypes{OptionalInt32: scalar.Int32(1)}); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	b := buf.Bytes()
+	for _, test := range []struct {
+		desc string
+		in   []byte
+		want error
+	}{
+		{"empty", nil, io.EOF},
+		{"truncated size", []byte{0x80}, io.ErrUnexpectedEOF},
+		{"truncated message", b[:len(b)-1], io.ErrUnexpectedEOF},
+	} {
+		r := protodelim.NewReader(bytes.NewReader(test.in), proto.UnmarshalOptions{})
+		if err := r.Read(&testpb.TestAllTypes{}); err != test.want {
+			t.Errorf("%v: Read() = %v, want %v", test.desc, err, test.want)
+		}
+	}
+}
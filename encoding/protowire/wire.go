@@ -2,10 +2,23 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// Package wire parses and formats the protobuf wire encoding.
+// Package protowire parses and formats the low-level raw wire encoding.
 //
 // See https://developers.google.com/protocol-buffers/docs/encoding.
-package wire
+//
+// For marshaling and unmarshaling entire protobuf messages,
+// use the proto package instead.
+//
+// Functions which parse the wire format (those named Consume) return
+// the number of bytes consumed. They do not return an error; instead,
+// a negative length reports that the input is invalid, and ParseError
+// converts the length into a descriptive error. Such a length may be passed
+// to ParseError as is, or returned from a larger parsing function which uses
+// the same convention.
+//
+// Functions which format the wire format (those named Append) append to and
+// return a byte slice, and perform no validation of their inputs.
+package protowire
 
 import (
 	"io"
@@ -45,6 +58,12 @@ const (
 	EndGroupType   Type = 4
 )
 
+// IsValid reports whether the wire type is one of the types defined above.
+// Parsing a record with any other wire type fails.
+func (t Type) IsValid() bool {
+	return VarintType <= t && t <= Fixed32Type
+}
+
 const (
 	_ = -iota
 	errCodeTruncated
@@ -52,8 +71,13 @@ const (
 	errCodeOverflow
 	errCodeReserved
 	errCodeEndGroup
+	errCodeRecursionLimit
 )
 
+// DefaultRecursionLimit is the default maximum nesting depth of groups
+// accepted by ConsumeFieldValue.
+const DefaultRecursionLimit = 10000
+
 var (
 	errFieldNumber = errors.New("invalid field number")
 	errOverflow    = errors.New("variable length integer overflow")
@@ -64,6 +88,10 @@ var (
 
 // ParseError converts an error code into an error value.
 // This returns nil if n is a non-negative number.
+//
+// Truncated input is reported as io.ErrUnexpectedEOF.
+// Exceeding the recursion limit of ConsumeFieldValueDepth is reported as
+// an error which matches proto.ErrRecursionLimit when tested with errors.Is.
 func ParseError(n int) error {
 	if n >= 0 {
 		return nil
@@ -79,6 +107,8 @@ func ParseError(n int) error {
 		return errReserved
 	case errCodeEndGroup:
 		return errEndGroup
+	case errCodeRecursionLimit:
+		return errors.ErrRecursionLimit
 	default:
 		return errParse
 	}
@@ -108,7 +138,14 @@ func ConsumeField(b []byte) (Number, Type, int) {
 //
 // When parsing a group, the length includes the end group marker and
 // the end group is verified to match the starting field number.
+// Groups may be nested at most DefaultRecursionLimit levels deep.
 func ConsumeFieldValue(num Number, typ Type, b []byte) (n int) {
+	return ConsumeFieldValueDepth(num, typ, b, DefaultRecursionLimit)
+}
+
+// ConsumeFieldValueDepth is like ConsumeFieldValue, but groups may be nested
+// at most depth levels deep.
+func ConsumeFieldValueDepth(num Number, typ Type, b []byte, depth int) (n int) {
 	switch typ {
 	case VarintType:
 		_, n = ConsumeVarint(b)
@@ -123,6 +160,9 @@ func ConsumeFieldValue(num Number, typ Type, b []byte) (n int) {
 		_, n = ConsumeBytes(b)
 		return n
 	case StartGroupType:
+		if depth <= 0 {
+			return errCodeRecursionLimit
+		}
 		n0 := len(b)
 		for {
 			num2, typ2, n := ConsumeTag(b)
@@ -137,7 +177,7 @@ func ConsumeFieldValue(num Number, typ Type, b []byte) (n int) {
 				return n0 - len(b)
 			}
 
-			n = ConsumeFieldValue(num2, typ2, b)
+			n = ConsumeFieldValueDepth(num2, typ2, b, depth-1)
 			if n < 0 {
 				return n // forward error code
 			}
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-package wire
+package protowire
 
 import (
 	"bytes"
@@ -678,3 +678,45 @@ func TestZigZag(t *testing.T) {
 		}
 	}
 }
+
+func TestValid(t *testing.T) {
+	numbers := []struct {
+		num  Number
+		want bool
+	}{
+		{0, false},
+		{MinValidNumber, true},
+		{FirstReservedNumber - 1, true},
+		{FirstReservedNumber, false},
+		{LastReservedNumber, false},
+		{LastReservedNumber + 1, true},
+		{MaxValidNumber, true},
+		{MaxValidNumber + 1, false},
+		{-1, false},
+	}
+	for _, tt := range numbers {
+		if got := tt.num.IsValid(); got != tt.want {
+			t.Errorf("Number(%d).IsValid() = %v, want %v", tt.num, got, tt.want)
+		}
+	}
+
+	types := []struct {
+		typ  Type
+		want bool
+	}{
+		{VarintType, true},
+		{Fixed64Type, true},
+		{BytesType, true},
+		{StartGroupType, true},
+		{EndGroupType, true},
+		{Fixed32Type, true},
+		{6, false},
+		{7, false},
+		{-1, false},
+	}
+	for _, tt := range types {
+		if got := tt.typ.IsValid(); got != tt.want {
+			t.Errorf("Type(%d).IsValid() = %v, want %v", tt.typ, got, tt.want)
+		}
+	}
+}
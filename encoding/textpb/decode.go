@@ -6,6 +6,7 @@ package textpb
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/golang/protobuf/v2/internal/encoding/text"
@@ -36,19 +37,24 @@ type UnmarshalOptions struct {
 	// and processing Any. If Resolver is not set, unmarshaling will default to
 	// using protoregistry.GlobalTypes.
 	Resolver *protoregistry.Types
+
+	// Merge merges the input into the given message.
+	// If Merge is false (the default), the message is reset before
+	// unmarshaling, as if by calling proto.Reset. Extension types
+	// registered with the message are retained.
+	Merge bool
 }
 
 // Unmarshal reads the given []byte and populates the given proto.Message using options in
-// UnmarshalOptions object.
+// UnmarshalOptions object. Unless Merge is set, it will clear the message first before
+// setting the fields.
 func (o UnmarshalOptions) Unmarshal(m proto.Message, b []byte) error {
 	var nerr errors.NonFatal
 
 	mr := m.ProtoReflect()
-	// Clear all fields before populating it.
-	// TODO: Determine if this needs to be consistent with jsonpb and binary unmarshal where
-	// behavior is to merge values into existing message. If decision is to not clear the fields
-	// ahead, code will need to be updated properly when merging nested messages.
-	resetMessage(mr)
+	if !o.Merge {
+		proto.Reset(m)
+	}
 
 	// Parse into text.Value of message type.
 	val, err := text.Unmarshal(b)
@@ -71,26 +77,6 @@ func (o UnmarshalOptions) Unmarshal(m proto.Message, b []byte) error {
 	return nerr.E
 }
 
-// resetMessage clears all fields of given protoreflect.Message.
-// TODO: This should go into the proto package.
-func resetMessage(m pref.Message) {
-	knownFields := m.KnownFields()
-	knownFields.Range(func(num pref.FieldNumber, _ pref.Value) bool {
-		knownFields.Clear(num)
-		return true
-	})
-	unknownFields := m.UnknownFields()
-	unknownFields.Range(func(num pref.FieldNumber, _ pref.RawFields) bool {
-		unknownFields.Set(num, nil)
-		return true
-	})
-	extTypes := knownFields.ExtensionTypes()
-	extTypes.Range(func(xt pref.ExtensionType) bool {
-		extTypes.Remove(xt)
-		return true
-	})
-}
-
 // unmarshalMessage unmarshals a [][2]text.Value message into the given protoreflect.Message.
 func (o UnmarshalOptions) unmarshalMessage(tmsg [][2]text.Value, m pref.Message) error {
 	var nerr errors.NonFatal
@@ -138,7 +124,7 @@ func (o UnmarshalOptions) unmarshalMessage(tmsg [][2]text.Value, m pref.Message)
 				var err error
 				xt, err = o.findExtension(xtName)
 				if err != nil && err != protoregistry.NotFound {
-					return errors.New("unable to resolve [%v]: %v", xtName, err)
+					return errors.AddOffset(errors.New("unable to resolve [%v]: %v", xtName, err), tkey.Offset())
 				}
 				if xt != nil {
 					xtTypes.Register(xt)
@@ -153,12 +139,13 @@ func (o UnmarshalOptions) unmarshalMessage(tmsg [][2]text.Value, m pref.Message)
 				continue
 			}
 			// TODO: Can provide option to ignore unknown message fields.
-			return errors.New("%v contains unknown field: %v", msgType.FullName(), tkey)
+			return errors.AddOffset(errors.New("%v contains unknown field: %v", msgType.FullName(), tkey), tkey.Offset())
 		}
 
 		if cardinality := fd.Cardinality(); cardinality == pref.Repeated {
 			// Map or list fields have cardinality of repeated.
-			if err := o.unmarshalRepeated(tval, fd, knownFields); !nerr.Merge(err) {
+			err := errors.AddField(o.unmarshalRepeated(tval, fd, knownFields), fieldName(fd))
+			if !nerr.Merge(err) {
 				return err
 			}
 		} else {
@@ -166,7 +153,7 @@ func (o UnmarshalOptions) unmarshalMessage(tmsg [][2]text.Value, m pref.Message)
 			if od := fd.OneofType(); od != nil {
 				idx := uint64(od.Index())
 				if seenOneofs.Has(idx) {
-					return errors.New("oneof %v is already set", od.FullName())
+					return errors.AddOffset(errors.New("oneof %v is already set", od.FullName()), tkey.Offset())
 				}
 				seenOneofs.Set(idx)
 			}
@@ -174,9 +161,10 @@ func (o UnmarshalOptions) unmarshalMessage(tmsg [][2]text.Value, m pref.Message)
 			// Required or optional fields.
 			num := uint64(fd.Number())
 			if seenNums.Has(num) {
-				return errors.New("non-repeated field %v is repeated", fd.FullName())
+				return errors.AddOffset(errors.New("non-repeated field %v is repeated", fd.FullName()), tkey.Offset())
 			}
-			if err := o.unmarshalSingular(tval, fd, knownFields); !nerr.Merge(err) {
+			err := errors.AddField(o.unmarshalSingular(tval, fd, knownFields), fieldName(fd))
+			if !nerr.Merge(err) {
 				return err
 			}
 			seenNums.Set(num)
@@ -186,6 +174,15 @@ func (o UnmarshalOptions) unmarshalMessage(tmsg [][2]text.Value, m pref.Message)
 	return nerr.E
 }
 
+// fieldName returns the name used for fd in the path of an error.
+// Extension fields are named by their full name in parentheses.
+func fieldName(fd pref.FieldDescriptor) string {
+	if fd.ExtendedType() != nil {
+		return "(" + string(fd.FullName()) + ")"
+	}
+	return string(fd.Name())
+}
+
 // findExtension returns protoreflect.ExtensionType from the Resolver if found.
 func (o UnmarshalOptions) findExtension(xtName pref.FullName) (pref.ExtensionType, error) {
 	xt, err := o.Resolver.FindExtensionByName(xtName)
@@ -210,9 +207,16 @@ func (o UnmarshalOptions) unmarshalSingular(input text.Value, fd pref.FieldDescr
 	switch fd.Kind() {
 	case pref.MessageKind, pref.GroupKind:
 		if input.Type() != text.Message {
-			return errors.New("%v contains invalid message/group value: %v", fd.FullName(), input)
+			return errors.AddOffset(errors.New("%v contains invalid message/group value: %v", fd.FullName(), input), input.Offset())
+		}
+		// Messages are merged with any existing message value,
+		// including a oneof member which is already set.
+		var m pref.Message
+		if knownFields.Has(num) {
+			m = knownFields.Get(num).Message()
+		} else {
+			m = knownFields.NewMessage(num)
 		}
-		m := knownFields.NewMessage(num)
 		if err := o.unmarshalMessage(input.Message(), m); !nerr.Merge(err) {
 			return err
 		}
@@ -309,12 +313,13 @@ func unmarshalScalar(input text.Value, fd pref.FieldDescriptor) (pref.Value, err
 			if enumVal := fd.EnumType().Values().ByName(name); enumVal != nil {
 				return pref.ValueOf(enumVal.Number()), nil
 			}
+			return pref.Value{}, &errors.UnknownEnumError{Offset: input.Offset(), Value: string(name)}
 		}
 	default:
 		panic(fmt.Sprintf("invalid scalar kind %v", kind))
 	}
 
-	return pref.Value{}, errors.New("%v contains invalid scalar value: %v", fd.FullName(), input)
+	return pref.Value{}, errors.AddOffset(errors.New("%v contains invalid scalar value: %v", fd.FullName(), input), input.Offset())
 }
 
 // unmarshalList unmarshals given []text.Value into given protoreflect.List.
@@ -325,10 +330,11 @@ func (o UnmarshalOptions) unmarshalList(inputList []text.Value, fd pref.FieldDes
 	case pref.MessageKind, pref.GroupKind:
 		for _, input := range inputList {
 			if input.Type() != text.Message {
-				return errors.New("%v contains invalid message/group value: %v", fd.FullName(), input)
+				return errors.AddOffset(errors.New("%v contains invalid message/group value: %v", fd.FullName(), input), input.Offset())
 			}
 			m := list.NewMessage()
-			if err := o.unmarshalMessage(input.Message(), m); !nerr.Merge(err) {
+			err := o.unmarshalMessage(input.Message(), m)
+			if err := errors.AddIndex(err, strconv.Itoa(list.Len())); !nerr.Merge(err) {
 				return err
 			}
 			list.Append(pref.ValueOf(m))
@@ -336,7 +342,7 @@ func (o UnmarshalOptions) unmarshalList(inputList []text.Value, fd pref.FieldDes
 	default:
 		for _, input := range inputList {
 			val, err := unmarshalScalar(input, fd)
-			if !nerr.Merge(err) {
+			if err := errors.AddIndex(err, strconv.Itoa(list.Len())); !nerr.Merge(err) {
 				return err
 			}
 			list.Append(val)
@@ -363,7 +369,7 @@ func (o UnmarshalOptions) unmarshalMap(input []text.Value, fd pref.FieldDescript
 
 	for _, entry := range input {
 		if entry.Type() != text.Message {
-			return errors.New("%v contains invalid map entry: %v", fd.FullName(), entry)
+			return errors.AddOffset(errors.New("%v contains invalid map entry: %v", fd.FullName(), entry), entry.Offset())
 		}
 		tkey, tval, err := parseMapEntry(entry.Message(), fd.FullName())
 		if !nerr.Merge(err) {
@@ -374,7 +380,7 @@ func (o UnmarshalOptions) unmarshalMap(input []text.Value, fd pref.FieldDescript
 			return err
 		}
 		err = unmarshalMapValue(tval, pkey, valDesc, mmap)
-		if !nerr.Merge(err) {
+		if err := errors.AddIndex(err, fmt.Sprint(pkey)); !nerr.Merge(err) {
 			return err
 		}
 	}
@@ -393,12 +399,12 @@ func parseMapEntry(mapEntry [][2]text.Value, name pref.FullName) (key text.Value
 			switch keyStr {
 			case "key":
 				if key.Type() != 0 {
-					return key, value, errors.New("%v contains duplicate key field", name)
+					return key, value, errors.AddOffset(errors.New("%v contains duplicate key field", name), field[0].Offset())
 				}
 				key = field[1]
 			case "value":
 				if value.Type() != 0 {
-					return key, value, errors.New("%v contains duplicate value field", name)
+					return key, value, errors.AddOffset(errors.New("%v contains duplicate value field", name), field[0].Offset())
 				}
 				value = field[1]
 			default:
@@ -407,7 +413,7 @@ func parseMapEntry(mapEntry [][2]text.Value, name pref.FullName) (key text.Value
 		}
 		if !ok {
 			// TODO: Do not return error if ignore unknown option is added and enabled.
-			return key, value, errors.New("%v contains unknown map entry name: %v", name, field[0])
+			return key, value, errors.AddOffset(errors.New("%v contains unknown map entry name: %v", name, field[0]), field[0].Offset())
 		}
 	}
 	return key, value, nil
@@ -423,7 +429,7 @@ func unmarshalMapKey(input text.Value, fd pref.FieldDescriptor) (pref.MapKey, er
 
 	val, err := unmarshalScalar(input, fd)
 	if err != nil {
-		return pref.MapKey{}, errors.New("%v contains invalid key: %v", fd.FullName(), input)
+		return pref.MapKey{}, errors.AddOffset(errors.New("%v contains invalid key: %v", fd.FullName(), input), input.Offset())
 	}
 	return val.MapKey(), nil
 }
@@ -482,7 +488,7 @@ func (o UnmarshalOptions) unmarshalAny(tfield [2]text.Value, knownFields pref.Kn
 
 	mt, err := o.Resolver.FindMessageByURL(typeURL)
 	if !nerr.Merge(err) {
-		return errors.New("unable to resolve message [%v]: %v", typeURL, err)
+		return errors.AddOffset(errors.New("unable to resolve message [%v]: %v", typeURL, err), tfield[0].Offset())
 	}
 	// Create new message for the embedded message type and unmarshal the
 	// value into it.
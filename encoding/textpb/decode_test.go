@@ -5,7 +5,9 @@
 package textpb_test
 
 import (
+	"errors"
 	"math"
+	"strings"
 	"testing"
 
 	protoV1 "github.com/golang/protobuf/proto"
@@ -1454,6 +1456,59 @@ unknown: ""
 type_url: "pb2.Nested"
 `,
 		wantErr: true,
+	}, {
+		desc: "existing message is reset",
+		inputMessage: &pb2.Nests{
+			OptNested: &pb2.Nested{OptString: scalar.String("hello")},
+			RptNested: []*pb2.Nested{{}},
+		},
+		inputText: `
+opt_nested: {
+  opt_nested: {}
+}
+rpt_nested: {}
+`,
+		wantMessage: &pb2.Nests{
+			OptNested: &pb2.Nested{OptNested: &pb2.Nested{}},
+			RptNested: []*pb2.Nested{{}},
+		},
+	}, {
+		desc: "merge into existing message",
+		umo:  textpb.UnmarshalOptions{Merge: true},
+		inputMessage: &pb2.Nests{
+			OptNested: &pb2.Nested{OptString: scalar.String("hello")},
+			RptNested: []*pb2.Nested{{}},
+		},
+		inputText: `
+opt_nested: {
+  opt_nested: {}
+}
+rpt_nested: {}
+`,
+		wantMessage: &pb2.Nests{
+			OptNested: &pb2.Nested{
+				OptString: scalar.String("hello"),
+				OptNested: &pb2.Nested{},
+			},
+			RptNested: []*pb2.Nested{{}, {}},
+		},
+	}, {
+		desc: "merge into existing oneof message",
+		umo:  textpb.UnmarshalOptions{Merge: true},
+		inputMessage: &pb3.Oneofs{
+			Union: &pb3.Oneofs_OneofNested{&pb3.Nested{SString: "hello"}},
+		},
+		inputText: `
+oneof_nested: {
+  s_nested: {}
+}
+`,
+		wantMessage: &pb3.Oneofs{
+			Union: &pb3.Oneofs_OneofNested{&pb3.Nested{
+				SString: "hello",
+				SNested: &pb3.Nested{},
+			}},
+		},
 	}}
 
 	for _, tt := range tests {
@@ -1473,3 +1528,66 @@ type_url: "pb2.Nested"
 		})
 	}
 }
+
+func TestUnmarshalResetExtensionTypes(t *testing.T) {
+	m := &pb2.Extensions{}
+	xt := pb2.E_OptExtString.Type
+	proto.SetExtension(m, xt, "x")
+	if err := textpb.Unmarshal(m, []byte(`opt_string: "hello"`)); err != nil {
+		t.Fatalf("Unmarshal() returned error: %v", err)
+	}
+	if proto.HasExtension(m, xt) {
+		t.Errorf("Unmarshal() did not clear extension field %v", xt.FullName())
+	}
+	// The message is reset as if by proto.Reset,
+	// which retains the extension types registered with it.
+	if m.ProtoReflect().KnownFields().ExtensionTypes().ByNumber(xt.Number()) == nil {
+		t.Errorf("Unmarshal() removed registered extension type %v", xt.FullName())
+	}
+}
+
+func TestUnmarshalErrorPath(t *testing.T) {
+	err := textpb.Unmarshal(&pb2.Enums{}, []byte(`rpt_enum: [ONE, BOGUS]`))
+	var eerr *proto.UnknownEnumError
+	if !errors.As(err, &eerr) {
+		t.Fatalf("Unmarshal() = %v, want UnknownEnumError", err)
+	}
+	if got, want := eerr.Path, "rpt_enum[1]"; got != want {
+		t.Errorf("UnknownEnumError.Path = %q, want %q", got, want)
+	}
+	if got, want := eerr.Value, "BOGUS"; got != want {
+		t.Errorf("UnknownEnumError.Value = %q, want %q", got, want)
+	}
+	if got, want := eerr.Offset, strings.Index(`rpt_enum: [ONE, BOGUS]`, "BOGUS"); got != want {
+		t.Errorf("UnknownEnumError.Offset = %v, want %v", got, want)
+	}
+
+	for _, tt := range []struct {
+		input, path, at string
+	}{
+		{`opt_nested: {opt_nested: {opt_string: 1}}`, "opt_nested.opt_nested.opt_string", "1}"},
+		{`opt_nested: {opt_nested: {bogus: "a"}}`, "opt_nested.opt_nested", "bogus"},
+	} {
+		err := textpb.Unmarshal(&pb2.Nests{}, []byte(tt.input))
+		var perr *proto.ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("Unmarshal(%q) = %v, want ParseError", tt.input, err)
+		}
+		if got, want := perr.Path, tt.path; got != want {
+			t.Errorf("Unmarshal(%q): ParseError.Path = %q, want %q", tt.input, got, want)
+		}
+		if got, want := perr.Offset, strings.Index(tt.input, tt.at); got != want {
+			t.Errorf("Unmarshal(%q): ParseError.Offset = %v, want %v", tt.input, got, want)
+		}
+	}
+
+	input := `opt_nested: {opt_nested: {opt_string: "a"}} rpt_nested: {}}`
+	err = textpb.Unmarshal(&pb2.Nests{}, []byte(input))
+	var perr *proto.ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("Unmarshal() = %v, want ParseError", err)
+	}
+	if got, want := perr.Offset, strings.LastIndex(input, "}"); got != want {
+		t.Errorf("ParseError.Offset = %v, want %v", got, want)
+	}
+}
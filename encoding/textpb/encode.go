@@ -8,8 +8,8 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/golang/protobuf/v2/encoding/protowire"
 	"github.com/golang/protobuf/v2/internal/encoding/text"
-	"github.com/golang/protobuf/v2/internal/encoding/wire"
 	"github.com/golang/protobuf/v2/internal/errors"
 	"github.com/golang/protobuf/v2/internal/fieldnum"
 	"github.com/golang/protobuf/v2/internal/mapsort"
@@ -312,21 +312,21 @@ func isMessageSetExtension(xt pref.ExtensionType) bool {
 func appendUnknown(fields [][2]text.Value, b []byte) [][2]text.Value {
 	for len(b) > 0 {
 		var value interface{}
-		num, wtype, n := wire.ConsumeTag(b)
+		num, wtype, n := protowire.ConsumeTag(b)
 		b = b[n:]
 
 		switch wtype {
-		case wire.VarintType:
-			value, n = wire.ConsumeVarint(b)
-		case wire.Fixed32Type:
-			value, n = wire.ConsumeFixed32(b)
-		case wire.Fixed64Type:
-			value, n = wire.ConsumeFixed64(b)
-		case wire.BytesType:
-			value, n = wire.ConsumeBytes(b)
-		case wire.StartGroupType:
+		case protowire.VarintType:
+			value, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed32Type:
+			value, n = protowire.ConsumeFixed32(b)
+		case protowire.Fixed64Type:
+			value, n = protowire.ConsumeFixed64(b)
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(b)
+		case protowire.StartGroupType:
 			var v []byte
-			v, n = wire.ConsumeGroup(num, b)
+			v, n = protowire.ConsumeGroup(num, b)
 			var msg [][2]text.Value
 			value = appendUnknown(msg, v)
 		default:
@@ -351,9 +351,9 @@ func (o MarshalOptions) marshalAny(m pref.Message) (text.Value, error) {
 		return text.Value{}, err
 	}
 	em := emt.New().Interface()
-	// TODO: Need to set types registry in binary unmarshaling.
 	err = proto.UnmarshalOptions{
 		AllowPartial: o.AllowPartial,
+		Resolver:     o.Resolver,
 	}.Unmarshal(value.Bytes(), em)
 	if !nerr.Merge(err) {
 		return text.Value{}, err
@@ -10,10 +10,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/golang/protobuf/v2/encoding/protowire"
 	"github.com/golang/protobuf/v2/encoding/textpb"
 	"github.com/golang/protobuf/v2/internal/detrand"
 	"github.com/golang/protobuf/v2/internal/encoding/pack"
-	"github.com/golang/protobuf/v2/internal/encoding/wire"
 	"github.com/golang/protobuf/v2/internal/scalar"
 	"github.com/golang/protobuf/v2/proto"
 	preg "github.com/golang/protobuf/v2/reflect/protoregistry"
@@ -56,7 +56,7 @@ func setExtension(m proto.Message, xd *protoiface.ExtensionDescV1, val interface
 		return
 	}
 	pval := xd.Type.ValueOf(val)
-	knownFields.Set(wire.Number(xd.Field), pval)
+	knownFields.Set(protowire.Number(xd.Field), pval)
 }
 
 // dhex decodes a hex-string and returns the bytes and panics if s is invalid.
@@ -0,0 +1,473 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import "text/template"
+
+// GoKind is a protobuf kind paired with the Go type used to represent it
+// in a generated message struct.
+type GoKind struct {
+	Name     string
+	WireType WireType
+
+	// GoType is the Go type of the struct field.
+	GoType Expr
+
+	// Accessor is the name of the pointer method that returns a typed
+	// pointer to a field of type GoType.
+	Accessor string
+
+	// ToGoValue converts the wire value v into a GoType.
+	ToGoValue Expr
+
+	// FromGoValue converts the GoType v into a wire value.
+	FromGoValue Expr
+
+	// IsZero reports whether the GoType v is the zero value.
+	IsZero Expr
+}
+
+var GoKinds = []GoKind{
+	{
+		Name:        "Bool",
+		WireType:    WireVarint,
+		GoType:      "bool",
+		Accessor:    "Bool",
+		ToGoValue:   "protowire.DecodeBool(v)",
+		FromGoValue: "protowire.EncodeBool(v)",
+		IsZero:      "!v",
+	},
+	{
+		Name:        "Int32",
+		WireType:    WireVarint,
+		GoType:      "int32",
+		Accessor:    "Int32",
+		ToGoValue:   "int32(v)",
+		FromGoValue: "uint64(v)",
+		IsZero:      "v == 0",
+	},
+	{
+		Name:        "Sint32",
+		WireType:    WireVarint,
+		GoType:      "int32",
+		Accessor:    "Int32",
+		ToGoValue:   "int32(protowire.DecodeZigZag(v & math.MaxUint32))",
+		FromGoValue: "protowire.EncodeZigZag(int64(v))",
+		IsZero:      "v == 0",
+	},
+	{
+		Name:        "Uint32",
+		WireType:    WireVarint,
+		GoType:      "uint32",
+		Accessor:    "Uint32",
+		ToGoValue:   "uint32(v)",
+		FromGoValue: "uint64(v)",
+		IsZero:      "v == 0",
+	},
+	{
+		Name:        "Int64",
+		WireType:    WireVarint,
+		GoType:      "int64",
+		Accessor:    "Int64",
+		ToGoValue:   "int64(v)",
+		FromGoValue: "uint64(v)",
+		IsZero:      "v == 0",
+	},
+	{
+		Name:        "Sint64",
+		WireType:    WireVarint,
+		GoType:      "int64",
+		Accessor:    "Int64",
+		ToGoValue:   "protowire.DecodeZigZag(v)",
+		FromGoValue: "protowire.EncodeZigZag(v)",
+		IsZero:      "v == 0",
+	},
+	{
+		Name:        "Uint64",
+		WireType:    WireVarint,
+		GoType:      "uint64",
+		Accessor:    "Uint64",
+		ToGoValue:   "v",
+		FromGoValue: "v",
+		IsZero:      "v == 0",
+	},
+	{
+		Name:        "Sfixed32",
+		WireType:    WireFixed32,
+		GoType:      "int32",
+		Accessor:    "Int32",
+		ToGoValue:   "int32(v)",
+		FromGoValue: "uint32(v)",
+		IsZero:      "v == 0",
+	},
+	{
+		Name:        "Fixed32",
+		WireType:    WireFixed32,
+		GoType:      "uint32",
+		Accessor:    "Uint32",
+		ToGoValue:   "v",
+		FromGoValue: "v",
+		IsZero:      "v == 0",
+	},
+	{
+		Name:        "Float",
+		WireType:    WireFixed32,
+		GoType:      "float32",
+		Accessor:    "Float32",
+		ToGoValue:   "math.Float32frombits(v)",
+		FromGoValue: "math.Float32bits(v)",
+		IsZero:      "v == 0",
+	},
+	{
+		Name:        "Sfixed64",
+		WireType:    WireFixed64,
+		GoType:      "int64",
+		Accessor:    "Int64",
+		ToGoValue:   "int64(v)",
+		FromGoValue: "uint64(v)",
+		IsZero:      "v == 0",
+	},
+	{
+		Name:        "Fixed64",
+		WireType:    WireFixed64,
+		GoType:      "uint64",
+		Accessor:    "Uint64",
+		ToGoValue:   "v",
+		FromGoValue: "v",
+		IsZero:      "v == 0",
+	},
+	{
+		Name:        "Double",
+		WireType:    WireFixed64,
+		GoType:      "float64",
+		Accessor:    "Float64",
+		ToGoValue:   "math.Float64frombits(v)",
+		FromGoValue: "math.Float64bits(v)",
+		IsZero:      "v == 0",
+	},
+	{
+		Name:        "String",
+		WireType:    WireBytes,
+		GoType:      "string",
+		Accessor:    "String",
+		ToGoValue:   "opts.toString(v)",
+		FromGoValue: "v",
+		IsZero:      "len(v) == 0",
+	},
+	{
+		Name:        "Bytes",
+		WireType:    WireBytes,
+		GoType:      "[]byte",
+		Accessor:    "Bytes",
+		ToGoValue:   "opts.toBytes(v)",
+		FromGoValue: "v",
+		IsZero:      "len(v) == 0",
+	},
+}
+
+func generateImplCodec() string {
+	return mustExecute(implCodecTemplate, GoKinds)
+}
+
+var implCodecTemplate = template.Must(template.New("").Parse(`
+// +build !purego,!appengine
+
+{{- define "Size" -}}
+{{- if eq .WireType "Varint" -}}
+protowire.SizeVarint({{.FromGoValue}})
+{{- else if eq .WireType "Bytes" -}}
+protowire.SizeBytes(len({{.FromGoValue}}))
+{{- else -}}
+protowire.Size{{.WireType}}()
+{{- end -}}
+{{- end -}}
+
+{{- define "Append" -}}
+{{- if eq .WireType "Bytes" -}}
+b = protowire.AppendVarint(b, uint64(len(v)))
+b = append(b, v...)
+{{- else -}}
+b = protowire.Append{{.WireType}}(b, {{.FromGoValue}})
+{{- end -}}
+{{- end -}}
+
+{{- range .}}
+
+// size{{.Name}} returns the size of wire encoding a {{.GoType}} pointer as a {{.Name}}.
+func size{{.Name}}(p pointer, tagsize int, _ marshalOptions) (size int) {
+	{{if not (eq .WireType "Fixed32" "Fixed64") -}}
+	v := *p.{{.Accessor}}()
+	{{end -}}
+	return tagsize + {{template "Size" .}}
+}
+
+// append{{.Name}} wire encodes a {{.GoType}} pointer as a {{.Name}}.
+func append{{.Name}}(b []byte, p pointer, wiretag uint64, _ marshalOptions) ([]byte, error) {
+	v := *p.{{.Accessor}}()
+	b = protowire.AppendVarint(b, wiretag)
+	{{template "Append" .}}
+	return b, nil
+}
+
+// consume{{.Name}} wire decodes a {{.GoType}} pointer as a {{.Name}}.
+func consume{{.Name}}(b []byte, p pointer, wtyp protowire.Type, opts unmarshalOptions) (n int, err error) {
+	if wtyp != {{.WireType.Expr}} {
+		return 0, errUnknown
+	}
+	v, n := protowire.Consume{{.WireType}}(b)
+	if n < 0 {
+		return 0, protowire.ParseError(n)
+	}
+	*p.{{.Accessor}}() = {{.ToGoValue}}
+	return n, nil
+}
+
+var coder{{.Name}} = pointerCoderFuncs{
+	size:      size{{.Name}},
+	marshal:   append{{.Name}},
+	unmarshal: consume{{.Name}},
+}
+
+// size{{.Name}}NoZero returns the size of wire encoding a {{.GoType}} pointer as a {{.Name}}.
+// The zero value is not encoded.
+func size{{.Name}}NoZero(p pointer, tagsize int, _ marshalOptions) (size int) {
+	v := *p.{{.Accessor}}()
+	if {{.IsZero}} {
+		return 0
+	}
+	return tagsize + {{template "Size" .}}
+}
+
+// append{{.Name}}NoZero wire encodes a {{.GoType}} pointer as a {{.Name}}.
+// The zero value is not encoded.
+func append{{.Name}}NoZero(b []byte, p pointer, wiretag uint64, _ marshalOptions) ([]byte, error) {
+	v := *p.{{.Accessor}}()
+	if {{.IsZero}} {
+		return b, nil
+	}
+	b = protowire.AppendVarint(b, wiretag)
+	{{template "Append" .}}
+	return b, nil
+}
+
+var coder{{.Name}}NoZero = pointerCoderFuncs{
+	size:      size{{.Name}}NoZero,
+	marshal:   append{{.Name}}NoZero,
+	unmarshal: consume{{.Name}},
+}
+
+{{- if eq .Name "Bytes"}}
+
+// sizeBytesNoNil returns the size of wire encoding a []byte pointer as a Bytes.
+// A nil slice is not encoded.
+func sizeBytesNoNil(p pointer, tagsize int, _ marshalOptions) (size int) {
+	v := *p.Bytes()
+	if v == nil {
+		return 0
+	}
+	return tagsize + {{template "Size" .}}
+}
+
+// appendBytesNoNil wire encodes a []byte pointer as a Bytes.
+// A nil slice is not encoded.
+func appendBytesNoNil(b []byte, p pointer, wiretag uint64, _ marshalOptions) ([]byte, error) {
+	v := *p.Bytes()
+	if v == nil {
+		return b, nil
+	}
+	b = protowire.AppendVarint(b, wiretag)
+	{{template "Append" .}}
+	return b, nil
+}
+
+var coderBytesNoNil = pointerCoderFuncs{
+	size:      sizeBytesNoNil,
+	marshal:   appendBytesNoNil,
+	unmarshal: consumeBytes,
+}
+{{- else}}
+
+// size{{.Name}}Ptr returns the size of wire encoding a *{{.GoType}} pointer as a {{.Name}}.
+// A nil pointer is not encoded.
+func size{{.Name}}Ptr(p pointer, tagsize int, _ marshalOptions) (size int) {
+	vp := *p.{{.Accessor}}Ptr()
+	if vp == nil {
+		return 0
+	}
+	{{if not (eq .WireType "Fixed32" "Fixed64") -}}
+	v := *vp
+	{{end -}}
+	return tagsize + {{template "Size" .}}
+}
+
+// append{{.Name}}Ptr wire encodes a *{{.GoType}} pointer as a {{.Name}}.
+// A nil pointer is not encoded.
+func append{{.Name}}Ptr(b []byte, p pointer, wiretag uint64, _ marshalOptions) ([]byte, error) {
+	vp := *p.{{.Accessor}}Ptr()
+	if vp == nil {
+		return b, nil
+	}
+	v := *vp
+	b = protowire.AppendVarint(b, wiretag)
+	{{template "Append" .}}
+	return b, nil
+}
+
+// consume{{.Name}}Ptr wire decodes a *{{.GoType}} pointer as a {{.Name}}.
+func consume{{.Name}}Ptr(b []byte, p pointer, wtyp protowire.Type, opts unmarshalOptions) (n int, err error) {
+	if wtyp != {{.WireType.Expr}} {
+		return 0, errUnknown
+	}
+	v, n := protowire.Consume{{.WireType}}(b)
+	if n < 0 {
+		return 0, protowire.ParseError(n)
+	}
+	vp := p.{{.Accessor}}Ptr()
+	if *vp == nil {
+		*vp = new({{.GoType}})
+	}
+	**vp = {{.ToGoValue}}
+	return n, nil
+}
+
+var coder{{.Name}}Ptr = pointerCoderFuncs{
+	size:      size{{.Name}}Ptr,
+	marshal:   append{{.Name}}Ptr,
+	unmarshal: consume{{.Name}}Ptr,
+}
+{{- end}}
+
+// size{{.Name}}Slice returns the size of wire encoding a []{{.GoType}} pointer as a repeated {{.Name}}.
+func size{{.Name}}Slice(p pointer, tagsize int, _ marshalOptions) (size int) {
+	s := *p.{{.Accessor}}Slice()
+	{{- if eq .WireType "Fixed32" "Fixed64"}}
+	size = len(s) * (tagsize + protowire.Size{{.WireType}}())
+	{{- else}}
+	for _, v := range s {
+		size += tagsize + {{template "Size" .}}
+	}
+	{{- end}}
+	return size
+}
+
+// append{{.Name}}Slice encodes a []{{.GoType}} pointer as a repeated {{.Name}}.
+func append{{.Name}}Slice(b []byte, p pointer, wiretag uint64, _ marshalOptions) ([]byte, error) {
+	s := *p.{{.Accessor}}Slice()
+	for _, v := range s {
+		b = protowire.AppendVarint(b, wiretag)
+		{{template "Append" .}}
+	}
+	return b, nil
+}
+
+// consume{{.Name}}Slice wire decodes a []{{.GoType}} pointer as a repeated {{.Name}}.
+func consume{{.Name}}Slice(b []byte, p pointer, wtyp protowire.Type, opts unmarshalOptions) (n int, err error) {
+	sp := p.{{.Accessor}}Slice()
+	{{- if .WireType.Packable}}
+	if wtyp == protowire.BytesType {
+		s := *sp
+		b, n = protowire.ConsumeBytes(b)
+		if n < 0 {
+			return 0, protowire.ParseError(n)
+		}
+		for len(b) > 0 {
+			v, n := protowire.Consume{{.WireType}}(b)
+			if n < 0 {
+				return 0, protowire.ParseError(n)
+			}
+			s = append(s, {{.ToGoValue}})
+			b = b[n:]
+		}
+		*sp = s
+		return n, nil
+	}
+	{{- end}}
+	if wtyp != {{.WireType.Expr}} {
+		return 0, errUnknown
+	}
+	v, n := protowire.Consume{{.WireType}}(b)
+	if n < 0 {
+		return 0, protowire.ParseError(n)
+	}
+	*sp = append(*sp, {{.ToGoValue}})
+	return n, nil
+}
+
+var coder{{.Name}}Slice = pointerCoderFuncs{
+	size:      size{{.Name}}Slice,
+	marshal:   append{{.Name}}Slice,
+	unmarshal: consume{{.Name}}Slice,
+}
+
+{{- if .WireType.Packable}}
+
+// size{{.Name}}PackedSlice returns the size of wire encoding a []{{.GoType}} pointer as a packed repeated {{.Name}}.
+func size{{.Name}}PackedSlice(p pointer, tagsize int, _ marshalOptions) (size int) {
+	s := *p.{{.Accessor}}Slice()
+	if len(s) == 0 {
+		return 0
+	}
+	{{- if eq .WireType "Fixed32" "Fixed64"}}
+	n := len(s) * protowire.Size{{.WireType}}()
+	{{- else}}
+	n := 0
+	for _, v := range s {
+		n += {{template "Size" .}}
+	}
+	{{- end}}
+	return tagsize + protowire.SizeBytes(n)
+}
+
+// append{{.Name}}PackedSlice encodes a []{{.GoType}} pointer as a packed repeated {{.Name}}.
+func append{{.Name}}PackedSlice(b []byte, p pointer, wiretag uint64, _ marshalOptions) ([]byte, error) {
+	s := *p.{{.Accessor}}Slice()
+	if len(s) == 0 {
+		return b, nil
+	}
+	b = protowire.AppendVarint(b, wiretag)
+	{{- if eq .WireType "Fixed32" "Fixed64"}}
+	n := len(s) * protowire.Size{{.WireType}}()
+	{{- else}}
+	n := 0
+	for _, v := range s {
+		n += {{template "Size" .}}
+	}
+	{{- end}}
+	b = protowire.AppendVarint(b, uint64(n))
+	for _, v := range s {
+		{{template "Append" .}}
+	}
+	return b, nil
+}
+
+var coder{{.Name}}PackedSlice = pointerCoderFuncs{
+	size:      size{{.Name}}PackedSlice,
+	marshal:   append{{.Name}}PackedSlice,
+	unmarshal: consume{{.Name}}Slice,
+}
+{{- end}}
+{{- end}}
+
+// scalarCoders maps each scalar kind to the coder functions for the
+// Go representations of that kind.
+var scalarCoders = map[protoreflect.Kind]scalarCoderFuncs{
+{{- range .}}
+	protoreflect.{{.Name}}Kind: {
+		goType: reflect.TypeOf((*{{.GoType}})(nil)).Elem(),
+		value: coder{{.Name}},
+		noZero: coder{{.Name}}NoZero,
+		{{- if eq .Name "Bytes"}}
+		ptr: coderBytesNoNil,
+		{{- else}}
+		ptr: coder{{.Name}}Ptr,
+		{{- end}}
+		slice: coder{{.Name}}Slice,
+		{{- if .WireType.Packable}}
+		packedSlice: coder{{.Name}}PackedSlice,
+		{{- end}}
+	},
+{{- end}}
+}
+`))
@@ -39,6 +39,7 @@ func main() {
 
 	chdirRoot()
 	writeSource("internal/fileinit/desc_list_gen.go", generateFileinitDescList())
+	writeSource("internal/impl/codec_gen.go", generateImplCodec())
 	writeSource("internal/prototype/protofile_list_gen.go", generateListTypes())
 	writeSource("proto/decode_gen.go", generateProtoDecode())
 	writeSource("proto/encode_gen.go", generateProtoEncode())
@@ -311,9 +312,11 @@ func writeSource(file, src string) {
 	for _, pkg := range []string{
 		"fmt",
 		"math",
+		"reflect",
+		"strconv",
 		"sync",
 		"",
-		"github.com/golang/protobuf/v2/internal/encoding/wire",
+		"github.com/golang/protobuf/v2/encoding/protowire",
 		"github.com/golang/protobuf/v2/internal/errors",
 		"github.com/golang/protobuf/v2/internal/pragma",
 		"github.com/golang/protobuf/v2/internal/typefmt",
@@ -326,6 +329,13 @@ func writeSource(file, src string) {
 		}
 	}
 
+	// Hoist any build constraint above the package clause.
+	var buildTag string
+	if src = strings.TrimLeft(src, "\n"); strings.HasPrefix(src, "// +build ") {
+		i := strings.Index(src, "\n")
+		buildTag, src = src[:i]+"\n\n", src[i+1:]
+	}
+
 	s := strings.Join([]string{
 		"// Copyright 2018 The Go Authors. All rights reserved.",
 		"// Use of this source code is governed by a BSD-style.",
@@ -333,7 +343,7 @@ func writeSource(file, src string) {
 		"",
 		"// Code generated by generate-types. DO NOT EDIT.",
 		"",
-		"package " + path.Base(path.Dir(path.Join("proto", file))),
+		buildTag + "package " + path.Base(path.Dir(path.Join("proto", file))),
 		"",
 		"import (" + strings.Join(imports, "\n") + ")",
 		"",
@@ -341,6 +351,8 @@ func writeSource(file, src string) {
 	}, "\n")
 	b, err := format.Source([]byte(s))
 	check(err)
+	// Newer versions of gofmt synthesize a //go:build line; keep only +build.
+	b = regexp.MustCompile(`(?m)^//go:build .*\n`).ReplaceAll(b, nil)
 
 	absFile := filepath.Join(repoRoot, file)
 	if run {
@@ -18,9 +18,9 @@ const (
 
 func (w WireType) Expr() Expr {
 	if w == WireGroup {
-		return "wire.StartGroupType"
+		return "protowire.StartGroupType"
 	}
-	return "wire." + Expr(w) + "Type"
+	return "protowire." + Expr(w) + "Type"
 }
 
 func (w WireType) Packable() bool {
@@ -42,8 +42,8 @@ var ProtoKinds = []ProtoKind{
 	{
 		Name:      "Bool",
 		WireType:  WireVarint,
-		ToValue:   "wire.DecodeBool(v)",
-		FromValue: "wire.EncodeBool(v.Bool())",
+		ToValue:   "protowire.DecodeBool(v)",
+		FromValue: "protowire.EncodeBool(v.Bool())",
 	},
 	{
 		Name:      "Enum",
@@ -60,8 +60,8 @@ var ProtoKinds = []ProtoKind{
 	{
 		Name:      "Sint32",
 		WireType:  WireVarint,
-		ToValue:   "int32(wire.DecodeZigZag(v & math.MaxUint32))",
-		FromValue: "wire.EncodeZigZag(int64(int32(v.Int())))",
+		ToValue:   "int32(protowire.DecodeZigZag(v & math.MaxUint32))",
+		FromValue: "protowire.EncodeZigZag(int64(int32(v.Int())))",
 	},
 	{
 		Name:      "Uint32",
@@ -78,8 +78,8 @@ var ProtoKinds = []ProtoKind{
 	{
 		Name:      "Sint64",
 		WireType:  WireVarint,
-		ToValue:   "wire.DecodeZigZag(v)",
-		FromValue: "wire.EncodeZigZag(v.Int())",
+		ToValue:   "protowire.DecodeZigZag(v)",
+		FromValue: "protowire.EncodeZigZag(v.Int())",
 	},
 	{
 		Name:      "Uint64",
@@ -126,13 +126,13 @@ var ProtoKinds = []ProtoKind{
 	{
 		Name:      "String",
 		WireType:  WireBytes,
-		ToValue:   "string(v)",
+		ToValue:   "o.toString(v)",
 		FromValue: "[]byte(v.String())",
 	},
 	{
 		Name:      "Bytes",
 		WireType:  WireBytes,
-		ToValue:   "append(([]byte)(nil), v...)",
+		ToValue:   "o.toBytes(v)",
 		FromValue: "v.Bytes()",
 	},
 	{
@@ -157,7 +157,7 @@ var protoDecodeTemplate = template.Must(template.New("").Parse(`
 // unmarshalScalar decodes a value of the given kind.
 //
 // Message values are decoded into a []byte which aliases the input data.
-func (o UnmarshalOptions) unmarshalScalar(b []byte, wtyp wire.Type, num wire.Number, kind protoreflect.Kind) (val protoreflect.Value, n int, err error) {
+func (o UnmarshalOptions) unmarshalScalar(b []byte, wtyp protowire.Type, num protowire.Number, kind protoreflect.Kind) (val protoreflect.Value, n int, err error) {
 	switch kind {
 	{{- range .}}
 	case {{.Expr}}:
@@ -165,12 +165,12 @@ func (o UnmarshalOptions) unmarshalScalar(b []byte, wtyp wire.Type, num wire.Num
 			return val, 0, errUnknown
 		}
 		{{if (eq .WireType "Group") -}}
-		v, n := wire.ConsumeGroup(num, b)
+		v, n := protowire.ConsumeGroup(num, b)
 		{{- else -}}
-		v, n := wire.Consume{{.WireType}}(b)
+		v, n := protowire.Consume{{.WireType}}(b)
 		{{- end}}
 		if n < 0 {
-			return val, 0, wire.ParseError(n)
+			return val, 0, protowire.ParseError(n)
 		}
 		return protoreflect.ValueOf({{.ToValue}}), n, nil
 	{{- end}}
@@ -179,21 +179,21 @@ func (o UnmarshalOptions) unmarshalScalar(b []byte, wtyp wire.Type, num wire.Num
 	}
 }
 
-func (o UnmarshalOptions) unmarshalList(b []byte, wtyp wire.Type, num wire.Number, list protoreflect.List, kind protoreflect.Kind) (n int, err error) {
+func (o UnmarshalOptions) unmarshalList(b []byte, wtyp protowire.Type, num protowire.Number, list protoreflect.List, kind protoreflect.Kind) (n int, err error) {
 	var nerr errors.NonFatal
 	switch kind {
 	{{- range .}}
 	case {{.Expr}}:
 		{{- if .WireType.Packable}}
-		if wtyp == wire.BytesType {
-			buf, n := wire.ConsumeBytes(b)
+		if wtyp == protowire.BytesType {
+			buf, n := protowire.ConsumeBytes(b)
 			if n < 0 {
-				return 0, wire.ParseError(n)
+				return 0, protowire.ParseError(n)
 			}
 			for len(buf) > 0 {
-				v, n := wire.Consume{{.WireType}}(buf)
+				v, n := protowire.Consume{{.WireType}}(buf)
 				if n < 0 {
-					return 0, wire.ParseError(n)
+					return 0, protowire.ParseError(n)
 				}
 				buf = buf[n:]
 				list.Append(protoreflect.ValueOf({{.ToValue}}))
@@ -205,16 +205,20 @@ func (o UnmarshalOptions) unmarshalList(b []byte, wtyp wire.Type, num wire.Numbe
 			return 0, errUnknown
 		}
 		{{if (eq .WireType "Group") -}}
-		v, n := wire.ConsumeGroup(num, b)
+		v, n := protowire.ConsumeGroup(num, b)
 		{{- else -}}
-		v, n := wire.Consume{{.WireType}}(b)
+		v, n := protowire.Consume{{.WireType}}(b)
 		{{- end}}
 		if n < 0 {
-			return 0, wire.ParseError(n)
+			return 0, protowire.ParseError(n)
 		}
 		{{if or (eq .Name "Message") (eq .Name "Group") -}}
 		m := list.NewMessage()
-		if err := o.unmarshalMessage(v, m); !nerr.Merge(err) {
+		err := o.unmarshalMessage(v, m)
+		{{- if (eq .Name "Message")}}
+		err = errors.AddOffset(err, n-len(v))
+		{{- end}}
+		if err := errors.AddIndex(err, strconv.Itoa(list.Len())); !nerr.Merge(err) {
 			return 0, err
 		}
 		list.Append(protoreflect.ValueOf(m))
@@ -234,13 +238,13 @@ func generateProtoEncode() string {
 }
 
 var protoEncodeTemplate = template.Must(template.New("").Parse(`
-var wireTypes = map[protoreflect.Kind]wire.Type{
+var wireTypes = map[protoreflect.Kind]protowire.Type{
 {{- range .}}
 	{{.Expr}}: {{.WireType.Expr}},
 {{- end}}
 }
 
-func (o MarshalOptions) marshalSingular(b []byte, num wire.Number, kind protoreflect.Kind, v protoreflect.Value) ([]byte, error) {
+func (o MarshalOptions) marshalSingular(b []byte, num protowire.Number, kind protoreflect.Kind, v protoreflect.Value) ([]byte, error) {
 	var nerr errors.NonFatal
 	switch kind {
 	{{- range .}}
@@ -260,9 +264,9 @@ func (o MarshalOptions) marshalSingular(b []byte, num wire.Number, kind protoref
 		if !nerr.Merge(err) {
 			return b, err
 		}
-		b = wire.AppendVarint(b, wire.EncodeTag(num, wire.EndGroupType))
+		b = protowire.AppendVarint(b, protowire.EncodeTag(num, protowire.EndGroupType))
 		{{- else -}}
-		b = wire.Append{{.WireType}}(b, {{.FromValue}})
+		b = protowire.Append{{.WireType}}(b, {{.FromValue}})
 		{{- end}}
 	{{- end}}
 	default:
@@ -277,20 +281,20 @@ func generateProtoSize() string {
 }
 
 var protoSizeTemplate = template.Must(template.New("").Parse(`
-func sizeSingular(num wire.Number, kind protoreflect.Kind, v protoreflect.Value) int {
+func (o MarshalOptions) sizeSingular(num protowire.Number, kind protoreflect.Kind, v protoreflect.Value) int {
 	switch kind {
 	{{- range .}}
 	case {{.Expr}}:
 		{{if (eq .Name "Message") -}}
-		return wire.SizeBytes(sizeMessage(v.Message()))
+		return protowire.SizeBytes(o.sizeMessage(v.Message()))
 		{{- else if or (eq .WireType "Fixed32") (eq .WireType "Fixed64") -}}
-		return wire.Size{{.WireType}}()
+		return protowire.Size{{.WireType}}()
 		{{- else if (eq .WireType "Bytes") -}}
-		return wire.Size{{.WireType}}(len({{.FromValue}}))
+		return protowire.Size{{.WireType}}(len({{.FromValue}}))
 		{{- else if (eq .WireType "Group") -}}
-		return wire.Size{{.WireType}}(num, sizeMessage(v.Message()))
+		return protowire.Size{{.WireType}}(num, o.sizeMessage(v.Message()))
 		{{- else -}}
-		return wire.Size{{.WireType}}({{.FromValue}})
+		return protowire.Size{{.WireType}}({{.FromValue}})
 		{{- end}}
 	{{- end}}
 	default:
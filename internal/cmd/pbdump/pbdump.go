@@ -17,8 +17,8 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/golang/protobuf/v2/encoding/protowire"
 	"github.com/golang/protobuf/v2/internal/encoding/pack"
-	"github.com/golang/protobuf/v2/internal/encoding/wire"
 	"github.com/golang/protobuf/v2/internal/prototype"
 	"github.com/golang/protobuf/v2/internal/scalar"
 	"github.com/golang/protobuf/v2/reflect/protoreflect"
@@ -141,7 +141,7 @@ func main() {
 
 // fields is a tree of fields, keyed by a field number.
 // Fields representing messages or groups have sub-fields.
-type fields map[wire.Number]*field
+type fields map[protowire.Number]*field
 type field struct {
 	kind protoreflect.Kind
 	sub  fields // only for MessageKind or GroupKind
@@ -172,8 +172,8 @@ func (fs fields) set(prefix, s string, k protoreflect.Kind) error {
 	}
 	prefix = strings.TrimPrefix(prefix+"."+s[:i], ".")
 	n, _ := strconv.ParseInt(s[:i], 10, 32)
-	num := wire.Number(n)
-	if num < wire.MinValidNumber || wire.MaxValidNumber < num {
+	num := protowire.Number(n)
+	if num < protowire.MinValidNumber || protowire.MaxValidNumber < num {
 		return fmt.Errorf("invalid field: %v", prefix)
 	}
 	s = strings.TrimPrefix(s[i:], ".")
@@ -241,7 +241,7 @@ func (fs fields) messageDescriptor(name protoreflect.FullName) prototype.Message
 	return m
 }
 
-func (fs fields) sortedNums() (ns []wire.Number) {
+func (fs fields) sortedNums() (ns []protowire.Number) {
 	for n := range fs {
 		ns = append(ns, n)
 	}
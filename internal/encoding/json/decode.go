@@ -232,7 +232,15 @@ func (d *Decoder) position() (int, int) {
 func (d *Decoder) newSyntaxError(f string, x ...interface{}) error {
 	e := errors.New(f, x...)
 	line, column := d.position()
-	return errors.New("syntax error (line %d:%d): %v", line, column, e)
+	return &errors.ParseError{
+		Offset: d.offset(),
+		Err:    errors.New("syntax error (line %d:%d): %v", line, column, e),
+	}
+}
+
+// offset returns the number of bytes of input consumed.
+func (d *Decoder) offset() int {
+	return len(d.orig) - len(d.in)
 }
 
 // matchWithDelim matches r with the input b and verifies that the match
@@ -294,6 +302,7 @@ func (d *Decoder) newValue(input []byte, typ Type) Value {
 	line, column := d.position()
 	return Value{
 		input:  input,
+		offset: d.offset(),
 		line:   line,
 		column: column,
 		typ:    typ,
@@ -305,6 +314,7 @@ func (d *Decoder) newBoolValue(input []byte, b bool) Value {
 	line, column := d.position()
 	return Value{
 		input:  input,
+		offset: d.offset(),
 		line:   line,
 		column: column,
 		typ:    Bool,
@@ -317,6 +327,7 @@ func (d *Decoder) newStringValue(input []byte, s string) Value {
 	line, column := d.position()
 	return Value{
 		input:  input,
+		offset: d.offset(),
 		line:   line,
 		column: column,
 		typ:    String,
@@ -339,6 +350,7 @@ func (d *Decoder) Clone() *Decoder {
 // additional data.
 type Value struct {
 	input  []byte
+	offset int
 	line   int
 	column int
 	typ    Type
@@ -348,7 +360,10 @@ type Value struct {
 
 func (v Value) newError(f string, x ...interface{}) error {
 	e := errors.New(f, x...)
-	return errors.New("error (line %d:%d): %v", v.line, v.column, e)
+	return &errors.ParseError{
+		Offset: v.offset,
+		Err:    errors.New("error (line %d:%d): %v", v.line, v.column, e),
+	}
 }
 
 // Type returns the JSON type.
@@ -361,6 +376,11 @@ func (v Value) Position() (int, int) {
 	return v.line, v.column
 }
 
+// Offset returns the byte offset of the value in the input.
+func (v Value) Offset() int {
+	return v.offset
+}
+
 // Bool returns the bool value if token is Bool, else it will return an error.
 func (v Value) Bool() (bool, error) {
 	if v.typ != Bool {
@@ -70,7 +70,7 @@ func (d *Decoder) parseString(in []byte) (string, int, error) {
 	for len(in) > 0 {
 		switch r, n := utf8.DecodeRune(in); {
 		case r == utf8.RuneError && n == 1:
-			nerr.AppendInvalidUTF8("")
+			nerr.Merge(&errors.InvalidUTF8Error{Offset: len(d.orig) - len(in)})
 			in, out = in[1:], append(out, in[0]) // preserve invalid byte
 		case r < ' ':
 			return "", 0, d.newSyntaxError("invalid character %q in string", r)
@@ -23,7 +23,7 @@ func newSyntaxError(f string, x ...interface{}) error {
 // Unmarshal parses b as the proto text format.
 // It returns a Value, which is always of the Message type.
 func Unmarshal(b []byte) (Value, error) {
-	p := decoder{in: b}
+	p := decoder{orig: b, in: b}
 	p.consume(0) // trim leading spaces or comments
 	v, err := p.unmarshalMessage(false)
 	if !p.nerr.Merge(err) {
@@ -34,18 +34,25 @@ func Unmarshal(b []byte) (Value, error) {
 				b = b[i+1:]
 			}
 			column := utf8.RuneCount(b) + 1 // ignore multi-rune characters
-			err = errors.New("syntax error (line %d:%d): %v", line, column, e.error)
+			err = &errors.ParseError{
+				Offset: len(p.orig) - len(p.in),
+				Err:    errors.New("syntax error (line %d:%d): %v", line, column, e.error),
+			}
 		}
 		return Value{}, err
 	}
 	if len(p.in) > 0 {
-		return Value{}, errors.New("%d bytes of unconsumed input", len(p.in))
+		return Value{}, &errors.ParseError{
+			Offset: len(p.orig) - len(p.in),
+			Err:    errors.New("%d bytes of unconsumed input", len(p.in)),
+		}
 	}
 	return v, p.nerr.E
 }
 
 type decoder struct {
 	nerr errors.NonFatal
+	orig []byte // used in reporting offsets
 	in   []byte
 }
 
@@ -123,6 +130,13 @@ var urlRegexp = regexp.MustCompile(`^[-_a-zA-Z0-9]+([./][-_a-zA-Z0-9]+)*`)
 
 // unmarshalKey parses the key, which may be a Name, String, or Uint.
 func (p *decoder) unmarshalKey() (v Value, err error) {
+	pos := len(p.orig) - len(p.in)
+	v, err = p.unmarshalKeyValue()
+	v.pos = pos
+	return v, err
+}
+
+func (p *decoder) unmarshalKeyValue() (v Value, err error) {
 	if p.tryConsumeChar('[') {
 		if len(p.in) == 0 {
 			return Value{}, io.ErrUnexpectedEOF
@@ -152,24 +166,28 @@ func (p *decoder) unmarshalKey() (v Value, err error) {
 	return p.unmarshalName()
 }
 
-func (p *decoder) unmarshalValue() (Value, error) {
+func (p *decoder) unmarshalValue() (v Value, err error) {
 	if len(p.in) == 0 {
 		return Value{}, io.ErrUnexpectedEOF
 	}
+	pos := len(p.orig) - len(p.in)
 	switch p.in[0] {
 	case '"', '\'':
-		return p.unmarshalStrings()
+		v, err = p.unmarshalStrings()
 	case '[':
-		return p.unmarshalList()
+		v, err = p.unmarshalList()
 	case '{', '<':
-		return p.unmarshalMessage(true)
+		v, err = p.unmarshalMessage(true)
 	default:
 		n := matchWithDelim(nameRegexp, p.in) // zero if no match
 		if n > 0 && literals[string(p.in[:n])] == nil {
-			return p.unmarshalName()
+			v, err = p.unmarshalName()
+		} else {
+			v, err = p.unmarshalNumber()
 		}
-		return p.unmarshalNumber()
 	}
+	v.pos = pos
+	return v, err
 }
 
 // This expression matches all valid proto identifiers.
@@ -93,6 +93,7 @@ func (t Type) String() string {
 type Value struct {
 	typ Type
 	raw []byte     // raw bytes of the serialized data
+	pos int        // offset of the raw bytes in the input to Unmarshal
 	str string     // only for String or Name
 	num uint64     // only for Bool, Int, Uint, Float32, or Float64
 	arr []Value    // only for List
@@ -320,6 +321,12 @@ func (v Value) Message() [][2]Value {
 	return v.obj
 }
 
+// Offset returns the byte offset of the value in the input given to Unmarshal.
+// It is zero for values not produced by Unmarshal.
+func (v Value) Offset() int {
+	return v.pos
+}
+
 // Raw returns the raw representation of the value.
 // The returned value may alias the input given to Unmarshal.
 func (v Value) Raw() []byte {
@@ -11,17 +11,9 @@ import (
 	"strings"
 )
 
-// TODO: This package currently only provides functionality for constructing
-// non-fatal errors. However, it does not currently provide functionality
-// to test for a specific kind of non-fatal error, which is necessary
-// for the end user.
-//
-// When that functionality is added, we need to think carefully about whether
-// a user only cares that some kind of non-fatal error was present or whether
-// all of the errors are of the same kind of non-fatal error.
-
 // NonFatalErrors is a list of non-fatal errors where each error
 // must either be a RequiredNotSet error or InvalidUTF8 error.
+// Use errors.As in the standard library to test for a specific kind.
 // The list must not be empty.
 type NonFatalErrors []error
 
@@ -46,6 +38,7 @@ func (es NonFatalErrors) Error() string {
 // an individual error where IsRequiredNotSet or IsInvalidUTF8 reports true.
 //
 // Typical usage pattern:
+//
 //	var nerr errors.NonFatal
 //	...
 //	if err := MyFunction(); !nerr.Merge(err) {
@@ -78,12 +71,12 @@ func (nf *NonFatal) Merge(err error) (ok bool) {
 
 // AppendRequiredNotSet appends a RequiredNotSet error.
 func (nf *NonFatal) AppendRequiredNotSet(field string) {
-	nf.append(requiredNotSetError(field))
+	nf.append(&RequiredNotSetError{Path: field})
 }
 
 // AppendInvalidUTF8 appends an InvalidUTF8 error.
 func (nf *NonFatal) AppendInvalidUTF8(field string) {
-	nf.append(invalidUTF8Error(field))
+	nf.append(&InvalidUTF8Error{Path: field, Offset: -1})
 }
 
 func (nf *NonFatal) append(errs ...error) {
@@ -92,32 +85,197 @@ func (nf *NonFatal) append(errs ...error) {
 	nf.E = es
 }
 
-type requiredNotSetError string
+// Unwrap returns the individual errors so that the errors.Is and errors.As
+// functions in the standard library may match any of them.
+func (es NonFatalErrors) Unwrap() []error { return es }
+
+// RequiredNotSetError is a non-fatal error reporting that a required field
+// is not set.
+type RequiredNotSetError struct {
+	// Path is the path to the unset field (e.g., "a.b[3].c"),
+	// or empty if unknown.
+	Path string
+}
 
-func (e requiredNotSetError) Error() string {
-	if e == "" {
+func (e *RequiredNotSetError) Error() string {
+	if e.Path == "" {
 		return "required field not set"
 	}
-	return string("required field " + e + " not set")
+	return "required field " + e.Path + " not set"
 }
-func (requiredNotSetError) RequiredNotSet() bool { return true }
+func (*RequiredNotSetError) RequiredNotSet() bool       { return true }
+func (e *RequiredNotSetError) context() (*string, *int) { return &e.Path, nil }
 
-type invalidUTF8Error string
+// InvalidUTF8Error is a non-fatal error reporting that a string field
+// contains invalid UTF-8.
+type InvalidUTF8Error struct {
+	// Path is the path to the field (e.g., "a.b[3].c"), or empty if unknown.
+	Path string
+	// Offset is the byte offset of the invalid data in the input,
+	// or -1 if unknown.
+	Offset int
+}
 
-func (e invalidUTF8Error) Error() string {
-	if e == "" {
+func (e *InvalidUTF8Error) Error() string {
+	if e.Path == "" {
 		return "invalid UTF-8 detected"
 	}
-	return string("field " + e + " contains invalid UTF-8")
+	return "field " + e.Path + " contains invalid UTF-8"
+}
+func (*InvalidUTF8Error) InvalidUTF8() bool          { return true }
+func (e *InvalidUTF8Error) context() (*string, *int) { return &e.Path, &e.Offset }
+
+// ParseError reports that the input is malformed.
+type ParseError struct {
+	// Path is the path to the field being parsed (e.g., "a.b[3].c"),
+	// or empty if the error is not specific to a field.
+	Path string
+	// Offset is the byte offset in the input at which the error was
+	// detected, or -1 if unknown.
+	Offset int
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *ParseError) Error() string            { return "proto: " + e.message() }
+func (e *ParseError) message() string          { return withPath(e.Path, message(e.Err)) }
+func (e *ParseError) Unwrap() error            { return e.Err }
+func (e *ParseError) context() (*string, *int) { return &e.Path, &e.Offset }
+
+// UnknownEnumError reports that the input contains an unknown enum value name.
+type UnknownEnumError struct {
+	// Path is the path to the enum field (e.g., "a.b[3].c").
+	Path string
+	// Offset is the byte offset of the value in the input, or -1 if unknown.
+	Offset int
+	// Value is the unknown value as it appears in the input.
+	Value string
+}
+
+func (e *UnknownEnumError) Error() string            { return "proto: " + e.message() }
+func (e *UnknownEnumError) message() string          { return withPath(e.Path, "invalid enum value "+e.Value) }
+func (e *UnknownEnumError) context() (*string, *int) { return &e.Path, &e.Offset }
+
+// RecursionLimitError reports that the input contains messages or groups
+// nested more deeply than the recursion limit.
+// It matches ErrRecursionLimit when tested with errors.Is.
+type RecursionLimitError struct {
+	// Path is the path to the field which exceeded the limit (e.g., "a.b[3].c").
+	Path string
+	// Offset is the byte offset in the input at which the limit was
+	// exceeded, or -1 if unknown.
+	Offset int
+}
+
+func (e *RecursionLimitError) Error() string            { return "proto: " + e.message() }
+func (e *RecursionLimitError) message() string          { return withPath(e.Path, message(ErrRecursionLimit)) }
+func (e *RecursionLimitError) Is(target error) bool     { return target == ErrRecursionLimit }
+func (e *RecursionLimitError) context() (*string, *int) { return &e.Path, &e.Offset }
+
+// contextError is implemented by errors which carry a field path and offset.
+type contextError interface {
+	error
+	// context returns pointers to the path and the offset of the error.
+	// The offset is nil if the error does not have one.
+	context() (path *string, offset *int)
+}
+
+// AddField prepends the field name to the path of err, converting err to
+// a ParseError if it does not already carry a path.
+// Extension fields are named by their full name in parentheses.
+func AddField(err error, name string) error {
+	return annotate(err, -1, func(p *string, _ *int) {
+		switch {
+		case *p == "":
+			*p = name
+		case (*p)[0] == '[':
+			*p = name + *p
+		default:
+			*p = name + "." + *p
+		}
+	})
+}
+
+// AddIndex prepends the list index or map key to the path of err,
+// converting err to a ParseError if it does not already carry a path.
+func AddIndex(err error, key string) error {
+	return annotate(err, -1, func(p *string, _ *int) {
+		switch {
+		case *p == "", (*p)[0] == '[':
+			*p = "[" + key + "]" + *p
+		default:
+			*p = "[" + key + "]." + *p
+		}
+	})
+}
+
+// AddOffset adds n to the offset of err, converting err to a ParseError at
+// offset n if it does not already carry an offset. Offsets in errors from
+// the decoding of a nested message are relative to the start of that message,
+// and the caller adds the position of the nested message in its own input.
+func AddOffset(err error, n int) error {
+	return annotate(err, n, func(_ *string, o *int) {
+		if o != nil && *o >= 0 {
+			*o += n
+		}
+	})
+}
+
+// annotate calls fn with the context of err or, if err is a NonFatalErrors,
+// of each error in the list. Errors without context are converted to
+// a ParseError (or RecursionLimitError) at the given offset.
+func annotate(err error, offset int, fn func(path *string, offset *int)) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case contextError:
+		fn(e.context())
+		return e
+	case NonFatalErrors:
+		for _, e := range e {
+			if e, ok := e.(contextError); ok {
+				fn(e.context())
+			}
+		}
+		return e
+	}
+	if err == ErrRecursionLimit {
+		return &RecursionLimitError{Offset: offset}
+	}
+	return &ParseError{Offset: offset, Err: err}
+}
+
+// withPath prefixes the message s with the field path, if any.
+func withPath(path, s string) string {
+	if path == "" {
+		return s
+	}
+	return "field " + path + ": " + s
+}
+
+// message returns the text of err without the "proto: " prefix.
+func message(err error) string {
+	if e, ok := err.(interface{ message() string }); ok {
+		return e.message()
+	}
+	return err.Error()
 }
-func (invalidUTF8Error) InvalidUTF8() bool { return true }
+
+var (
+	// ErrRecursionLimit is returned when parsing input containing messages
+	// or groups nested more deeply than the recursion limit.
+	ErrRecursionLimit = New("exceeded maximum recursion depth")
+
+	// ErrMaxSize is returned when parsing input larger than the maximum size.
+	ErrMaxSize = New("input exceeds maximum size")
+)
 
 // New formats a string according to the format specifier and arguments and
 // returns an error that has a "proto" prefix.
 func New(f string, x ...interface{}) error {
 	for i := 0; i < len(x); i++ {
-		if e, ok := x[i].(*prefixError); ok {
-			x[i] = e.s // avoid "proto: " prefix when chaining
+		if e, ok := x[i].(interface{ message() string }); ok {
+			x[i] = e.message() // avoid "proto: " prefix when chaining
 		}
 	}
 	return &prefixError{s: fmt.Sprintf(f, x...)}
@@ -125,4 +283,5 @@ func New(f string, x ...interface{}) error {
 
 type prefixError struct{ s string }
 
-func (e *prefixError) Error() string { return "proto: " + e.s }
+func (e *prefixError) Error() string   { return "proto: " + e.s }
+func (e *prefixError) message() string { return e.s }
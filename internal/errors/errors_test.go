@@ -44,18 +44,18 @@ func TestNonFatal(t *testing.T) {
 			appendInvalidUTF8{"bar"},
 			merge{inErr: customInvalidUTF8Error{}, wantOk: true},
 			merge{inErr: NonFatalErrors{
-				requiredNotSetError("fizz"),
-				invalidUTF8Error("buzz"),
+				&RequiredNotSetError{Path: "fizz"},
+				&InvalidUTF8Error{Path: "buzz", Offset: -1},
 			}, wantOk: true},
 			merge{inErr: errors.New("fatal error")}, // not stored
 		},
 		wantErr: NonFatalErrors{
-			requiredNotSetError("foo"),
+			&RequiredNotSetError{Path: "foo"},
 			customRequiredNotSetError{},
-			invalidUTF8Error("bar"),
+			&InvalidUTF8Error{Path: "bar", Offset: -1},
 			customInvalidUTF8Error{},
-			requiredNotSetError("fizz"),
-			invalidUTF8Error("buzz"),
+			&RequiredNotSetError{Path: "fizz"},
+			&InvalidUTF8Error{Path: "buzz", Offset: -1},
 		},
 	}}
 
@@ -93,6 +93,49 @@ type customRequiredNotSetError struct{}
 func (customRequiredNotSetError) Error() string        { return "required field not set" }
 func (customRequiredNotSetError) RequiredNotSet() bool { return true }
 
+func TestAnnotate(t *testing.T) {
+	var nerr NonFatal
+	nerr.AppendRequiredNotSet("c")
+	err := AddField(AddIndex(AddField(nerr.E, "b"), "3"), "a")
+	var rerr *RequiredNotSetError
+	if !errors.As(err, &rerr) {
+		t.Fatalf("errors.As(%v, *RequiredNotSetError) = false, want true", err)
+	}
+	if got, want := rerr.Path, "a[3].b.c"; got != want {
+		t.Errorf("RequiredNotSetError.Path = %q, want %q", got, want)
+	}
+
+	err = AddField(AddOffset(AddIndex(AddOffset(New("bad"), 2), "k"), 10), "(x.y)")
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("errors.As(%v, *ParseError) = false, want true", err)
+	}
+	if got, want := perr.Path, "(x.y)[k]"; got != want {
+		t.Errorf("ParseError.Path = %q, want %q", got, want)
+	}
+	if got, want := perr.Offset, 12; got != want {
+		t.Errorf("ParseError.Offset = %v, want %v", got, want)
+	}
+	if got, want := err.Error(), "proto: field (x.y)[k]: bad"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	// Offsets are only added to errors from decoding.
+	err = AddOffset(AddField(New("bad"), "a"), 10)
+	if !errors.As(err, &perr) || perr.Offset != -1 {
+		t.Errorf("AddOffset(AddField(err)) = %#v, want offset -1", err)
+	}
+
+	err = AddField(AddOffset(ErrRecursionLimit, 5), "a")
+	var lerr *RecursionLimitError
+	if !errors.As(err, &lerr) || lerr.Path != "a" || lerr.Offset != 5 {
+		t.Errorf("AddField(AddOffset(ErrRecursionLimit)) = %#v, want RecursionLimitError{a, 5}", err)
+	}
+	if !errors.Is(err, ErrRecursionLimit) {
+		t.Errorf("errors.Is(%v, ErrRecursionLimit) = false, want true", err)
+	}
+}
+
 func TestNewPrefix(t *testing.T) {
 	e1 := New("abc")
 	got := e1.Error()
@@ -221,6 +221,7 @@ type (
 		imports fileImports
 		byName  map[pref.FullName]pref.Descriptor
 		options []byte
+		locs    pref.SourceLocations
 	}
 )
 
@@ -241,6 +242,7 @@ func (fd *fileDesc) Messages() pref.MessageDescriptors                { return &
 func (fd *fileDesc) Extensions() pref.ExtensionDescriptors            { return &fd.extensions }
 func (fd *fileDesc) Services() pref.ServiceDescriptors                { return &fd.services }
 func (fd *fileDesc) DescriptorByName(s pref.FullName) pref.Descriptor { return fd.lazyInit().byName[s] }
+func (fd *fileDesc) SourceLocations() pref.SourceLocations            { return fd.lazyInit().locs }
 func (fd *fileDesc) Format(s fmt.State, r rune)                       { pfmt.FormatDesc(s, r, fd) }
 func (fd *fileDesc) ProtoType(pref.FileDescriptor)                    {}
 func (fd *fileDesc) ProtoInternal(pragma.DoNotImplement)              {}
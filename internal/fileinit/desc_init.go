@@ -5,7 +5,7 @@
 package fileinit
 
 import (
-	wire "github.com/golang/protobuf/v2/internal/encoding/wire"
+	"github.com/golang/protobuf/v2/encoding/protowire"
 	fieldnum "github.com/golang/protobuf/v2/internal/fieldnum"
 	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
 )
@@ -82,11 +82,11 @@ func (fd *fileDesc) unmarshalSeed(b []byte) {
 	var posEnums, posMessages, posExtensions, posServices int
 	b0 := b
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		b = b[n:]
 		switch typ {
-		case wire.BytesType:
-			v, m := wire.ConsumeBytes(b)
+		case protowire.BytesType:
+			v, m := protowire.ConsumeBytes(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.FileDescriptorProto_Name:
@@ -128,7 +128,7 @@ func (fd *fileDesc) unmarshalSeed(b []byte) {
 			}
 			prevField = num
 		default:
-			m := wire.ConsumeFieldValue(num, typ, b)
+			m := protowire.ConsumeFieldValue(num, typ, b)
 			b = b[m:]
 			prevField = -1 // ignore known field numbers of unknown wire type
 		}
@@ -152,8 +152,8 @@ func (fd *fileDesc) unmarshalSeed(b []byte) {
 	if numEnums > 0 {
 		b := b0[posEnums:]
 		for i := range fd.enums.list {
-			_, n := wire.ConsumeVarint(b)
-			v, m := wire.ConsumeBytes(b[n:])
+			_, n := protowire.ConsumeVarint(b)
+			v, m := protowire.ConsumeBytes(b[n:])
 			fd.enums.list[i].unmarshalSeed(v, nb, fd, fd, i)
 			b = b[n+m:]
 		}
@@ -161,8 +161,8 @@ func (fd *fileDesc) unmarshalSeed(b []byte) {
 	if numMessages > 0 {
 		b := b0[posMessages:]
 		for i := range fd.messages.list {
-			_, n := wire.ConsumeVarint(b)
-			v, m := wire.ConsumeBytes(b[n:])
+			_, n := protowire.ConsumeVarint(b)
+			v, m := protowire.ConsumeBytes(b[n:])
 			fd.messages.list[i].unmarshalSeed(v, nb, fd, fd, i)
 			b = b[n+m:]
 		}
@@ -170,8 +170,8 @@ func (fd *fileDesc) unmarshalSeed(b []byte) {
 	if numExtensions > 0 {
 		b := b0[posExtensions:]
 		for i := range fd.extensions.list {
-			_, n := wire.ConsumeVarint(b)
-			v, m := wire.ConsumeBytes(b[n:])
+			_, n := protowire.ConsumeVarint(b)
+			v, m := protowire.ConsumeBytes(b[n:])
 			fd.extensions.list[i].unmarshalSeed(v, nb, fd, fd, i)
 			b = b[n+m:]
 		}
@@ -179,8 +179,8 @@ func (fd *fileDesc) unmarshalSeed(b []byte) {
 	if numServices > 0 {
 		b := b0[posServices:]
 		for i := range fd.services.list {
-			_, n := wire.ConsumeVarint(b)
-			v, m := wire.ConsumeBytes(b[n:])
+			_, n := protowire.ConsumeVarint(b)
+			v, m := protowire.ConsumeBytes(b[n:])
 			fd.services.list[i].unmarshalSeed(v, nb, fd, fd, i)
 			b = b[n+m:]
 		}
@@ -193,18 +193,18 @@ func (ed *enumDesc) unmarshalSeed(b []byte, nb *nameBuilder, pf *fileDesc, pd pr
 	ed.index = i
 
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		b = b[n:]
 		switch typ {
-		case wire.BytesType:
-			v, m := wire.ConsumeBytes(b)
+		case protowire.BytesType:
+			v, m := protowire.ConsumeBytes(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.EnumDescriptorProto_Name:
 				ed.fullName = nb.AppendFullName(pd.FullName(), v)
 			}
 		default:
-			m := wire.ConsumeFieldValue(num, typ, b)
+			m := protowire.ConsumeFieldValue(num, typ, b)
 			b = b[m:]
 		}
 	}
@@ -220,11 +220,11 @@ func (md *messageDesc) unmarshalSeed(b []byte, nb *nameBuilder, pf *fileDesc, pd
 	var posEnums, posMessages, posExtensions int
 	b0 := b
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		b = b[n:]
 		switch typ {
-		case wire.BytesType:
-			v, m := wire.ConsumeBytes(b)
+		case protowire.BytesType:
+			v, m := protowire.ConsumeBytes(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.DescriptorProto_Name:
@@ -256,7 +256,7 @@ func (md *messageDesc) unmarshalSeed(b []byte, nb *nameBuilder, pf *fileDesc, pd
 			}
 			prevField = num
 		default:
-			m := wire.ConsumeFieldValue(num, typ, b)
+			m := protowire.ConsumeFieldValue(num, typ, b)
 			b = b[m:]
 			prevField = -1 // ignore known field numbers of unknown wire type
 		}
@@ -277,8 +277,8 @@ func (md *messageDesc) unmarshalSeed(b []byte, nb *nameBuilder, pf *fileDesc, pd
 	if numEnums > 0 {
 		b := b0[posEnums:]
 		for i := range md.enums.list {
-			_, n := wire.ConsumeVarint(b)
-			v, m := wire.ConsumeBytes(b[n:])
+			_, n := protowire.ConsumeVarint(b)
+			v, m := protowire.ConsumeBytes(b[n:])
 			md.enums.list[i].unmarshalSeed(v, nb, pf, md.asDesc(), i)
 			b = b[n+m:]
 		}
@@ -286,8 +286,8 @@ func (md *messageDesc) unmarshalSeed(b []byte, nb *nameBuilder, pf *fileDesc, pd
 	if numMessages > 0 {
 		b := b0[posMessages:]
 		for i := range md.messages.list {
-			_, n := wire.ConsumeVarint(b)
-			v, m := wire.ConsumeBytes(b[n:])
+			_, n := protowire.ConsumeVarint(b)
+			v, m := protowire.ConsumeBytes(b[n:])
 			md.messages.list[i].unmarshalSeed(v, nb, pf, md.asDesc(), i)
 			b = b[n+m:]
 		}
@@ -295,8 +295,8 @@ func (md *messageDesc) unmarshalSeed(b []byte, nb *nameBuilder, pf *fileDesc, pd
 	if numExtensions > 0 {
 		b := b0[posExtensions:]
 		for i := range md.extensions.list {
-			_, n := wire.ConsumeVarint(b)
-			v, m := wire.ConsumeBytes(b[n:])
+			_, n := protowire.ConsumeVarint(b)
+			v, m := protowire.ConsumeBytes(b[n:])
 			md.extensions.list[i].unmarshalSeed(v, nb, pf, md.asDesc(), i)
 			b = b[n+m:]
 		}
@@ -309,25 +309,25 @@ func (xd *extensionDesc) unmarshalSeed(b []byte, nb *nameBuilder, pf *fileDesc,
 	xd.index = i
 
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		b = b[n:]
 		switch typ {
-		case wire.VarintType:
-			v, m := wire.ConsumeVarint(b)
+		case protowire.VarintType:
+			v, m := protowire.ConsumeVarint(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.FieldDescriptorProto_Number:
 				xd.number = pref.FieldNumber(v)
 			}
-		case wire.BytesType:
-			v, m := wire.ConsumeBytes(b)
+		case protowire.BytesType:
+			v, m := protowire.ConsumeBytes(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.FieldDescriptorProto_Name:
 				xd.fullName = nb.AppendFullName(pd.FullName(), v)
 			}
 		default:
-			m := wire.ConsumeFieldValue(num, typ, b)
+			m := protowire.ConsumeFieldValue(num, typ, b)
 			b = b[m:]
 		}
 	}
@@ -339,18 +339,18 @@ func (sd *serviceDesc) unmarshalSeed(b []byte, nb *nameBuilder, pf *fileDesc, pd
 	sd.index = i
 
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		b = b[n:]
 		switch typ {
-		case wire.BytesType:
-			v, m := wire.ConsumeBytes(b)
+		case protowire.BytesType:
+			v, m := protowire.ConsumeBytes(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.ServiceDescriptorProto_Name:
 				sd.fullName = nb.AppendFullName(pd.FullName(), v)
 			}
 		default:
-			m := wire.ConsumeFieldValue(num, typ, b)
+			m := protowire.ConsumeFieldValue(num, typ, b)
 			b = b[m:]
 		}
 	}
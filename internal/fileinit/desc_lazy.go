@@ -9,8 +9,8 @@ import (
 	"fmt"
 	"reflect"
 
+	"github.com/golang/protobuf/v2/encoding/protowire"
 	defval "github.com/golang/protobuf/v2/internal/encoding/defval"
-	wire "github.com/golang/protobuf/v2/internal/encoding/wire"
 	fieldnum "github.com/golang/protobuf/v2/internal/fieldnum"
 	pimpl "github.com/golang/protobuf/v2/internal/impl"
 	ptype "github.com/golang/protobuf/v2/internal/prototype"
@@ -333,13 +333,14 @@ func (fd *fileDesc) unmarshalFull(b []byte) {
 
 	var hasSyntax bool
 	var enumIdx, messageIdx, extensionIdx, serviceIdx int
+	var locs []pref.SourceLocation
 	fd.lazy = &fileLazy{byName: make(map[pref.FullName]pref.Descriptor)}
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		b = b[n:]
 		switch typ {
-		case wire.VarintType:
-			v, m := wire.ConsumeVarint(b)
+		case protowire.VarintType:
+			v, m := protowire.ConsumeVarint(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.FileDescriptorProto_PublicDependency:
@@ -347,8 +348,8 @@ func (fd *fileDesc) unmarshalFull(b []byte) {
 			case fieldnum.FileDescriptorProto_WeakDependency:
 				fd.lazy.imports[v].IsWeak = true
 			}
-		case wire.BytesType:
-			v, m := wire.ConsumeBytes(b)
+		case protowire.BytesType:
+			v, m := protowire.ConsumeBytes(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.FileDescriptorProto_Syntax:
@@ -379,9 +380,11 @@ func (fd *fileDesc) unmarshalFull(b []byte) {
 				serviceIdx++
 			case fieldnum.FileDescriptorProto_Options:
 				fd.lazy.options = append(fd.lazy.options, v...)
+			case fieldnum.FileDescriptorProto_SourceCodeInfo:
+				locs = unmarshalSourceCodeInfo(locs, v)
 			}
 		default:
-			m := wire.ConsumeFieldValue(num, typ, b)
+			m := protowire.ConsumeFieldValue(num, typ, b)
 			b = b[m:]
 		}
 	}
@@ -390,17 +393,101 @@ func (fd *fileDesc) unmarshalFull(b []byte) {
 	if !hasSyntax {
 		fd.lazy.syntax = pref.Proto2
 	}
+	fd.lazy.locs = ptype.NewSourceLocations(fd, locs)
+}
+
+// unmarshalSourceCodeInfo appends the locations in the
+// google.protobuf.SourceCodeInfo message b to ls.
+func unmarshalSourceCodeInfo(ls []pref.SourceLocation, b []byte) []pref.SourceLocation {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		b = b[n:]
+		switch typ {
+		case protowire.BytesType:
+			v, m := protowire.ConsumeBytes(b)
+			b = b[m:]
+			switch num {
+			case fieldnum.SourceCodeInfo_Location:
+				if l, ok := unmarshalSourceLocation(v); ok {
+					ls = append(ls, l)
+				}
+			}
+		default:
+			m := protowire.ConsumeFieldValue(num, typ, b)
+			b = b[m:]
+		}
+	}
+	return ls
+}
+
+// unmarshalSourceLocation unmarshals a google.protobuf.SourceCodeInfo.Location
+// message. It reports false if the location has an invalid span.
+func unmarshalSourceLocation(b []byte) (l pref.SourceLocation, ok bool) {
+	// The path of a location is non-nil, even if it refers to the file.
+	l.Path = pref.SourcePath{}
+	var span []int32
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		b = b[n:]
+		switch typ {
+		case protowire.VarintType:
+			v, m := protowire.ConsumeVarint(b)
+			b = b[m:]
+			switch num {
+			case fieldnum.SourceCodeInfo_Location_Path:
+				l.Path = append(l.Path, int32(v))
+			case fieldnum.SourceCodeInfo_Location_Span:
+				span = append(span, int32(v))
+			}
+		case protowire.BytesType:
+			v, m := protowire.ConsumeBytes(b)
+			b = b[m:]
+			switch num {
+			case fieldnum.SourceCodeInfo_Location_Path:
+				l.Path = unmarshalPackedInt32s(l.Path, v)
+			case fieldnum.SourceCodeInfo_Location_Span:
+				span = unmarshalPackedInt32s(span, v)
+			case fieldnum.SourceCodeInfo_Location_LeadingComments:
+				l.LeadingComments = string(v)
+			case fieldnum.SourceCodeInfo_Location_TrailingComments:
+				l.TrailingComments = string(v)
+			case fieldnum.SourceCodeInfo_Location_LeadingDetachedComments:
+				l.LeadingDetachedComments = append(l.LeadingDetachedComments, string(v))
+			}
+		default:
+			m := protowire.ConsumeFieldValue(num, typ, b)
+			b = b[m:]
+		}
+	}
+	switch len(span) {
+	case 3:
+		l.StartLine, l.StartColumn, l.EndLine, l.EndColumn = int(span[0]), int(span[1]), int(span[0]), int(span[2])
+	case 4:
+		l.StartLine, l.StartColumn, l.EndLine, l.EndColumn = int(span[0]), int(span[1]), int(span[2]), int(span[3])
+	default:
+		return pref.SourceLocation{}, false
+	}
+	return l, true
+}
+
+func unmarshalPackedInt32s(vs []int32, b []byte) []int32 {
+	for len(b) > 0 {
+		v, n := protowire.ConsumeVarint(b)
+		b = b[n:]
+		vs = append(vs, int32(v))
+	}
+	return vs
 }
 
 func (ed *enumDesc) unmarshalFull(b []byte, nb *nameBuilder) {
 	var rawValues [][]byte
 	ed.lazy = new(enumLazy)
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		b = b[n:]
 		switch typ {
-		case wire.BytesType:
-			v, m := wire.ConsumeBytes(b)
+		case protowire.BytesType:
+			v, m := protowire.ConsumeBytes(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.EnumDescriptorProto_Value:
@@ -413,7 +500,7 @@ func (ed *enumDesc) unmarshalFull(b []byte, nb *nameBuilder) {
 				ed.lazy.options = append(ed.lazy.options, v...)
 			}
 		default:
-			m := wire.ConsumeFieldValue(num, typ, b)
+			m := protowire.ConsumeFieldValue(num, typ, b)
 			b = b[m:]
 		}
 	}
@@ -430,11 +517,11 @@ func (ed *enumDesc) unmarshalFull(b []byte, nb *nameBuilder) {
 
 func unmarshalEnumReservedRange(b []byte) (r [2]pref.EnumNumber) {
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		b = b[n:]
 		switch typ {
-		case wire.VarintType:
-			v, m := wire.ConsumeVarint(b)
+		case protowire.VarintType:
+			v, m := protowire.ConsumeVarint(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.EnumDescriptorProto_EnumReservedRange_Start:
@@ -443,7 +530,7 @@ func unmarshalEnumReservedRange(b []byte) (r [2]pref.EnumNumber) {
 				r[1] = pref.EnumNumber(v)
 			}
 		default:
-			m := wire.ConsumeFieldValue(num, typ, b)
+			m := protowire.ConsumeFieldValue(num, typ, b)
 			b = b[m:]
 		}
 	}
@@ -456,18 +543,18 @@ func (vd *enumValueDesc) unmarshalFull(b []byte, nb *nameBuilder, pf *fileDesc,
 	vd.index = i
 
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		b = b[n:]
 		switch typ {
-		case wire.VarintType:
-			v, m := wire.ConsumeVarint(b)
+		case protowire.VarintType:
+			v, m := protowire.ConsumeVarint(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.EnumValueDescriptorProto_Number:
 				vd.number = pref.EnumNumber(v)
 			}
-		case wire.BytesType:
-			v, m := wire.ConsumeBytes(b)
+		case protowire.BytesType:
+			v, m := protowire.ConsumeBytes(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.EnumValueDescriptorProto_Name:
@@ -476,7 +563,7 @@ func (vd *enumValueDesc) unmarshalFull(b []byte, nb *nameBuilder, pf *fileDesc,
 				vd.options = append(vd.options, v...)
 			}
 		default:
-			m := wire.ConsumeFieldValue(num, typ, b)
+			m := protowire.ConsumeFieldValue(num, typ, b)
 			b = b[m:]
 		}
 	}
@@ -490,11 +577,11 @@ func (md *messageDesc) unmarshalFull(b []byte, nb *nameBuilder) {
 	var isMapEntry bool
 	md.lazy = new(messageLazy)
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		b = b[n:]
 		switch typ {
-		case wire.BytesType:
-			v, m := wire.ConsumeBytes(b)
+		case protowire.BytesType:
+			v, m := protowire.ConsumeBytes(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.DescriptorProto_Field:
@@ -522,7 +609,7 @@ func (md *messageDesc) unmarshalFull(b []byte, nb *nameBuilder) {
 				md.unmarshalOptions(v, &isMapEntry)
 			}
 		default:
-			m := wire.ConsumeFieldValue(num, typ, b)
+			m := protowire.ConsumeFieldValue(num, typ, b)
 			b = b[m:]
 		}
 	}
@@ -553,20 +640,20 @@ func (md *messageDesc) unmarshalFull(b []byte, nb *nameBuilder) {
 func (md *messageDesc) unmarshalOptions(b []byte, isMapEntry *bool) {
 	md.lazy.options = append(md.lazy.options, b...)
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		b = b[n:]
 		switch typ {
-		case wire.VarintType:
-			v, m := wire.ConsumeVarint(b)
+		case protowire.VarintType:
+			v, m := protowire.ConsumeVarint(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.MessageOptions_MapEntry:
-				*isMapEntry = wire.DecodeBool(v)
+				*isMapEntry = protowire.DecodeBool(v)
 			case fieldnum.MessageOptions_MessageSetWireFormat:
-				md.lazy.isMessageSet = wire.DecodeBool(v)
+				md.lazy.isMessageSet = protowire.DecodeBool(v)
 			}
 		default:
-			m := wire.ConsumeFieldValue(num, typ, b)
+			m := protowire.ConsumeFieldValue(num, typ, b)
 			b = b[m:]
 		}
 	}
@@ -574,11 +661,11 @@ func (md *messageDesc) unmarshalOptions(b []byte, isMapEntry *bool) {
 
 func unmarshalMessageReservedRange(b []byte) (r [2]pref.FieldNumber) {
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		b = b[n:]
 		switch typ {
-		case wire.VarintType:
-			v, m := wire.ConsumeVarint(b)
+		case protowire.VarintType:
+			v, m := protowire.ConsumeVarint(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.DescriptorProto_ReservedRange_Start:
@@ -587,7 +674,7 @@ func unmarshalMessageReservedRange(b []byte) (r [2]pref.FieldNumber) {
 				r[1] = pref.FieldNumber(v)
 			}
 		default:
-			m := wire.ConsumeFieldValue(num, typ, b)
+			m := protowire.ConsumeFieldValue(num, typ, b)
 			b = b[m:]
 		}
 	}
@@ -596,11 +683,11 @@ func unmarshalMessageReservedRange(b []byte) (r [2]pref.FieldNumber) {
 
 func unmarshalMessageExtensionRange(b []byte) (r [2]pref.FieldNumber, opts []byte) {
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		b = b[n:]
 		switch typ {
-		case wire.VarintType:
-			v, m := wire.ConsumeVarint(b)
+		case protowire.VarintType:
+			v, m := protowire.ConsumeVarint(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.DescriptorProto_ExtensionRange_Start:
@@ -608,15 +695,15 @@ func unmarshalMessageExtensionRange(b []byte) (r [2]pref.FieldNumber, opts []byt
 			case fieldnum.DescriptorProto_ExtensionRange_End:
 				r[1] = pref.FieldNumber(v)
 			}
-		case wire.BytesType:
-			v, m := wire.ConsumeBytes(b)
+		case protowire.BytesType:
+			v, m := protowire.ConsumeBytes(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.DescriptorProto_ExtensionRange_Options:
 				opts = append(opts, v...)
 			}
 		default:
-			m := wire.ConsumeFieldValue(num, typ, b)
+			m := protowire.ConsumeFieldValue(num, typ, b)
 			b = b[m:]
 		}
 	}
@@ -631,11 +718,11 @@ func (fd *fieldDesc) unmarshalFull(b []byte, nb *nameBuilder, pf *fileDesc, pd p
 	var rawDefVal []byte
 	var rawTypeName []byte
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		b = b[n:]
 		switch typ {
-		case wire.VarintType:
-			v, m := wire.ConsumeVarint(b)
+		case protowire.VarintType:
+			v, m := protowire.ConsumeVarint(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.FieldDescriptorProto_Number:
@@ -655,8 +742,8 @@ func (fd *fieldDesc) unmarshalFull(b []byte, nb *nameBuilder, pf *fileDesc, pd p
 				}
 				fd.oneofType = od
 			}
-		case wire.BytesType:
-			v, m := wire.ConsumeBytes(b)
+		case protowire.BytesType:
+			v, m := protowire.ConsumeBytes(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.FieldDescriptorProto_Name:
@@ -673,7 +760,7 @@ func (fd *fieldDesc) unmarshalFull(b []byte, nb *nameBuilder, pf *fileDesc, pd p
 				fd.unmarshalOptions(v)
 			}
 		default:
-			m := wire.ConsumeFieldValue(num, typ, b)
+			m := protowire.ConsumeFieldValue(num, typ, b)
 			b = b[m:]
 		}
 	}
@@ -701,21 +788,21 @@ func (fd *fieldDesc) unmarshalFull(b []byte, nb *nameBuilder, pf *fileDesc, pd p
 func (fd *fieldDesc) unmarshalOptions(b []byte) {
 	fd.options = append(fd.options, b...)
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		b = b[n:]
 		switch typ {
-		case wire.VarintType:
-			v, m := wire.ConsumeVarint(b)
+		case protowire.VarintType:
+			v, m := protowire.ConsumeVarint(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.FieldOptions_Packed:
 				fd.hasPacked = true
-				fd.isPacked = wire.DecodeBool(v)
+				fd.isPacked = protowire.DecodeBool(v)
 			case fieldnum.FieldOptions_Weak:
-				fd.isWeak = wire.DecodeBool(v)
+				fd.isWeak = protowire.DecodeBool(v)
 			}
 		default:
-			m := wire.ConsumeFieldValue(num, typ, b)
+			m := protowire.ConsumeFieldValue(num, typ, b)
 			b = b[m:]
 		}
 	}
@@ -727,11 +814,11 @@ func (od *oneofDesc) unmarshalFull(b []byte, nb *nameBuilder, pf *fileDesc, pd p
 	od.index = i
 
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		b = b[n:]
 		switch typ {
-		case wire.BytesType:
-			v, m := wire.ConsumeBytes(b)
+		case protowire.BytesType:
+			v, m := protowire.ConsumeBytes(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.OneofDescriptorProto_Name:
@@ -740,7 +827,7 @@ func (od *oneofDesc) unmarshalFull(b []byte, nb *nameBuilder, pf *fileDesc, pd p
 				od.options = append(od.options, v...)
 			}
 		default:
-			m := wire.ConsumeFieldValue(num, typ, b)
+			m := protowire.ConsumeFieldValue(num, typ, b)
 			b = b[m:]
 		}
 	}
@@ -752,11 +839,11 @@ func (xd *extensionDesc) unmarshalFull(b []byte, nb *nameBuilder) {
 	var rawDefVal []byte
 	xd.lazy = new(extensionLazy)
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		b = b[n:]
 		switch typ {
-		case wire.VarintType:
-			v, m := wire.ConsumeVarint(b)
+		case protowire.VarintType:
+			v, m := protowire.ConsumeVarint(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.FieldDescriptorProto_Label:
@@ -764,8 +851,8 @@ func (xd *extensionDesc) unmarshalFull(b []byte, nb *nameBuilder) {
 			case fieldnum.FieldDescriptorProto_Type:
 				xd.lazy.kind = pref.Kind(v)
 			}
-		case wire.BytesType:
-			v, m := wire.ConsumeBytes(b)
+		case protowire.BytesType:
+			v, m := protowire.ConsumeBytes(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.FieldDescriptorProto_JsonName:
@@ -778,7 +865,7 @@ func (xd *extensionDesc) unmarshalFull(b []byte, nb *nameBuilder) {
 				xd.unmarshalOptions(v)
 			}
 		default:
-			m := wire.ConsumeFieldValue(num, typ, b)
+			m := protowire.ConsumeFieldValue(num, typ, b)
 			b = b[m:]
 		}
 	}
@@ -797,18 +884,18 @@ func (xd *extensionDesc) unmarshalFull(b []byte, nb *nameBuilder) {
 func (xd *extensionDesc) unmarshalOptions(b []byte) {
 	xd.lazy.options = append(xd.lazy.options, b...)
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		b = b[n:]
 		switch typ {
-		case wire.VarintType:
-			v, m := wire.ConsumeVarint(b)
+		case protowire.VarintType:
+			v, m := protowire.ConsumeVarint(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.FieldOptions_Packed:
-				xd.lazy.isPacked = wire.DecodeBool(v)
+				xd.lazy.isPacked = protowire.DecodeBool(v)
 			}
 		default:
-			m := wire.ConsumeFieldValue(num, typ, b)
+			m := protowire.ConsumeFieldValue(num, typ, b)
 			b = b[m:]
 		}
 	}
@@ -818,11 +905,11 @@ func (sd *serviceDesc) unmarshalFull(b []byte, nb *nameBuilder) {
 	var rawMethods [][]byte
 	sd.lazy = new(serviceLazy)
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		b = b[n:]
 		switch typ {
-		case wire.BytesType:
-			v, m := wire.ConsumeBytes(b)
+		case protowire.BytesType:
+			v, m := protowire.ConsumeBytes(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.ServiceDescriptorProto_Method:
@@ -831,7 +918,7 @@ func (sd *serviceDesc) unmarshalFull(b []byte, nb *nameBuilder) {
 				sd.lazy.options = append(sd.lazy.options, v...)
 			}
 		default:
-			m := wire.ConsumeFieldValue(num, typ, b)
+			m := protowire.ConsumeFieldValue(num, typ, b)
 			b = b[m:]
 		}
 	}
@@ -852,20 +939,20 @@ func (md *methodDesc) unmarshalFull(b []byte, nb *nameBuilder, pf *fileDesc, pd
 	md.index = i
 
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		b = b[n:]
 		switch typ {
-		case wire.VarintType:
-			v, m := wire.ConsumeVarint(b)
+		case protowire.VarintType:
+			v, m := protowire.ConsumeVarint(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.MethodDescriptorProto_ClientStreaming:
-				md.isStreamingClient = wire.DecodeBool(v)
+				md.isStreamingClient = protowire.DecodeBool(v)
 			case fieldnum.MethodDescriptorProto_ServerStreaming:
-				md.isStreamingServer = wire.DecodeBool(v)
+				md.isStreamingServer = protowire.DecodeBool(v)
 			}
-		case wire.BytesType:
-			v, m := wire.ConsumeBytes(b)
+		case protowire.BytesType:
+			v, m := protowire.ConsumeBytes(b)
 			b = b[m:]
 			switch num {
 			case fieldnum.MethodDescriptorProto_Name:
@@ -874,7 +961,7 @@ func (md *methodDesc) unmarshalFull(b []byte, nb *nameBuilder, pf *fileDesc, pd
 				md.options = append(md.options, v...)
 			}
 		default:
-			m := wire.ConsumeFieldValue(num, typ, b)
+			m := protowire.ConsumeFieldValue(num, typ, b)
 			b = b[m:]
 		}
 	}
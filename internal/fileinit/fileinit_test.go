@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"compress/gzip"
 	"io/ioutil"
+	"reflect"
 	"testing"
 
 	proto "github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/v2/internal/fileinit"
+	scalar "github.com/golang/protobuf/v2/internal/scalar"
 	testpb "github.com/golang/protobuf/v2/internal/testprotos/test"
 	"github.com/golang/protobuf/v2/reflect/protodesc"
 	"github.com/golang/protobuf/v2/reflect/protoreflect"
@@ -40,7 +43,7 @@ func TestInit(t *testing.T) {
 		seen[field.FullName()] = true
 	})
 	ignore := map[protoreflect.FullName]bool{
-		// The protoreflect descriptors don't include source info.
+		// The generated descriptors don't include source info.
 		"google.protobuf.FileDescriptorProto.source_code_info": true,
 		"google.protobuf.FileDescriptorProto.syntax":           true,
 
@@ -79,6 +82,71 @@ func TestInit(t *testing.T) {
 	}
 }
 
+func TestSourceLocations(t *testing.T) {
+	b, err := proto.Marshal(&descriptorpb.FileDescriptorProto{
+		Name:    scalar.String("test.proto"),
+		Package: scalar.String("test"),
+		Service: []*descriptorpb.ServiceDescriptorProto{
+			{Name: scalar.String("Foo")},
+			{Name: scalar.String("Bar")},
+		},
+		SourceCodeInfo: &descriptorpb.SourceCodeInfo{
+			Location: []*descriptorpb.SourceCodeInfo_Location{{
+				Span: []int32{0, 0, 4, 1},
+			}, {
+				Path:                    []int32{6, 0},
+				Span:                    []int32{2, 0, 3},
+				LeadingDetachedComments: []string{" detached\n"},
+				LeadingComments:         scalar.String(" leading\n"),
+				TrailingComments:        scalar.String(" trailing\n"),
+			}, {
+				// A location with an invalid span is ignored.
+				Path: []int32{6, 1},
+				Span: []int32{5, 0},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd := fileinit.FileBuilder{RawDescriptor: b}.Init()
+	other := fileinit.FileBuilder{RawDescriptor: b}.Init()
+
+	locs := fd.SourceLocations()
+	if got, want := locs.Len(), 2; got != want {
+		t.Fatalf("SourceLocations().Len() = %v, want %v", got, want)
+	}
+	for _, tt := range []struct {
+		d    protoreflect.Descriptor
+		want protoreflect.SourceLocation
+	}{{
+		d:    fd,
+		want: protoreflect.SourceLocation{Path: protoreflect.SourcePath{}, EndLine: 4, EndColumn: 1},
+	}, {
+		d: fd.Services().Get(0),
+		want: protoreflect.SourceLocation{
+			Path:                    protoreflect.SourcePath{6, 0},
+			StartLine:               2,
+			EndLine:                 2,
+			EndColumn:               3,
+			LeadingDetachedComments: []string{" detached\n"},
+			LeadingComments:         " leading\n",
+			TrailingComments:        " trailing\n",
+		},
+	}, {
+		d:    fd.Services().Get(1),
+		want: protoreflect.SourceLocation{},
+	}, {
+		// A declaration at the same path in another file has no location.
+		d:    other.Services().Get(0),
+		want: protoreflect.SourceLocation{},
+	}} {
+		if got := locs.ByDescriptor(tt.d); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ByDescriptor(%v) = %+v, want %+v", tt.d.FullName(), got, tt.want)
+		}
+	}
+}
+
 // visitFields calls f for every field set in m and its children.
 func visitFields(m protoreflect.Message, f func(protoreflect.FieldDescriptor)) {
 	typ := m.Type()
@@ -0,0 +1,90 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !purego,!appengine
+
+package impl
+
+import (
+	"github.com/golang/protobuf/v2/encoding/protowire"
+	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+)
+
+// closedEnumValues returns the values of the enum type of fd if it is a
+// closed enum, or nil otherwise. Enums declared in proto2 files are closed:
+// values which are not members of the enum are treated as unknown fields.
+func closedEnumValues(fd pref.FieldDescriptor) pref.EnumValueDescriptors {
+	if fd.Kind() != pref.EnumKind {
+		return nil
+	}
+	ed := fd.EnumType()
+	if ed == nil || ed.IsPlaceholder() || ed.Syntax() != pref.Proto2 {
+		return nil
+	}
+	return ed.Values()
+}
+
+// unknownValues is returned by the unmarshal function of a repeated closed
+// enum field, along with the length of the record, when a packed record
+// contains values which are not members of the enum. The remaining values
+// have been decoded into the field. It holds the unknown values encoded as
+// unpacked records of the field, to be added to the unknown fields.
+type unknownValues []byte
+
+func (unknownValues) Error() string { return "BUG: internal error (unknown values)" }
+
+// makeClosedEnumCoder wraps the coder functions of a field so that values of
+// a closed enum which are not members of the enum are left to the unknown
+// fields. It returns funcs unchanged for fields of any other type.
+func makeClosedEnumCoder(fd pref.FieldDescriptor, funcs pointerCoderFuncs) pointerCoderFuncs {
+	values := closedEnumValues(fd)
+	if values == nil {
+		return funcs
+	}
+	num := fd.Number()
+	isRepeated := fd.Cardinality() == pref.Repeated
+	unmarshal := funcs.unmarshal
+	funcs.unmarshal = func(b []byte, p pointer, wtyp protowire.Type, opts unmarshalOptions) (int, error) {
+		switch {
+		case wtyp == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return 0, protowire.ParseError(n)
+			}
+			if values.ByNumber(pref.EnumNumber(v)) == nil {
+				return 0, errUnknown
+			}
+		case wtyp == protowire.BytesType && isRepeated:
+			buf, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return 0, protowire.ParseError(n)
+			}
+			var known, unknown []byte
+			for len(buf) > 0 {
+				v, n := protowire.ConsumeVarint(buf)
+				if n < 0 {
+					return 0, protowire.ParseError(n)
+				}
+				buf = buf[n:]
+				if values.ByNumber(pref.EnumNumber(v)) == nil {
+					unknown = protowire.AppendTag(unknown, num, protowire.VarintType)
+					unknown = protowire.AppendVarint(unknown, v)
+				} else {
+					known = protowire.AppendVarint(known, v)
+				}
+			}
+			if unknown == nil {
+				break
+			}
+			if len(known) > 0 {
+				if _, err := unmarshal(protowire.AppendBytes(nil, known), p, wtyp, opts); err != nil {
+					return 0, err
+				}
+			}
+			return n, unknownValues(unknown)
+		}
+		return unmarshal(b, p, wtyp, opts)
+	}
+	return funcs
+}
@@ -0,0 +1,656 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !purego,!appengine
+
+package impl
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/golang/protobuf/v2/encoding/protowire"
+	"github.com/golang/protobuf/v2/internal/errors"
+	"github.com/golang/protobuf/v2/proto"
+	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+)
+
+// fieldCoder returns the fast-path functions for a non-oneof field
+// represented by the Go type ft. It reports false if the Go type is not
+// supported by the fast path.
+func fieldCoder(fd pref.FieldDescriptor, ft reflect.Type) (pointerCoderFuncs, bool) {
+	isMessage := fd.Kind() == pref.MessageKind || fd.Kind() == pref.GroupKind
+	switch {
+	case fd.IsMap():
+		if ft.Kind() != reflect.Map {
+			return pointerCoderFuncs{}, false
+		}
+		return makeMapFieldCoder(fd, ft)
+	case fd.Cardinality() == pref.Repeated && isMessage:
+		if ft.Kind() != reflect.Slice {
+			return pointerCoderFuncs{}, false
+		}
+		return makeMessageSliceFieldCoder(fd, ft.Elem())
+	case fd.Cardinality() == pref.Repeated:
+		if ft.Kind() != reflect.Slice {
+			return pointerCoderFuncs{}, false
+		}
+		sc, ok := scalarCoderOf(fd, ft.Elem())
+		if !ok {
+			return pointerCoderFuncs{}, false
+		}
+		if fd.IsPacked() {
+			return sc.packedSlice, true
+		}
+		return sc.slice, true
+	case isMessage:
+		return makeMessageFieldCoder(fd, ft)
+	case fd.Syntax() == pref.Proto3:
+		sc, ok := scalarCoderOf(fd, ft)
+		return sc.noZero, ok
+	case fd.Kind() == pref.BytesKind:
+		sc, ok := scalarCoderOf(fd, ft)
+		return sc.ptr, ok
+	default:
+		if ft.Kind() != reflect.Ptr {
+			return pointerCoderFuncs{}, false
+		}
+		sc, ok := scalarCoderOf(fd, ft.Elem())
+		return sc.ptr, ok
+	}
+}
+
+// scalarCoderOf returns the coder functions for a scalar field of
+// the Go type ft. It reports false if ft does not have the memory layout
+// of the Go type used by the coder functions.
+func scalarCoderOf(fd pref.FieldDescriptor, ft reflect.Type) (scalarCoderFuncs, bool) {
+	kind := fd.Kind()
+	if kind == pref.EnumKind {
+		// Enums are represented as named int32 types.
+		kind = pref.Int32Kind
+	}
+	sc, ok := scalarCoders[kind]
+	if !ok || ft.Kind() != sc.goType.Kind() {
+		return scalarCoderFuncs{}, false
+	}
+	if ft.Kind() == reflect.Slice && ft.Elem().Kind() != sc.goType.Elem().Kind() {
+		return scalarCoderFuncs{}, false
+	}
+	return sc, true
+}
+
+// wireTypeOf returns the wire type used to encode a field.
+func wireTypeOf(fd pref.FieldDescriptor) protowire.Type {
+	if fd.IsPacked() {
+		return protowire.BytesType
+	}
+	switch fd.Kind() {
+	case pref.BoolKind, pref.EnumKind,
+		pref.Int32Kind, pref.Sint32Kind, pref.Uint32Kind,
+		pref.Int64Kind, pref.Sint64Kind, pref.Uint64Kind:
+		return protowire.VarintType
+	case pref.Sfixed32Kind, pref.Fixed32Kind, pref.FloatKind:
+		return protowire.Fixed32Type
+	case pref.Sfixed64Kind, pref.Fixed64Kind, pref.DoubleKind:
+		return protowire.Fixed64Type
+	case pref.GroupKind:
+		return protowire.StartGroupType
+	default:
+		return protowire.BytesType
+	}
+}
+
+// makeOneofFieldCoder returns the coder functions for a member of a oneof.
+// The oneof is stored in the interface field fs, and the member is stored
+// in the first field of the wrapper struct type ot.
+func makeOneofFieldCoder(fd pref.FieldDescriptor, fs reflect.StructField, ot reflect.Type) (pointerCoderFuncs, bool) {
+	if fs.Type.Kind() != reflect.Interface || ot == nil || ot.Kind() != reflect.Struct {
+		return pointerCoderFuncs{}, false
+	}
+	ft := fs.Type
+	vf := ot.Field(0)
+	valueOffset := offsetOf(vf)
+	isMessage := fd.Kind() == pref.MessageKind || fd.Kind() == pref.GroupKind
+	var funcs pointerCoderFuncs
+	if isMessage {
+		var ok bool
+		if funcs, ok = makeMessageFieldCoder(fd, vf.Type); !ok {
+			return pointerCoderFuncs{}, false
+		}
+	} else {
+		sc, ok := scalarCoderOf(fd, vf.Type)
+		if !ok {
+			return pointerCoderFuncs{}, false
+		}
+		funcs = makeUTF8Coder(fd, sc.value)
+	}
+
+	// getValue returns a pointer to the member value, if the oneof is
+	// currently set to this member.
+	getValue := func(p pointer) (pointer, bool) {
+		rv := p.AsValueOf(ft).Elem()
+		if rv.IsNil() || rv.Elem().Type().Elem() != ot {
+			return pointer{}, false
+		}
+		return pointerOfValue(rv.Elem()).Apply(valueOffset), true
+	}
+	oneofFuncs := pointerCoderFuncs{
+		size: func(p pointer, tagsize int, opts marshalOptions) int {
+			v, ok := getValue(p)
+			if !ok {
+				return 0
+			}
+			return funcs.size(v, tagsize, opts)
+		},
+		marshal: func(b []byte, p pointer, wiretag uint64, opts marshalOptions) ([]byte, error) {
+			v, ok := getValue(p)
+			if !ok {
+				return b, nil
+			}
+			return funcs.marshal(b, v, wiretag, opts)
+		},
+		unmarshal: func(b []byte, p pointer, wtyp protowire.Type, opts unmarshalOptions) (int, error) {
+			// Messages in a oneof replace any existing value.
+			rv := p.AsValueOf(ft).Elem()
+			var wv reflect.Value
+			if isMessage || rv.IsNil() || rv.Elem().Type().Elem() != ot {
+				wv = reflect.New(ot)
+			} else {
+				wv = rv.Elem()
+			}
+			n, err := funcs.unmarshal(b, pointerOfValue(wv).Apply(valueOffset), wtyp, opts)
+			var nerr errors.NonFatal
+			if !nerr.Merge(err) {
+				return n, err
+			}
+			rv.Set(wv)
+			return n, nerr.E
+		},
+	}
+	if funcs.isInit != nil {
+		oneofFuncs.isInit = func(p pointer) error {
+			v, ok := getValue(p)
+			if !ok {
+				return nil
+			}
+			return funcs.isInit(v)
+		}
+	}
+	return oneofFuncs, true
+}
+
+// messageCoder encodes and decodes messages of a particular Go type,
+// which must be a pointer to a struct. It uses the fast-path functions of
+// the message type when available, and the proto package otherwise.
+type messageCoder struct {
+	goType reflect.Type
+
+	once sync.Once
+	mi   *MessageType // nil if the fast path is unavailable
+}
+
+// messageType returns the MessageType for the message, or nil if the
+// message does not support the fast path.
+//
+// The MessageType is lazily resolved since messages may be recursive.
+func (mc *messageCoder) messageType() *MessageType {
+	mc.once.Do(func() {
+		if mi := messageTypeOf(mc.goType); mi != nil && mi.Methods() != nil {
+			mc.mi = mi
+		}
+	})
+	return mc.mi
+}
+
+// messageTypeOf returns the MessageType for a message Go type,
+// or nil if the message is not implemented by a MessageType.
+func messageTypeOf(t reflect.Type) *MessageType {
+	v := reflect.Zero(t).Interface()
+	var m pref.Message
+	switch {
+	case isProtoMessage(v):
+		m = v.(pref.ProtoMessage).ProtoReflect()
+	case legacyWrapper != nil:
+		m = legacyWrapper.MessageOf(v)
+	default:
+		return nil
+	}
+	if w, ok := m.(*messageReflectWrapper); ok {
+		return w.mi
+	}
+	return nil
+}
+
+func isProtoMessage(v interface{}) bool {
+	_, ok := v.(pref.ProtoMessage)
+	return ok
+}
+
+// asMessage returns the message pointed to by p.
+func (mc *messageCoder) asMessage(p pointer) pref.ProtoMessage {
+	v := p.AsIfaceOf(mc.goType.Elem())
+	if m, ok := v.(pref.ProtoMessage); ok {
+		return m
+	}
+	return legacyWrapper.MessageOf(v).Interface()
+}
+
+func (mc *messageCoder) size(p pointer, opts marshalOptions) int {
+	if mi := mc.messageType(); mi != nil {
+		return mi.sizePointer(p, opts)
+	}
+	return proto.Size(mc.asMessage(p))
+}
+
+func (mc *messageCoder) marshal(b []byte, p pointer, opts marshalOptions) ([]byte, error) {
+	if mi := mc.messageType(); mi != nil {
+		return mi.marshalAppendPointer(b, p, opts)
+	}
+	return proto.MarshalOptions{
+		AllowPartial:  true,
+		Deterministic: opts.Deterministic,
+	}.MarshalAppend(b, mc.asMessage(p))
+}
+
+func (mc *messageCoder) unmarshal(b []byte, p pointer, opts unmarshalOptions) error {
+	if mi := mc.messageType(); mi != nil {
+		return mi.unmarshalPointer(b, p, opts)
+	}
+	if opts.RecursionLimit <= 0 {
+		// Avoid passing a zero limit, which proto treats as the default.
+		return errors.ErrRecursionLimit
+	}
+	return proto.UnmarshalOptions{
+		AllowPartial:   true,
+		DiscardUnknown: opts.DiscardUnknown,
+		Merge:          true,
+		Resolver:       opts.Resolver,
+		RecursionLimit: opts.RecursionLimit,
+		AliasBuffer:    opts.AliasBuffer,
+		AliasStrings:   opts.AliasStrings,
+	}.Unmarshal(b, mc.asMessage(p))
+}
+
+func (mc *messageCoder) isInit(p pointer) error {
+	if mi := mc.messageType(); mi != nil {
+		return mi.isInitializedPointer(p)
+	}
+	return proto.IsInitialized(mc.asMessage(p))
+}
+
+// newMessage allocates a new message struct, returning a pointer to it.
+func (mc *messageCoder) newMessage() pointer {
+	return pointerOfValue(reflect.New(mc.goType.Elem()))
+}
+
+// makeMessageFieldCoder returns the coder functions for a singular message
+// field of the Go type ft, which must be a pointer to a struct.
+func makeMessageFieldCoder(fd pref.FieldDescriptor, ft reflect.Type) (pointerCoderFuncs, bool) {
+	if ft.Kind() != reflect.Ptr || ft.Elem().Kind() != reflect.Struct {
+		return pointerCoderFuncs{}, false
+	}
+	mc := &messageCoder{goType: ft}
+	if fd.Kind() == pref.GroupKind {
+		num := fd.Number()
+		return pointerCoderFuncs{
+			size: func(p pointer, tagsize int, opts marshalOptions) int {
+				v := p.Elem()
+				if v.IsNil() {
+					return 0
+				}
+				return 2*tagsize + mc.size(v, opts)
+			},
+			marshal: func(b []byte, p pointer, wiretag uint64, opts marshalOptions) ([]byte, error) {
+				v := p.Elem()
+				if v.IsNil() {
+					return b, nil
+				}
+				b = protowire.AppendVarint(b, wiretag) // start group
+				b, err := mc.marshal(b, v, opts)
+				b = protowire.AppendVarint(b, protowire.EncodeTag(num, protowire.EndGroupType))
+				return b, err
+			},
+			unmarshal: func(b []byte, p pointer, wtyp protowire.Type, opts unmarshalOptions) (int, error) {
+				if wtyp != protowire.StartGroupType {
+					return 0, errUnknown
+				}
+				b, n := protowire.ConsumeGroup(num, b)
+				if n < 0 {
+					return 0, protowire.ParseError(n)
+				}
+				if p.Elem().IsNil() {
+					p.SetPointer(mc.newMessage())
+				}
+				return n, mc.unmarshal(b, p.Elem(), opts)
+			},
+			isInit: func(p pointer) error {
+				v := p.Elem()
+				if v.IsNil() {
+					return nil
+				}
+				return mc.isInit(v)
+			},
+		}, true
+	}
+	return pointerCoderFuncs{
+		size: func(p pointer, tagsize int, opts marshalOptions) int {
+			v := p.Elem()
+			if v.IsNil() {
+				return 0
+			}
+			return tagsize + protowire.SizeBytes(mc.size(v, opts))
+		},
+		marshal: func(b []byte, p pointer, wiretag uint64, opts marshalOptions) ([]byte, error) {
+			v := p.Elem()
+			if v.IsNil() {
+				return b, nil
+			}
+			b = protowire.AppendVarint(b, wiretag)
+			b = protowire.AppendVarint(b, uint64(mc.size(v, opts)))
+			return mc.marshal(b, v, opts)
+		},
+		unmarshal: func(b []byte, p pointer, wtyp protowire.Type, opts unmarshalOptions) (int, error) {
+			if wtyp != protowire.BytesType {
+				return 0, errUnknown
+			}
+			b, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return 0, protowire.ParseError(n)
+			}
+			if p.Elem().IsNil() {
+				p.SetPointer(mc.newMessage())
+			}
+			return n, errors.AddOffset(mc.unmarshal(b, p.Elem(), opts), n-len(b))
+		},
+		isInit: func(p pointer) error {
+			v := p.Elem()
+			if v.IsNil() {
+				return nil
+			}
+			return mc.isInit(v)
+		},
+	}, true
+}
+
+// makeMessageSliceFieldCoder returns the coder functions for a repeated
+// message field with the element Go type ft, which must be a pointer to
+// a struct.
+func makeMessageSliceFieldCoder(fd pref.FieldDescriptor, ft reflect.Type) (pointerCoderFuncs, bool) {
+	if ft.Kind() != reflect.Ptr || ft.Elem().Kind() != reflect.Struct {
+		return pointerCoderFuncs{}, false
+	}
+	mc := &messageCoder{goType: ft}
+	isInit := func(p pointer) error {
+		for _, v := range p.PointerSlice() {
+			if err := mc.isInit(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if fd.Kind() == pref.GroupKind {
+		num := fd.Number()
+		return pointerCoderFuncs{
+			size: func(p pointer, tagsize int, opts marshalOptions) (size int) {
+				for _, v := range p.PointerSlice() {
+					size += 2*tagsize + mc.size(v, opts)
+				}
+				return size
+			},
+			marshal: func(b []byte, p pointer, wiretag uint64, opts marshalOptions) ([]byte, error) {
+				var nerr errors.NonFatal
+				for _, v := range p.PointerSlice() {
+					var err error
+					b = protowire.AppendVarint(b, wiretag) // start group
+					b, err = mc.marshal(b, v, opts)
+					if !nerr.Merge(err) {
+						return b, err
+					}
+					b = protowire.AppendVarint(b, protowire.EncodeTag(num, protowire.EndGroupType))
+				}
+				return b, nerr.E
+			},
+			unmarshal: func(b []byte, p pointer, wtyp protowire.Type, opts unmarshalOptions) (int, error) {
+				if wtyp != protowire.StartGroupType {
+					return 0, errUnknown
+				}
+				b, n := protowire.ConsumeGroup(num, b)
+				if n < 0 {
+					return 0, protowire.ParseError(n)
+				}
+				v := mc.newMessage()
+				err := mc.unmarshal(b, v, opts)
+				err = errors.AddIndex(err, strconv.Itoa(len(p.PointerSlice())))
+				var nerr errors.NonFatal
+				if !nerr.Merge(err) {
+					return 0, err
+				}
+				p.AppendPointerSlice(v)
+				return n, nerr.E
+			},
+			isInit: isInit,
+		}, true
+	}
+	return pointerCoderFuncs{
+		size: func(p pointer, tagsize int, opts marshalOptions) (size int) {
+			for _, v := range p.PointerSlice() {
+				size += tagsize + protowire.SizeBytes(mc.size(v, opts))
+			}
+			return size
+		},
+		marshal: func(b []byte, p pointer, wiretag uint64, opts marshalOptions) ([]byte, error) {
+			var nerr errors.NonFatal
+			for _, v := range p.PointerSlice() {
+				var err error
+				b = protowire.AppendVarint(b, wiretag)
+				b = protowire.AppendVarint(b, uint64(mc.size(v, opts)))
+				b, err = mc.marshal(b, v, opts)
+				if !nerr.Merge(err) {
+					return b, err
+				}
+			}
+			return b, nerr.E
+		},
+		unmarshal: func(b []byte, p pointer, wtyp protowire.Type, opts unmarshalOptions) (int, error) {
+			if wtyp != protowire.BytesType {
+				return 0, errUnknown
+			}
+			b, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return 0, protowire.ParseError(n)
+			}
+			v := mc.newMessage()
+			err := mc.unmarshal(b, v, opts)
+			err = errors.AddIndex(errors.AddOffset(err, n-len(b)), strconv.Itoa(len(p.PointerSlice())))
+			var nerr errors.NonFatal
+			if !nerr.Merge(err) {
+				return 0, err
+			}
+			p.AppendPointerSlice(v)
+			return n, nerr.E
+		},
+		isInit: isInit,
+	}, true
+}
+
+// makeMapFieldCoder returns the coder functions for a map field of
+// the Go type ft.
+//
+// Each map entry is encoded as a message with the key in field 1 and the
+// value in field 2. Keys and values are copied into addressable temporaries
+// so that they may be handled by the pointer coder functions.
+func makeMapFieldCoder(fd pref.FieldDescriptor, ft reflect.Type) (pointerCoderFuncs, bool) {
+	keyField := fd.MessageType().Fields().ByNumber(1)
+	valField := fd.MessageType().Fields().ByNumber(2)
+	keySC, ok := scalarCoderOf(keyField, ft.Key())
+	if !ok {
+		return pointerCoderFuncs{}, false
+	}
+	keyFuncs := makeUTF8Coder(keyField, keySC.value)
+	var valFuncs pointerCoderFuncs
+	valIsMessage := valField.Kind() == pref.MessageKind
+	if valIsMessage {
+		if valFuncs, ok = makeMessageFieldCoder(valField, ft.Elem()); !ok {
+			return pointerCoderFuncs{}, false
+		}
+	} else {
+		valSC, ok := scalarCoderOf(valField, ft.Elem())
+		if !ok {
+			return pointerCoderFuncs{}, false
+		}
+		valFuncs = makeUTF8Coder(valField, valSC.value)
+	}
+	valEnums := closedEnumValues(valField)
+	keyWiretag := protowire.EncodeTag(1, wireTypeOf(keyField))
+	valWiretag := protowire.EncodeTag(2, wireTypeOf(valField))
+	emptyValTag := protowire.AppendVarint(protowire.AppendVarint(nil, valWiretag), 0)
+
+	// sizeEntry returns the size of a map entry, not including the
+	// tag and length of the entry itself.
+	sizeEntry := func(kp, vp pointer, opts marshalOptions) int {
+		n := keyFuncs.size(kp, 1, opts)
+		if valIsMessage && vp.Elem().IsNil() {
+			return n + len(emptyValTag)
+		}
+		return n + valFuncs.size(vp, 1, opts)
+	}
+	funcs := pointerCoderFuncs{
+		size: func(p pointer, tagsize int, opts marshalOptions) (size int) {
+			mapv := p.AsValueOf(ft).Elem()
+			if mapv.Len() == 0 {
+				return 0
+			}
+			kv, vv := reflect.New(ft.Key()), reflect.New(ft.Elem())
+			kp, vp := pointerOfValue(kv), pointerOfValue(vv)
+			for _, k := range mapv.MapKeys() {
+				kv.Elem().Set(k)
+				vv.Elem().Set(mapv.MapIndex(k))
+				size += tagsize + protowire.SizeBytes(sizeEntry(kp, vp, opts))
+			}
+			return size
+		},
+		marshal: func(b []byte, p pointer, wiretag uint64, opts marshalOptions) ([]byte, error) {
+			mapv := p.AsValueOf(ft).Elem()
+			if mapv.Len() == 0 {
+				return b, nil
+			}
+			keys := mapv.MapKeys()
+			if opts.Deterministic {
+				sortMapKeys(keys)
+			}
+			kv, vv := reflect.New(ft.Key()), reflect.New(ft.Elem())
+			kp, vp := pointerOfValue(kv), pointerOfValue(vv)
+			var nerr errors.NonFatal
+			for _, k := range keys {
+				kv.Elem().Set(k)
+				vv.Elem().Set(mapv.MapIndex(k))
+				b = protowire.AppendVarint(b, wiretag)
+				b = protowire.AppendVarint(b, uint64(sizeEntry(kp, vp, opts)))
+				var err error
+				var eerr errors.NonFatal
+				b, err = keyFuncs.marshal(b, kp, keyWiretag, opts)
+				eerr.Merge(err) // keys only report non-fatal errors
+				if valIsMessage && vp.Elem().IsNil() {
+					b = append(b, emptyValTag...)
+				} else {
+					b, err = valFuncs.marshal(b, vp, valWiretag, opts)
+					if !eerr.Merge(err) {
+						return b, err
+					}
+				}
+				nerr.Merge(errors.AddIndex(eerr.E, fmt.Sprint(k.Interface())))
+			}
+			return b, nerr.E
+		},
+		unmarshal: func(b []byte, p pointer, wtyp protowire.Type, opts unmarshalOptions) (int, error) {
+			if wtyp != protowire.BytesType {
+				return 0, errUnknown
+			}
+			b, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return 0, protowire.ParseError(n)
+			}
+			kv, vv := reflect.New(ft.Key()), reflect.New(ft.Elem())
+			kp, vp := pointerOfValue(kv), pointerOfValue(vv)
+			var nerr errors.NonFatal
+			for pos := n - len(b); len(b) > 0; {
+				num, wtyp, n := protowire.ConsumeTag(b)
+				if n < 0 {
+					return 0, errors.AddOffset(protowire.ParseError(n), pos)
+				}
+				b = b[n:]
+				pos += n
+				err := errUnknown
+				switch num {
+				case 1:
+					n, err = keyFuncs.unmarshal(b, kp, wtyp, opts)
+				case 2:
+					if valEnums != nil && wtyp == protowire.VarintType {
+						v, n := protowire.ConsumeVarint(b)
+						if n >= 0 && valEnums.ByNumber(pref.EnumNumber(v)) == nil {
+							// The entire entry is unknown if the value is not
+							// a member of a closed enum.
+							return 0, errUnknown
+						}
+					}
+					n, err = valFuncs.unmarshal(b, vp, wtyp, opts)
+				}
+				if err == errUnknown {
+					n = protowire.ConsumeFieldValue(num, wtyp, b)
+					if n < 0 {
+						return 0, errors.AddOffset(protowire.ParseError(n), pos)
+					}
+				} else if err := errors.AddOffset(err, pos); !nerr.Merge(err) {
+					return 0, err
+				}
+				b = b[n:]
+				pos += n
+			}
+			if valIsMessage && vp.Elem().IsNil() {
+				vp.SetPointer(pointerOfValue(reflect.New(ft.Elem().Elem())))
+			}
+			mapv := p.AsValueOf(ft).Elem()
+			if mapv.IsNil() {
+				mapv.Set(reflect.MakeMap(ft))
+			}
+			mapv.SetMapIndex(kv.Elem(), vv.Elem())
+			return n, errors.AddIndex(nerr.E, fmt.Sprint(kv.Elem().Interface()))
+		},
+	}
+	if valIsMessage {
+		funcs.isInit = func(p pointer) error {
+			mapv := p.AsValueOf(ft).Elem()
+			vv := reflect.New(ft.Elem())
+			vp := pointerOfValue(vv)
+			for _, k := range mapv.MapKeys() {
+				vv.Elem().Set(mapv.MapIndex(k))
+				if err := valFuncs.isInit(vp); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	return funcs, true
+}
+
+// sortMapKeys sorts map keys of a bool, integer, or string kind
+// in ascending order.
+func sortMapKeys(keys []reflect.Value) {
+	sort.Slice(keys, func(i, j int) bool {
+		switch keys[i].Kind() {
+		case reflect.Bool:
+			return !keys[i].Bool() && keys[j].Bool()
+		case reflect.Int32, reflect.Int64:
+			return keys[i].Int() < keys[j].Int()
+		case reflect.Uint32, reflect.Uint64:
+			return keys[i].Uint() < keys[j].Uint()
+		default:
+			return keys[i].String() < keys[j].String()
+		}
+	})
+}
@@ -0,0 +1,369 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !purego,!appengine
+
+package impl
+
+import (
+	"reflect"
+	"sort"
+	"sync/atomic"
+
+	"github.com/golang/protobuf/v2/encoding/protowire"
+	"github.com/golang/protobuf/v2/internal/errors"
+	"github.com/golang/protobuf/v2/internal/strs"
+	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+	piface "github.com/golang/protobuf/v2/runtime/protoiface"
+)
+
+// coderMessageInfo contains per-message information used by the fast-path
+// functions. This is a different type from MessageType to keep MessageType
+// as general-purpose as possible.
+type coderMessageInfo struct {
+	orderedCoderFields []*coderFieldInfo
+	denseCoderFields   []*coderFieldInfo
+	coderFields        map[protowire.Number]*coderFieldInfo
+
+	unknownOffset   offset
+	hasUnknown      bool // whether unknownOffset is valid
+	sizecacheOffset offset
+	hasSizecache    bool // whether sizecacheOffset is valid
+	needsInitCheck  bool
+}
+
+type coderFieldInfo struct {
+	funcs      pointerCoderFuncs // fast-path per-field functions
+	num        protowire.Number  // field number
+	offset     offset            // struct field offset
+	wiretag    uint64            // field tag (number + wire type)
+	tagsize    int               // size of the varint-encoded tag
+	isRequired bool              // true if field is required
+	name       pref.FullName     // full name of the field
+}
+
+// pointerCoderFuncs is a set of pointer encoding functions.
+type pointerCoderFuncs struct {
+	size      func(p pointer, tagsize int, opts marshalOptions) int
+	marshal   func(b []byte, p pointer, wiretag uint64, opts marshalOptions) ([]byte, error)
+	unmarshal func(b []byte, p pointer, wtyp protowire.Type, opts unmarshalOptions) (int, error)
+	isInit    func(p pointer) error
+}
+
+// scalarCoderFuncs is the set of pointer encoding functions for the
+// Go representations of a scalar kind.
+type scalarCoderFuncs struct {
+	goType      reflect.Type
+	value       pointerCoderFuncs // T, always encoded
+	noZero      pointerCoderFuncs // T, zero value not encoded
+	ptr         pointerCoderFuncs // *T, nil not encoded ([]byte for bytes)
+	slice       pointerCoderFuncs // []T
+	packedSlice pointerCoderFuncs // []T, packed encoding
+}
+
+type marshalOptions piface.MarshalOptions
+type unmarshalOptions piface.UnmarshalOptions
+
+// errUnknown is used internally to indicate fields which should be added
+// to the unknown field set of a message. It is never returned from an exported
+// function.
+var errUnknown = errors.New("BUG: internal error (unknown)")
+
+// emptyBuf is the backing array of decoded bytes fields which are empty,
+// ensuring that they are non-nil.
+var emptyBuf [0]byte
+
+// toBytes returns the value of a decoded bytes field.
+func (o unmarshalOptions) toBytes(v []byte) []byte {
+	if o.AliasBuffer {
+		// Limit the capacity so that appending to the field
+		// does not overwrite the remainder of the input.
+		return v[:len(v):len(v)]
+	}
+	return append(emptyBuf[:], v...)
+}
+
+// toString returns the value of a decoded string field.
+func (o unmarshalOptions) toString(v []byte) string {
+	if o.AliasBuffer && o.AliasStrings {
+		return strs.UnsafeString(v)
+	}
+	return string(v)
+}
+
+func (mi *MessageType) makeCoderMethods(t reflect.Type, si structInfo) {
+	// The fast path does not support extensions or weak fields.
+	for _, name := range []string{"XXX_InternalExtensions", "XXX_extensions", "XXX_weak"} {
+		if _, ok := si.specialByName[name]; ok {
+			return
+		}
+	}
+
+	fields := mi.PBType.Fields()
+	coderFields := make(map[protowire.Number]*coderFieldInfo, fields.Len())
+	var orderedCoderFields []*coderFieldInfo
+	var needsInitCheck bool
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		var fs reflect.StructField
+		var funcs pointerCoderFuncs
+		var ok bool
+		switch {
+		case fd.IsWeak():
+		case fd.OneofType() != nil:
+			fs = si.oneofsByName[fd.OneofType().Name()]
+			funcs, ok = makeOneofFieldCoder(fd, fs, si.oneofWrappersByNumber[fd.Number()])
+		default:
+			fs = si.fieldsByNumber[fd.Number()]
+			funcs, ok = fieldCoder(fd, fs.Type)
+		}
+		if !ok {
+			// Use the reflective implementation for the entire message.
+			return
+		}
+		if fd.OneofType() == nil {
+			// The values of oneof members are checked by makeOneofFieldCoder.
+			funcs = makeUTF8Coder(fd, funcs)
+		}
+		funcs = makeClosedEnumCoder(fd, funcs)
+		wiretag := protowire.EncodeTag(fd.Number(), wireTypeOf(fd))
+		cf := &coderFieldInfo{
+			funcs:      funcs,
+			num:        fd.Number(),
+			offset:     offsetOf(fs),
+			wiretag:    wiretag,
+			tagsize:    protowire.SizeVarint(wiretag),
+			isRequired: fd.Cardinality() == pref.Required,
+			name:       fd.FullName(),
+		}
+		if cf.isRequired || cf.funcs.isInit != nil {
+			needsInitCheck = true
+		}
+		coderFields[cf.num] = cf
+		orderedCoderFields = append(orderedCoderFields, cf)
+	}
+	sort.Slice(orderedCoderFields, func(i, j int) bool {
+		return orderedCoderFields[i].num < orderedCoderFields[j].num
+	})
+
+	var maxDense protowire.Number
+	for _, cf := range orderedCoderFields {
+		if cf.num >= 16 && cf.num >= 2*maxDense {
+			break
+		}
+		maxDense = cf.num
+	}
+	denseCoderFields := make([]*coderFieldInfo, maxDense+1)
+	for _, cf := range orderedCoderFields {
+		if int(cf.num) >= len(denseCoderFields) {
+			break
+		}
+		denseCoderFields[cf.num] = cf
+	}
+
+	mi.coderMessageInfo = coderMessageInfo{
+		orderedCoderFields: orderedCoderFields,
+		denseCoderFields:   denseCoderFields,
+		coderFields:        coderFields,
+		needsInitCheck:     needsInitCheck,
+	}
+	if fu, ok := si.specialByName["XXX_unrecognized"]; ok && fu.Type == bytesType {
+		mi.unknownOffset = offsetOf(fu)
+		mi.hasUnknown = true
+	}
+	if fs, ok := si.specialByName["XXX_sizecache"]; ok && fs.Type.Kind() == reflect.Int32 {
+		mi.sizecacheOffset = offsetOf(fs)
+		mi.hasSizecache = true
+	}
+	mi.methods = &piface.Methods{
+		Flags:         piface.MethodFlagDeterministicMarshal,
+		MarshalAppend: mi.marshalAppend,
+		Size:          mi.size,
+		Unmarshal:     mi.unmarshal,
+		IsInitialized: mi.isInitialized,
+	}
+}
+
+// pointerOf returns a pointer to the message struct of m,
+// which must be a message of this type.
+func (mi *MessageType) pointerOf(m pref.ProtoMessage) pointer {
+	if w, ok := m.(*messageIfaceWrapper); ok {
+		return w.p
+	}
+	return pointerOfIface(m)
+}
+
+func (mi *MessageType) size(m pref.ProtoMessage, opts piface.MarshalOptions) int {
+	return mi.sizePointer(mi.pointerOf(m), marshalOptions(opts))
+}
+
+// sizePointer returns the size of the message pointed to by p.
+//
+// If the message has a size cache, the computed size is stored in it.
+// If opts.UseCachedSize is set, the previously cached size is returned
+// instead of being recomputed.
+func (mi *MessageType) sizePointer(p pointer, opts marshalOptions) (size int) {
+	if p.IsNil() {
+		return 0
+	}
+	if !mi.hasSizecache {
+		return mi.sizePointerSlow(p, opts)
+	}
+	// The size cache is accessed atomically, since it may be concurrently
+	// written by callers marshaling the same message.
+	sizecache := p.Apply(mi.sizecacheOffset).Int32()
+	if opts.UseCachedSize {
+		return int(atomic.LoadInt32(sizecache))
+	}
+	size = mi.sizePointerSlow(p, opts)
+	atomic.StoreInt32(sizecache, int32(size))
+	return size
+}
+
+func (mi *MessageType) sizePointerSlow(p pointer, opts marshalOptions) (size int) {
+	for _, f := range mi.orderedCoderFields {
+		size += f.funcs.size(p.Apply(f.offset), f.tagsize, opts)
+	}
+	if mi.hasUnknown {
+		size += len(*p.Apply(mi.unknownOffset).Bytes())
+	}
+	return size
+}
+
+func (mi *MessageType) marshalAppend(b []byte, m pref.ProtoMessage, opts piface.MarshalOptions) ([]byte, error) {
+	p := mi.pointerOf(m)
+	if !opts.UseCachedSize {
+		// Populate the size cache of the message and all its submessages,
+		// so that the length prefix of each nested message is computed
+		// only once.
+		mi.sizePointer(p, marshalOptions(opts))
+		opts.UseCachedSize = true
+	}
+	return mi.marshalAppendPointer(b, p, marshalOptions(opts))
+}
+
+func (mi *MessageType) marshalAppendPointer(b []byte, p pointer, opts marshalOptions) ([]byte, error) {
+	if p.IsNil() {
+		return b, nil
+	}
+	var err error
+	var nerr errors.NonFatal
+	for _, f := range mi.orderedCoderFields {
+		b, err = f.funcs.marshal(b, p.Apply(f.offset), f.wiretag, opts)
+		var ferr errors.NonFatal
+		if !ferr.Merge(err) {
+			return b, err
+		}
+		nerr.Merge(errors.AddField(ferr.E, string(f.name.Name())))
+	}
+	if mi.hasUnknown {
+		b = append(b, *p.Apply(mi.unknownOffset).Bytes()...)
+	}
+	return b, nerr.E
+}
+
+func (mi *MessageType) unmarshal(b []byte, m pref.ProtoMessage, opts piface.UnmarshalOptions) error {
+	if opts.MaxSize > 0 && len(b) > opts.MaxSize {
+		return errors.ErrMaxSize
+	}
+	if opts.RecursionLimit == 0 {
+		opts.RecursionLimit = protowire.DefaultRecursionLimit
+	}
+	return mi.unmarshalPointer(b, mi.pointerOf(m), unmarshalOptions(opts))
+}
+
+func (mi *MessageType) unmarshalPointer(b []byte, p pointer, opts unmarshalOptions) error {
+	opts.RecursionLimit--
+	if opts.RecursionLimit < 0 {
+		return errors.ErrRecursionLimit
+	}
+	var nerr errors.NonFatal
+	for pos := 0; len(b) > 0; {
+		// Parse the tag (field number and wire type).
+		num, wtyp, tagLen := protowire.ConsumeTag(b)
+		if tagLen < 0 {
+			return errors.AddOffset(protowire.ParseError(tagLen), pos)
+		}
+
+		// Parse the field value.
+		var f *coderFieldInfo
+		if int(num) < len(mi.denseCoderFields) {
+			f = mi.denseCoderFields[num]
+		} else {
+			f = mi.coderFields[num]
+		}
+		var err error
+		var valLen int
+		if f == nil {
+			err = errUnknown
+		} else {
+			valLen, err = f.funcs.unmarshal(b[tagLen:], p.Apply(f.offset), wtyp, opts)
+		}
+		if raw, ok := err.(unknownValues); ok {
+			// The record was decoded, save for some values of a closed enum.
+			if mi.hasUnknown && !opts.DiscardUnknown {
+				u := p.Apply(mi.unknownOffset).Bytes()
+				*u = append(*u, raw...)
+			}
+			err = nil
+		}
+		if err == errUnknown {
+			valLen = protowire.ConsumeFieldValueDepth(num, wtyp, b[tagLen:], opts.RecursionLimit)
+			if valLen < 0 {
+				return errors.AddOffset(protowire.ParseError(valLen), pos+tagLen)
+			}
+			if mi.hasUnknown && !opts.DiscardUnknown {
+				u := p.Apply(mi.unknownOffset).Bytes()
+				*u = append(*u, b[:tagLen+valLen]...)
+			}
+		} else if err != nil {
+			err = errors.AddField(errors.AddOffset(err, pos+tagLen), string(f.name.Name()))
+			if !nerr.Merge(err) {
+				return err
+			}
+		}
+		b = b[tagLen+valLen:]
+		pos += tagLen + valLen
+	}
+	return nerr.E
+}
+
+func (mi *MessageType) isInitialized(m pref.ProtoMessage) error {
+	return mi.isInitializedPointer(mi.pointerOf(m))
+}
+
+func (mi *MessageType) isInitializedPointer(p pointer) error {
+	if !mi.needsInitCheck {
+		return nil
+	}
+	for _, f := range mi.orderedCoderFields {
+		if !f.isRequired && f.funcs.isInit == nil {
+			continue
+		}
+		if p.IsNil() {
+			if f.isRequired {
+				return newRequiredNotSetError(f.name)
+			}
+			continue
+		}
+		fp := p.Apply(f.offset)
+		// Required fields are always represented by a pointer or a slice,
+		// which are nil when unset.
+		if f.isRequired && fp.Elem().IsNil() {
+			return newRequiredNotSetError(f.name)
+		}
+		if f.funcs.isInit != nil {
+			if err := f.funcs.isInit(fp); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func newRequiredNotSetError(name pref.FullName) error {
+	var nerr errors.NonFatal
+	nerr.AppendRequiredNotSet(string(name))
+	return nerr.E
+}
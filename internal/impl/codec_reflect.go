@@ -0,0 +1,15 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build purego appengine
+
+package impl
+
+import "reflect"
+
+// The fast-path codec operates directly on struct memory and is unavailable
+// without package unsafe. All operations use the reflective implementation.
+type coderMessageInfo struct{}
+
+func (mi *MessageType) makeCoderMethods(t reflect.Type, si structInfo) {}
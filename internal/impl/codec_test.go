@@ -0,0 +1,205 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !purego,!appengine
+
+package impl_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/v2/internal/encoding/pack"
+	"github.com/golang/protobuf/v2/internal/scalar"
+	"github.com/golang/protobuf/v2/proto"
+	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+	piface "github.com/golang/protobuf/v2/runtime/protoiface"
+
+	testpb "github.com/golang/protobuf/v2/internal/testprotos/test"
+	test3pb "github.com/golang/protobuf/v2/internal/testprotos/test3"
+)
+
+func TestCodecMethods(t *testing.T) {
+	nested := &testpb.TestAllTypes_NestedMessage{
+		A:           scalar.Int32(1),
+		Corecursive: &testpb.TestAllTypes{OptionalInt32: scalar.Int32(2)},
+	}
+	tests := []pref.ProtoMessage{
+		&testpb.TestAllTypes{},
+		&testpb.TestAllTypes{
+			OptionalInt32:         scalar.Int32(-1),
+			OptionalSint64:        scalar.Int64(-2),
+			OptionalFixed32:       scalar.Uint32(3),
+			OptionalFloat:         scalar.Float32(4.5),
+			OptionalDouble:        scalar.Float64(-5.5),
+			OptionalBool:          scalar.Bool(false),
+			OptionalString:        scalar.String(""),
+			OptionalBytes:         []byte{},
+			OptionalNestedEnum:    testpb.TestAllTypes_BAR.Enum(),
+			Optionalgroup:         &testpb.TestAllTypes_OptionalGroup{A: scalar.Int32(6)},
+			OptionalNestedMessage: nested,
+			RepeatedInt32:         []int32{1, -2, 3},
+			RepeatedFloat:         []float32{1, 2},
+			RepeatedString:        []string{"a", "", "c"},
+			RepeatedBytes:         [][]byte{[]byte("a"), {}},
+			RepeatedNestedEnum:    []testpb.TestAllTypes_NestedEnum{testpb.TestAllTypes_FOO, testpb.TestAllTypes_NEG},
+			Repeatedgroup:         []*testpb.TestAllTypes_RepeatedGroup{{A: scalar.Int32(7)}, {}},
+			RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{nested, {}},
+			MapInt32Int32:         map[int32]int32{1: 2, 0: 0},
+			MapStringString:       map[string]string{"a": "b", "": ""},
+			MapStringNestedEnum:   map[string]testpb.TestAllTypes_NestedEnum{"a": testpb.TestAllTypes_BAZ},
+			MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+				"a": nested,
+				"b": {},
+			},
+			OneofField: &testpb.TestAllTypes_OneofNestedMessage{nested},
+		},
+		&testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofUint32{0}},
+		&testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofBytes{[]byte("a")}},
+		&test3pb.TestAllTypes{},
+		&test3pb.TestAllTypes{
+			OptionalInt32:      -1,
+			OptionalString:     "a",
+			OptionalBytes:      []byte("b"),
+			OptionalNestedEnum: test3pb.TestAllTypes_BAR,
+			RepeatedSint32:     []int32{-1, 2},
+			RepeatedDouble:     []float64{1, 2},
+			MapUint64Uint64:    map[uint64]uint64{1: 2},
+			MapBoolBool:        map[bool]bool{true: false},
+		},
+		&testpb.TestRequiredForeign{
+			OptionalMessage: &testpb.TestRequired{RequiredField: scalar.Int32(1)},
+		},
+	}
+	for _, m := range tests {
+		methods := m.(piface.Methoder).XXX_Methods()
+		if methods == nil {
+			t.Errorf("%T: XXX_Methods() = nil, want fast-path methods", m)
+			continue
+		}
+		b, err := methods.MarshalAppend(nil, m, piface.MarshalOptions{Deterministic: true})
+		if err != nil {
+			t.Errorf("%T: MarshalAppend error: %v", m, err)
+			continue
+		}
+		if got, want := methods.Size(m, piface.MarshalOptions{}), len(b); got != want {
+			t.Errorf("%T: Size() = %v, want %v", m, got, want)
+		}
+		got := reflect.New(reflect.TypeOf(m).Elem()).Interface().(pref.ProtoMessage)
+		if err := methods.Unmarshal(b, got, piface.UnmarshalOptions{}); err != nil {
+			t.Errorf("%T: Unmarshal error: %v", m, err)
+			continue
+		}
+		if !proto.Equal(got, m) {
+			t.Errorf("%T: Unmarshal(MarshalAppend(m)) != m\ngot:  %v\nwant: %v", m, got, m)
+		}
+		if err := methods.IsInitialized(m); err != nil {
+			t.Errorf("%T: IsInitialized error: %v", m, err)
+		}
+	}
+}
+
+func TestCodecUnknownFields(t *testing.T) {
+	b := pack.Message{
+		pack.Tag{1, pack.VarintType}, pack.Varint(1),
+		pack.Tag{100000, pack.VarintType}, pack.Varint(2),
+		// Mismatched wire type for optional_int64.
+		pack.Tag{2, pack.Fixed32Type}, pack.Uint32(3),
+	}.Marshal()
+	want := pack.Message{
+		pack.Tag{100000, pack.VarintType}, pack.Varint(2),
+		pack.Tag{2, pack.Fixed32Type}, pack.Uint32(3),
+	}.Marshal()
+
+	m := &testpb.TestAllTypes{}
+	methods := m.XXX_Methods()
+	if err := methods.Unmarshal(b, m, piface.UnmarshalOptions{}); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if got := []byte(m.XXX_unrecognized); !reflect.DeepEqual(got, want) {
+		t.Errorf("unknown fields = %x, want %x", got, want)
+	}
+
+	m = &testpb.TestAllTypes{}
+	if err := methods.Unmarshal(b, m, piface.UnmarshalOptions{DiscardUnknown: true}); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if len(m.XXX_unrecognized) != 0 {
+		t.Errorf("unknown fields = %x, want none", m.XXX_unrecognized)
+	}
+}
+
+func TestCodecRequired(t *testing.T) {
+	for _, m := range []pref.ProtoMessage{
+		&testpb.TestRequired{},
+		&testpb.TestRequiredForeign{OptionalMessage: &testpb.TestRequired{}},
+		&testpb.TestRequiredForeign{RepeatedMessage: []*testpb.TestRequired{{}}},
+		&testpb.TestRequiredForeign{MapMessage: map[int32]*testpb.TestRequired{1: {}}},
+	} {
+		if err := m.(piface.Methoder).XXX_Methods().IsInitialized(m); err == nil {
+			t.Errorf("%T: IsInitialized(%v) = nil, want error", m, m)
+		}
+	}
+}
+
+func TestCodecUnsupported(t *testing.T) {
+	// Messages with extensions use the reflective implementation.
+	if methods := (&testpb.TestAllExtensions{}).XXX_Methods(); methods != nil {
+		t.Errorf("TestAllExtensions.XXX_Methods() = %v, want nil", methods)
+	}
+}
+
+func TestCodecSizeCache(t *testing.T) {
+	nested := &testpb.TestAllTypes_NestedMessage{
+		A:           scalar.Int32(1),
+		Corecursive: &testpb.TestAllTypes{OptionalString: scalar.String("abc")},
+	}
+	m := &testpb.TestAllTypes{OptionalNestedMessage: nested}
+	methods := m.XXX_Methods()
+	size := methods.Size(m, piface.MarshalOptions{})
+	if got := int(m.XXX_sizecache); got != size {
+		t.Errorf("XXX_sizecache = %v, want %v", got, size)
+	}
+	if got, want := nested.XXX_sizecache, int32(nested.XXX_Methods().Size(nested, piface.MarshalOptions{})); got != want {
+		t.Errorf("nested XXX_sizecache = %v, want %v", got, want)
+	}
+
+	// Marshal with UseCachedSize must produce the same output as without it.
+	want, err := methods.MarshalAppend(nil, m, piface.MarshalOptions{})
+	if err != nil {
+		t.Fatalf("MarshalAppend error: %v", err)
+	}
+	methods.Size(m, piface.MarshalOptions{})
+	got, err := methods.MarshalAppend(nil, m, piface.MarshalOptions{UseCachedSize: true})
+	if err != nil {
+		t.Fatalf("MarshalAppend error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MarshalAppend(UseCachedSize) = %x, want %x", got, want)
+	}
+
+	// Size with UseCachedSize returns the cached size without recomputing it,
+	// which the proto package relies on to presize the output of Marshal.
+	m.XXX_sizecache = 1000
+	if got := methods.Size(m, piface.MarshalOptions{UseCachedSize: true}); got != 1000 {
+		t.Errorf("Size(UseCachedSize) = %v, want cached size 1000", got)
+	}
+	if got := m.XXX_sizecache; got != 1000 {
+		t.Errorf("XXX_sizecache after Size(UseCachedSize) = %v, want 1000", got)
+	}
+
+	// Marshal without UseCachedSize must not use a stale size.
+	nested.Corecursive.OptionalString = scalar.String("abcdef")
+	b, err := methods.MarshalAppend(nil, m, piface.MarshalOptions{})
+	if err != nil {
+		t.Fatalf("MarshalAppend error: %v", err)
+	}
+	got2 := &testpb.TestAllTypes{}
+	if err := proto.Unmarshal(b, got2); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if !proto.Equal(got2, m) {
+		t.Errorf("Unmarshal(MarshalAppend(m)) = %v, want %v", got2, m)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !purego,!appengine
+
+package impl
+
+import (
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/v2/encoding/protowire"
+	"github.com/golang/protobuf/v2/internal/errors"
+	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+)
+
+// enforceUTF8 reports whether the values of fd must be valid UTF-8,
+// which is the case for string fields declared in proto3 files.
+func enforceUTF8(fd pref.FieldDescriptor) bool {
+	return fd.Kind() == pref.StringKind && fd.Syntax() == pref.Proto3
+}
+
+// makeUTF8Coder wraps the coder functions of a field so that marshaling or
+// unmarshaling a value which is not valid UTF-8 reports a non-fatal
+// InvalidUTF8Error. It returns funcs unchanged if fd does not require
+// valid UTF-8.
+//
+// The wrapped functions take a pointer to a string,
+// or to a []string if fd is repeated.
+func makeUTF8Coder(fd pref.FieldDescriptor, funcs pointerCoderFuncs) pointerCoderFuncs {
+	if !enforceUTF8(fd) {
+		return funcs
+	}
+	isRepeated := fd.Cardinality() == pref.Repeated
+	marshal, unmarshal := funcs.marshal, funcs.unmarshal
+	funcs.marshal = func(b []byte, p pointer, wiretag uint64, opts marshalOptions) ([]byte, error) {
+		var nerr errors.NonFatal
+		if isRepeated {
+			for i, v := range *p.StringSlice() {
+				if !utf8.ValidString(v) {
+					nerr.Merge(errors.AddIndex(&errors.InvalidUTF8Error{Offset: -1}, strconv.Itoa(i)))
+				}
+			}
+		} else if !utf8.ValidString(*p.String()) {
+			nerr.Merge(&errors.InvalidUTF8Error{Offset: -1})
+		}
+		b, err := marshal(b, p, wiretag, opts)
+		if err != nil {
+			return b, err
+		}
+		return b, nerr.E
+	}
+	funcs.unmarshal = func(b []byte, p pointer, wtyp protowire.Type, opts unmarshalOptions) (int, error) {
+		n, err := unmarshal(b, p, wtyp, opts)
+		if err != nil {
+			return n, err
+		}
+		v, _ := protowire.ConsumeBytes(b)
+		if utf8.Valid(v) {
+			return n, nil
+		}
+		err = &errors.InvalidUTF8Error{Offset: n - len(v)}
+		if isRepeated {
+			err = errors.AddIndex(err, strconv.Itoa(len(*p.StringSlice())-1))
+		}
+		return n, err
+	}
+	return funcs
+}
@@ -9,7 +9,7 @@ import (
 	"reflect"
 	"sort"
 
-	"github.com/golang/protobuf/v2/internal/encoding/wire"
+	"github.com/golang/protobuf/v2/encoding/protowire"
 	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
 )
 
@@ -126,7 +126,7 @@ func (fs *legacyUnknownBytes) Len() int {
 	b := *fs
 	m := map[pref.FieldNumber]bool{}
 	for len(b) > 0 {
-		num, _, n := wire.ConsumeField(b)
+		num, _, n := protowire.ConsumeField(b)
 		m[num] = true
 		b = b[n:]
 	}
@@ -137,7 +137,7 @@ func (fs *legacyUnknownBytes) Get(num pref.FieldNumber) (raw pref.RawFields) {
 	// Runtime complexity: O(n)
 	b := *fs
 	for len(b) > 0 {
-		num2, _, n := wire.ConsumeField(b)
+		num2, _, n := protowire.ConsumeField(b)
 		if num == num2 {
 			raw = append(raw, b[:n]...)
 		}
@@ -147,7 +147,7 @@ func (fs *legacyUnknownBytes) Get(num pref.FieldNumber) (raw pref.RawFields) {
 }
 
 func (fs *legacyUnknownBytes) Set(num pref.FieldNumber, raw pref.RawFields) {
-	num2, _, _ := wire.ConsumeTag(raw)
+	num2, _, _ := protowire.ConsumeTag(raw)
 	if len(raw) > 0 && (!raw.IsValid() || num != num2) {
 		panic("invalid raw fields")
 	}
@@ -157,7 +157,7 @@ func (fs *legacyUnknownBytes) Set(num pref.FieldNumber, raw pref.RawFields) {
 	b := *fs
 	out := (*fs)[:0]
 	for len(b) > 0 {
-		num2, _, n := wire.ConsumeField(b)
+		num2, _, n := protowire.ConsumeField(b)
 		if num != num2 {
 			out = append(out, b[:n]...)
 		}
@@ -184,7 +184,7 @@ func (fs *legacyUnknownBytes) Range(f func(pref.FieldNumber, pref.RawFields) boo
 	l := list.New()
 	m := map[pref.FieldNumber]*list.Element{}
 	for len(b) > 0 {
-		num, _, n := wire.ConsumeField(b)
+		num, _, n := protowire.ConsumeField(b)
 		if e, ok := m[num]; ok {
 			x := e.Value.(*entry)
 			x.raw = append(x.raw, b[:n]...)
@@ -37,6 +37,11 @@ type MessageType struct {
 
 	unknownFields   func(*messageDataType) pref.UnknownFields
 	extensionFields func(*messageDataType) pref.KnownFields
+
+	// methods is the set of fast-path methods for the message,
+	// or nil if the message does not support the fast path.
+	methods *piface.Methods
+	coderMessageInfo
 }
 
 func (mi *MessageType) init() {
@@ -46,44 +51,51 @@ func (mi *MessageType) init() {
 			panic(fmt.Sprintf("got %v, want *struct kind", t))
 		}
 
-		mi.makeKnownFieldsFunc(t.Elem())
+		si := mi.makeStructInfo(t.Elem())
+		mi.makeKnownFieldsFunc(si)
 		mi.makeUnknownFieldsFunc(t.Elem())
 		mi.makeExtensionFieldsFunc(t.Elem())
+		mi.makeCoderMethods(t.Elem(), si)
 	})
 }
 
-// makeKnownFieldsFunc generates functions for operations that can be performed
-// on each protobuf message field. It takes in a reflect.Type representing the
-// Go struct and matches message fields with struct fields.
-//
-// This code assumes that the struct is well-formed and panics if there are
-// any discrepancies.
-func (mi *MessageType) makeKnownFieldsFunc(t reflect.Type) {
-	// Generate a mapping of field numbers and names to Go struct field or type.
-	var (
-		fieldsByNumber        = map[pref.FieldNumber]reflect.StructField{}
-		oneofsByName          = map[pref.Name]reflect.StructField{}
-		oneofWrappersByType   = map[reflect.Type]pref.FieldNumber{}
-		oneofWrappersByNumber = map[pref.FieldNumber]reflect.Type{}
-		specialByName         = map[string]reflect.StructField{}
-	)
+// structInfo contains information about the Go struct type of a message,
+// matching message fields with struct fields.
+type structInfo struct {
+	fieldsByNumber        map[pref.FieldNumber]reflect.StructField
+	oneofsByName          map[pref.Name]reflect.StructField
+	oneofWrappersByType   map[reflect.Type]pref.FieldNumber
+	oneofWrappersByNumber map[pref.FieldNumber]reflect.Type
+	specialByName         map[string]reflect.StructField
+}
+
+// makeStructInfo generates a mapping of field numbers and names to
+// Go struct field or type.
+func (mi *MessageType) makeStructInfo(t reflect.Type) structInfo {
+	si := structInfo{
+		fieldsByNumber:        map[pref.FieldNumber]reflect.StructField{},
+		oneofsByName:          map[pref.Name]reflect.StructField{},
+		oneofWrappersByType:   map[reflect.Type]pref.FieldNumber{},
+		oneofWrappersByNumber: map[pref.FieldNumber]reflect.Type{},
+		specialByName:         map[string]reflect.StructField{},
+	}
 fieldLoop:
 	for i := 0; i < t.NumField(); i++ {
 		f := t.Field(i)
 		for _, s := range strings.Split(f.Tag.Get("protobuf"), ",") {
 			if len(s) > 0 && strings.Trim(s, "0123456789") == "" {
 				n, _ := strconv.ParseUint(s, 10, 64)
-				fieldsByNumber[pref.FieldNumber(n)] = f
+				si.fieldsByNumber[pref.FieldNumber(n)] = f
 				continue fieldLoop
 			}
 		}
 		if s := f.Tag.Get("protobuf_oneof"); len(s) > 0 {
-			oneofsByName[pref.Name(s)] = f
+			si.oneofsByName[pref.Name(s)] = f
 			continue fieldLoop
 		}
 		switch f.Name {
 		case "XXX_weak", "XXX_unrecognized", "XXX_sizecache", "XXX_extensions", "XXX_InternalExtensions":
-			specialByName[f.Name] = f
+			si.specialByName[f.Name] = f
 			continue fieldLoop
 		}
 	}
@@ -100,23 +112,32 @@ fieldLoop:
 		for _, s := range strings.Split(f.Tag.Get("protobuf"), ",") {
 			if len(s) > 0 && strings.Trim(s, "0123456789") == "" {
 				n, _ := strconv.ParseUint(s, 10, 64)
-				oneofWrappersByType[tf] = pref.FieldNumber(n)
-				oneofWrappersByNumber[pref.FieldNumber(n)] = tf
+				si.oneofWrappersByType[tf] = pref.FieldNumber(n)
+				si.oneofWrappersByNumber[pref.FieldNumber(n)] = tf
 				break
 			}
 		}
 	}
+	return si
+}
 
+// makeKnownFieldsFunc generates functions for operations that can be performed
+// on each protobuf message field. It takes in a reflect.Type representing the
+// Go struct and matches message fields with struct fields.
+//
+// This code assumes that the struct is well-formed and panics if there are
+// any discrepancies.
+func (mi *MessageType) makeKnownFieldsFunc(si structInfo) {
 	mi.fields = map[pref.FieldNumber]*fieldInfo{}
 	for i := 0; i < mi.PBType.Fields().Len(); i++ {
 		fd := mi.PBType.Fields().Get(i)
-		fs := fieldsByNumber[fd.Number()]
+		fs := si.fieldsByNumber[fd.Number()]
 		var fi fieldInfo
 		switch {
 		case fd.IsWeak():
-			fi = fieldInfoForWeak(fd, specialByName["XXX_weak"])
+			fi = fieldInfoForWeak(fd, si.specialByName["XXX_weak"])
 		case fd.OneofType() != nil:
-			fi = fieldInfoForOneof(fd, oneofsByName[fd.OneofType().Name()], oneofWrappersByNumber[fd.Number()])
+			fi = fieldInfoForOneof(fd, si.oneofsByName[fd.OneofType().Name()], si.oneofWrappersByNumber[fd.Number()])
 		case fd.IsMap():
 			fi = fieldInfoForMap(fd, fs)
 		case fd.Cardinality() == pref.Repeated:
@@ -132,7 +153,7 @@ fieldLoop:
 	mi.oneofs = map[pref.Name]*oneofInfo{}
 	for i := 0; i < mi.PBType.Oneofs().Len(); i++ {
 		od := mi.PBType.Oneofs().Get(i)
-		mi.oneofs[od.Name()] = makeOneofInfo(od, oneofsByName[od.Name()], oneofWrappersByType)
+		mi.oneofs[od.Name()] = makeOneofInfo(od, si.oneofsByName[od.Name()], si.oneofWrappersByType)
 	}
 }
 
@@ -161,7 +182,8 @@ func (mi *MessageType) MessageOf(p interface{}) pref.Message {
 }
 
 func (mi *MessageType) Methods() *piface.Methods {
-	return nil
+	mi.init()
+	return mi.methods
 }
 
 func (mi *MessageType) dataTypeOf(p interface{}) *messageDataType {
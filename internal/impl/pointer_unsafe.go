@@ -63,3 +63,51 @@ func (p pointer) AsIfaceOf(t reflect.Type) interface{} {
 	// TODO: Use tricky unsafe magic to directly create ifaceHeader.
 	return p.AsValueOf(t).Interface()
 }
+
+func (p pointer) Bool() *bool              { return (*bool)(p.p) }
+func (p pointer) BoolPtr() **bool          { return (**bool)(p.p) }
+func (p pointer) BoolSlice() *[]bool       { return (*[]bool)(p.p) }
+func (p pointer) Int32() *int32            { return (*int32)(p.p) }
+func (p pointer) Int32Ptr() **int32        { return (**int32)(p.p) }
+func (p pointer) Int32Slice() *[]int32     { return (*[]int32)(p.p) }
+func (p pointer) Int64() *int64            { return (*int64)(p.p) }
+func (p pointer) Int64Ptr() **int64        { return (**int64)(p.p) }
+func (p pointer) Int64Slice() *[]int64     { return (*[]int64)(p.p) }
+func (p pointer) Uint32() *uint32          { return (*uint32)(p.p) }
+func (p pointer) Uint32Ptr() **uint32      { return (**uint32)(p.p) }
+func (p pointer) Uint32Slice() *[]uint32   { return (*[]uint32)(p.p) }
+func (p pointer) Uint64() *uint64          { return (*uint64)(p.p) }
+func (p pointer) Uint64Ptr() **uint64      { return (**uint64)(p.p) }
+func (p pointer) Uint64Slice() *[]uint64   { return (*[]uint64)(p.p) }
+func (p pointer) Float32() *float32        { return (*float32)(p.p) }
+func (p pointer) Float32Ptr() **float32    { return (**float32)(p.p) }
+func (p pointer) Float32Slice() *[]float32 { return (*[]float32)(p.p) }
+func (p pointer) Float64() *float64        { return (*float64)(p.p) }
+func (p pointer) Float64Ptr() **float64    { return (**float64)(p.p) }
+func (p pointer) Float64Slice() *[]float64 { return (*[]float64)(p.p) }
+func (p pointer) String() *string          { return (*string)(p.p) }
+func (p pointer) StringPtr() **string      { return (**string)(p.p) }
+func (p pointer) StringSlice() *[]string   { return (*[]string)(p.p) }
+func (p pointer) Bytes() *[]byte           { return (*[]byte)(p.p) }
+func (p pointer) BytesSlice() *[][]byte    { return (*[][]byte)(p.p) }
+
+// Elem returns the pointer stored at the location p points to.
+func (p pointer) Elem() pointer {
+	return pointer{p: *(*unsafe.Pointer)(p.p)}
+}
+
+// SetPointer stores v at the location p points to.
+func (p pointer) SetPointer(v pointer) {
+	*(*unsafe.Pointer)(p.p) = v.p
+}
+
+// PointerSlice treats p as a pointer to a slice of pointers
+// (e.g., *[]*T) and returns the slice.
+func (p pointer) PointerSlice() []pointer {
+	return *(*[]pointer)(p.p)
+}
+
+// AppendPointerSlice appends v to the slice of pointers p points to.
+func (p pointer) AppendPointerSlice(v pointer) {
+	*(*[]pointer)(p.p) = append(*(*[]pointer)(p.p), v)
+}
@@ -5,7 +5,7 @@
 package legacy
 
 import (
-	"github.com/golang/protobuf/v2/internal/encoding/wire"
+	"github.com/golang/protobuf/v2/encoding/protowire"
 	"github.com/golang/protobuf/v2/internal/fieldnum"
 )
 
@@ -27,12 +27,12 @@ func (fd fileDescriptorProto) GetPackage() string { return fd.Package }
 func parseFileDescProto(b []byte) *fileDescriptorProto {
 	fd := &fileDescriptorProto{}
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		parseCheck(n)
 		b = b[n:]
 		switch typ {
-		case wire.BytesType:
-			v, n := wire.ConsumeBytes(b)
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
 			b = b[n:]
 			switch num {
 			case fieldnum.FileDescriptorProto_Syntax:
@@ -45,7 +45,7 @@ func parseFileDescProto(b []byte) *fileDescriptorProto {
 				fd.MessageType = append(fd.MessageType, parseDescProto(v))
 			}
 		default:
-			n := wire.ConsumeFieldValue(num, typ, b)
+			n := protowire.ConsumeFieldValue(num, typ, b)
 			parseCheck(n)
 			b = b[n:]
 		}
@@ -64,12 +64,12 @@ func (md descriptorProto) GetName() string { return md.Name }
 func parseDescProto(b []byte) *descriptorProto {
 	md := &descriptorProto{}
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		parseCheck(n)
 		b = b[n:]
 		switch typ {
-		case wire.BytesType:
-			v, n := wire.ConsumeBytes(b)
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
 			parseCheck(n)
 			b = b[n:]
 			switch num {
@@ -81,7 +81,7 @@ func parseDescProto(b []byte) *descriptorProto {
 				md.EnumType = append(md.EnumType, parseEnumDescProto(v))
 			}
 		default:
-			n := wire.ConsumeFieldValue(num, typ, b)
+			n := protowire.ConsumeFieldValue(num, typ, b)
 			parseCheck(n)
 			b = b[n:]
 		}
@@ -99,12 +99,12 @@ func (ed enumDescriptorProto) GetName() string { return ed.Name }
 func parseEnumDescProto(b []byte) *enumDescriptorProto {
 	ed := &enumDescriptorProto{}
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		parseCheck(n)
 		b = b[n:]
 		switch typ {
-		case wire.BytesType:
-			v, n := wire.ConsumeBytes(b)
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
 			parseCheck(n)
 			b = b[n:]
 			switch num {
@@ -114,7 +114,7 @@ func parseEnumDescProto(b []byte) *enumDescriptorProto {
 				ed.Value = append(ed.Value, parseEnumValueDescProto(v))
 			}
 		default:
-			n := wire.ConsumeFieldValue(num, typ, b)
+			n := protowire.ConsumeFieldValue(num, typ, b)
 			parseCheck(n)
 			b = b[n:]
 		}
@@ -133,20 +133,20 @@ func (ed enumValueDescriptorProto) GetNumber() int32 { return ed.Number }
 func parseEnumValueDescProto(b []byte) *enumValueDescriptorProto {
 	vd := &enumValueDescriptorProto{}
 	for len(b) > 0 {
-		num, typ, n := wire.ConsumeTag(b)
+		num, typ, n := protowire.ConsumeTag(b)
 		parseCheck(n)
 		b = b[n:]
 		switch typ {
-		case wire.VarintType:
-			v, n := wire.ConsumeVarint(b)
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
 			parseCheck(n)
 			b = b[n:]
 			switch num {
 			case fieldnum.EnumValueDescriptorProto_Number:
 				vd.Number = int32(v)
 			}
-		case wire.BytesType:
-			v, n := wire.ConsumeBytes(b)
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
 			parseCheck(n)
 			b = b[n:]
 			switch num {
@@ -154,7 +154,7 @@ func parseEnumValueDescProto(b []byte) *enumValueDescriptorProto {
 				vd.Name = string(v)
 			}
 		default:
-			n := wire.ConsumeFieldValue(num, typ, b)
+			n := protowire.ConsumeFieldValue(num, typ, b)
 			parseCheck(n)
 			b = b[n:]
 		}
@@ -164,6 +164,6 @@ func parseEnumValueDescProto(b []byte) *enumValueDescriptorProto {
 
 func parseCheck(n int) {
 	if n < 0 {
-		panic(wire.ParseError(n))
+		panic(protowire.ParseError(n))
 	}
 }
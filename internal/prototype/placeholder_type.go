@@ -13,18 +13,19 @@ import (
 )
 
 var (
-	emptyFiles       fileImports
-	emptyMessages    messages
-	emptyFields      fields
-	emptyOneofs      oneofs
-	emptyNames       names
-	emptyNumbers     numbers
-	emptyFieldRanges fieldRanges
-	emptyEnums       enums
-	emptyEnumValues  enumValues
-	emptyEnumRanges  enumRanges
-	emptyExtensions  extensions
-	emptyServices    services
+	emptyFiles           fileImports
+	emptySourceLocations sourceLocations
+	emptyMessages        messages
+	emptyFields          fields
+	emptyOneofs          oneofs
+	emptyNames           names
+	emptyNumbers         numbers
+	emptyFieldRanges     fieldRanges
+	emptyEnums           enums
+	emptyEnumValues      enumValues
+	emptyEnumRanges      enumRanges
+	emptyExtensions      extensions
+	emptyServices        services
 )
 
 type placeholderName pref.FullName
@@ -51,6 +52,7 @@ func (t placeholderFile) Messages() pref.MessageDescriptors              { retur
 func (t placeholderFile) Extensions() pref.ExtensionDescriptors          { return &emptyExtensions }
 func (t placeholderFile) Services() pref.ServiceDescriptors              { return &emptyServices }
 func (t placeholderFile) DescriptorByName(pref.FullName) pref.Descriptor { return nil }
+func (t placeholderFile) SourceLocations() pref.SourceLocations          { return &emptySourceLocations }
 func (t placeholderFile) Format(s fmt.State, r rune)                     { pfmt.FormatDesc(s, r, t) }
 func (t placeholderFile) ProtoType(pref.FileDescriptor)                  {}
 
@@ -54,6 +54,10 @@ type File struct {
 	Extensions []Extension
 	Services   []Service
 
+	// SourceLocations are the locations of the declarations in the file,
+	// as recorded in the google.protobuf.SourceCodeInfo message.
+	SourceLocations []protoreflect.SourceLocation
+
 	*fileMeta
 }
 
@@ -92,6 +96,13 @@ func NewFile(t *File) (protoreflect.FileDescriptor, error) {
 	return ft, nil
 }
 
+// NewSourceLocations returns a protoreflect.SourceLocations for the list ls
+// of source locations within the file fd.
+// The caller must not mutate ls after the call.
+func NewSourceLocations(fd protoreflect.FileDescriptor, ls []protoreflect.SourceLocation) protoreflect.SourceLocations {
+	return new(sourceLocationsMeta).lazyInit(fd, ls)
+}
+
 func visitMessages(d interface {
 	Enums() protoreflect.EnumDescriptors
 	Messages() protoreflect.MessageDescriptors
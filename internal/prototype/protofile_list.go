@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/golang/protobuf/v2/internal/fieldnum"
 	pragma "github.com/golang/protobuf/v2/internal/pragma"
 	pset "github.com/golang/protobuf/v2/internal/set"
 	pfmt "github.com/golang/protobuf/v2/internal/typefmt"
@@ -129,3 +130,96 @@ func (p *oneofFields) ByJSONName(s string) pref.FieldDescriptor         { return
 func (p *oneofFields) ByNumber(n pref.FieldNumber) pref.FieldDescriptor { return p.byNum[n] }
 func (p *oneofFields) Format(s fmt.State, r rune)                       { pfmt.FormatList(s, r, p) }
 func (p *oneofFields) ProtoInternal(pragma.DoNotImplement)              {}
+
+type sourceLocationsMeta struct {
+	once   sync.Once
+	file   pref.FileDescriptor
+	list   []pref.SourceLocation
+	byPath map[string]int // index into list by the path of each location
+}
+type sourceLocations sourceLocationsMeta
+
+func (p *sourceLocationsMeta) lazyInit(fd pref.FileDescriptor, ls []pref.SourceLocation) *sourceLocations {
+	p.once.Do(func() {
+		p.file = fd
+		p.list = ls
+		if len(ls) > 0 {
+			p.byPath = make(map[string]int, len(ls))
+			for i := len(ls) - 1; i >= 0; i-- {
+				p.byPath[pathKey(ls[i].Path)] = i // first location wins
+			}
+		}
+	})
+	return (*sourceLocations)(p)
+}
+func (p *sourceLocations) Len() int                      { return len(p.list) }
+func (p *sourceLocations) Get(i int) pref.SourceLocation { return p.list[i] }
+func (p *sourceLocations) ByDescriptor(d pref.Descriptor) pref.SourceLocation {
+	fd, path := sourcePath(d)
+	if fd == nil || fd != p.file {
+		return pref.SourceLocation{}
+	}
+	if i, ok := p.byPath[pathKey(path)]; ok {
+		return p.list[i]
+	}
+	return pref.SourceLocation{}
+}
+func (p *sourceLocations) ProtoInternal(pragma.DoNotImplement) {}
+
+// pathKey returns a map key for a source path.
+func pathKey(p pref.SourcePath) string {
+	b := make([]byte, 0, 4*len(p))
+	for _, n := range p {
+		b = append(b, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	return string(b)
+}
+
+// sourcePath returns the file in which d is declared and the path of the
+// declaration within that file, which is empty for the file itself.
+// The file is nil if d is not a declaration within a file.
+func sourcePath(d pref.Descriptor) (pref.FileDescriptor, pref.SourcePath) {
+	parent, ok := d.Parent()
+	if !ok {
+		fd, _ := d.(pref.FileDescriptor)
+		return fd, nil
+	}
+	fd, path := sourcePath(parent)
+	if fd == nil {
+		return nil, nil
+	}
+	_, inFile := parent.(pref.FileDescriptor)
+	var num int32
+	switch d := d.(type) {
+	case pref.MessageDescriptor:
+		num = fieldnum.DescriptorProto_NestedType
+		if inFile {
+			num = fieldnum.FileDescriptorProto_MessageType
+		}
+	case pref.EnumDescriptor:
+		num = fieldnum.DescriptorProto_EnumType
+		if inFile {
+			num = fieldnum.FileDescriptorProto_EnumType
+		}
+	case pref.FieldDescriptor:
+		switch {
+		case d.ExtendedType() == nil:
+			num = fieldnum.DescriptorProto_Field
+		case inFile:
+			num = fieldnum.FileDescriptorProto_Extension
+		default:
+			num = fieldnum.DescriptorProto_Extension
+		}
+	case pref.OneofDescriptor:
+		num = fieldnum.DescriptorProto_OneofDecl
+	case pref.EnumValueDescriptor:
+		num = fieldnum.EnumDescriptorProto_Value
+	case pref.ServiceDescriptor:
+		num = fieldnum.FileDescriptorProto_Service
+	case pref.MethodDescriptor:
+		num = fieldnum.ServiceDescriptorProto_Method
+	default:
+		return nil, nil
+	}
+	return fd, append(path[:len(path):len(path)], num, int32(d.Index()))
+}
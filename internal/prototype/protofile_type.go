@@ -44,6 +44,7 @@ type fileMeta struct {
 	xs extensionsMeta
 	ss servicesMeta
 	ds descriptorsMeta
+	ls sourceLocationsMeta
 }
 type fileDesc struct{ f *File }
 
@@ -77,6 +78,7 @@ func (t fileDesc) Messages() pref.MessageDescriptors                { return t.f
 func (t fileDesc) Extensions() pref.ExtensionDescriptors            { return t.f.xs.lazyInit(t, t.f.Extensions) }
 func (t fileDesc) Services() pref.ServiceDescriptors                { return t.f.ss.lazyInit(t, t.f.Services) }
 func (t fileDesc) DescriptorByName(s pref.FullName) pref.Descriptor { return t.f.ds.lookup(t, s) }
+func (t fileDesc) SourceLocations() pref.SourceLocations            { return t.f.ls.lazyInit(t, t.f.SourceLocations) }
 func (t fileDesc) Format(s fmt.State, r rune)                       { pfmt.FormatDesc(s, r, t) }
 func (t fileDesc) ProtoType(pref.FileDescriptor)                    {}
 func (t fileDesc) ProtoInternal(pragma.DoNotImplement)              {}
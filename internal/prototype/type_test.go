@@ -904,3 +904,145 @@ func compactMultiFormat(s string) string {
 	}
 	return string(b)
 }
+
+func TestSourceLocations(t *testing.T) {
+	sci := &descriptorpb.SourceCodeInfo{
+		Location: []*descriptorpb.SourceCodeInfo_Location{{
+			Span: []int32{0, 0, 12, 1},
+		}, {
+			Path:                    []int32{4, 0},
+			Span:                    []int32{4, 0, 8, 1},
+			LeadingComments:         scalar.String(" M comment\n"),
+			LeadingDetachedComments: []string{" detached\n"},
+		}, {
+			Path:             []int32{4, 0, 2, 0},
+			Span:             []int32{5, 2, 20},
+			TrailingComments: scalar.String(" f comment\n"),
+		}, {
+			Path: []int32{4, 0, 4, 0, 2, 0},
+			Span: []int32{6, 14, 20},
+		}, {
+			Path: []int32{7, 0},
+			Span: []int32{9, 2, 28},
+		}, {
+			Path:            []int32{6, 0, 2, 0},
+			Span:            []int32{11, 2, 30},
+			LeadingComments: scalar.String(" method comment\n"),
+		}},
+	}
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:    scalar.String("test.proto"),
+		Package: scalar.String("test"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: scalar.String("M"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:   scalar.String("f"),
+				Number: scalar.Int32(1),
+				Label:  descriptorpb.FieldDescriptorProto_Label(pref.Optional).Enum(),
+				Type:   descriptorpb.FieldDescriptorProto_Type(pref.Int32Kind).Enum(),
+			}},
+			EnumType: []*descriptorpb.EnumDescriptorProto{{
+				Name:  scalar.String("E"),
+				Value: []*descriptorpb.EnumValueDescriptorProto{{Name: scalar.String("V"), Number: scalar.Int32(0)}},
+			}},
+			ExtensionRange: []*descriptorpb.DescriptorProto_ExtensionRange{
+				{Start: scalar.Int32(100), End: scalar.Int32(200)},
+			},
+		}},
+		Extension: []*descriptorpb.FieldDescriptorProto{{
+			Name:     scalar.String("X"),
+			Number:   scalar.Int32(100),
+			Label:    descriptorpb.FieldDescriptorProto_Label(pref.Optional).Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_Type(pref.Int32Kind).Enum(),
+			Extendee: scalar.String(".test.M"),
+		}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: scalar.String("S"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       scalar.String("Do"),
+				InputType:  scalar.String(".test.M"),
+				OutputType: scalar.String(".test.M"),
+			}},
+		}},
+		SourceCodeInfo: sci,
+	}
+	fd, err := pdesc.NewFile(fdp, nil)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile() error: %v", err)
+	}
+	other, err := pdesc.NewFile(fdp, nil)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile() error: %v", err)
+	}
+
+	locs := fd.SourceLocations()
+	if got, want := locs.Len(), len(sci.Location); got != want {
+		t.Errorf("SourceLocations().Len() = %v, want %v", got, want)
+	}
+	m := fd.Messages().Get(0)
+	for _, test := range []struct {
+		desc pref.Descriptor
+		want pref.SourceLocation
+	}{{
+		desc: fd,
+		want: pref.SourceLocation{Path: pref.SourcePath{}, EndLine: 12, EndColumn: 1},
+	}, {
+		desc: m,
+		want: pref.SourceLocation{
+			Path:                    pref.SourcePath{4, 0},
+			StartLine:               4,
+			EndLine:                 8,
+			EndColumn:               1,
+			LeadingComments:         " M comment\n",
+			LeadingDetachedComments: []string{" detached\n"},
+		},
+	}, {
+		desc: m.Fields().Get(0),
+		want: pref.SourceLocation{
+			Path:             pref.SourcePath{4, 0, 2, 0},
+			StartLine:        5,
+			StartColumn:      2,
+			EndLine:          5,
+			EndColumn:        20,
+			TrailingComments: " f comment\n",
+		},
+	}, {
+		desc: m.Enums().Get(0),
+		want: pref.SourceLocation{},
+	}, {
+		desc: m.Enums().Get(0).Values().Get(0),
+		want: pref.SourceLocation{Path: pref.SourcePath{4, 0, 4, 0, 2, 0}, StartLine: 6, StartColumn: 14, EndLine: 6, EndColumn: 20},
+	}, {
+		desc: fd.Extensions().Get(0),
+		want: pref.SourceLocation{Path: pref.SourcePath{7, 0}, StartLine: 9, StartColumn: 2, EndLine: 9, EndColumn: 28},
+	}, {
+		desc: fd.Services().Get(0).Methods().Get(0),
+		want: pref.SourceLocation{
+			Path:            pref.SourcePath{6, 0, 2, 0},
+			StartLine:       11,
+			StartColumn:     2,
+			EndLine:         11,
+			EndColumn:       30,
+			LeadingComments: " method comment\n",
+		},
+	}, {
+		// A declaration at the same path in another file has no location.
+		desc: other.Messages().Get(0),
+		want: pref.SourceLocation{},
+	}} {
+		if got := locs.ByDescriptor(test.desc); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ByDescriptor(%v) = %+v, want %+v", test.desc.FullName(), got, test.want)
+		}
+	}
+
+	if got := pdesc.ToFileDescriptorProto(fd).GetSourceCodeInfo(); !protoV1.Equal(got, sci) {
+		t.Errorf("ToFileDescriptorProto() source code info mismatch:\ngot  %v\nwant %v", got, sci)
+	}
+
+	fdp.SourceCodeInfo = &descriptorpb.SourceCodeInfo{
+		Location: []*descriptorpb.SourceCodeInfo_Location{{Path: []int32{4, 0}, Span: []int32{1, 2}}},
+	}
+	if _, err := pdesc.NewFile(fdp, nil); err == nil {
+		t.Errorf("protodesc.NewFile() with invalid span succeeded, want error")
+	}
+}
@@ -0,0 +1,14 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build purego appengine
+
+// Package strs provides string manipulation functionality specific to protobuf.
+package strs
+
+// UnsafeString returns a copy of b as a string, since the memory of b
+// cannot be shared without package unsafe.
+func UnsafeString(b []byte) string {
+	return string(b)
+}
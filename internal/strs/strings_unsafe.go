@@ -0,0 +1,19 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !purego,!appengine
+
+// Package strs provides string manipulation functionality specific to protobuf.
+package strs
+
+import "unsafe"
+
+// UnsafeString returns a string which shares the underlying memory of b.
+// The caller must not modify b for as long as the string is in use.
+func UnsafeString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	return *(*string)(unsafe.Pointer(&b))
+}
@@ -11,7 +11,10 @@ import (
 	"testing"
 
 	protoV1 "github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/v2/internal/scalar"
 	"github.com/golang/protobuf/v2/proto"
+
+	testpb "github.com/golang/protobuf/v2/internal/testprotos/test"
 )
 
 // The results of these microbenchmarks are unlikely to correspond well
@@ -73,3 +76,58 @@ func BenchmarkDecode(b *testing.B) {
 		}
 	}
 }
+
+// BenchmarkEncodeDeep benchmarks encoding deeply nested messages,
+// where every nested message must be prefixed by its length.
+func BenchmarkEncodeDeep(b *testing.B) {
+	for _, depth := range []int{1, 10, 100} {
+		m := deepMessage(depth)
+		b.Run(fmt.Sprintf("depth=%d/Marshal", depth), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := proto.Marshal(m); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("depth=%d/UseCachedSize", depth), func(b *testing.B) {
+			opts := proto.MarshalOptions{UseCachedSize: true}
+			buf := make([]byte, 0, proto.Size(m))
+			for i := 0; i < b.N; i++ {
+				if _, err := opts.MarshalAppend(buf[:0], m); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("depth=%d/SizeAndUseCachedSize", depth), func(b *testing.B) {
+			// Size followed by Marshal with UseCachedSize computes the size
+			// only once, and so performs equivalently to Marshal alone.
+			opts := proto.MarshalOptions{UseCachedSize: true}
+			for i := 0; i < b.N; i++ {
+				buf := make([]byte, 0, proto.Size(m))
+				if _, err := opts.MarshalAppend(buf, m); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("depth=%d/Size", depth), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				proto.Size(m)
+			}
+		})
+	}
+}
+
+// deepMessage returns a message with depth levels of nested messages.
+func deepMessage(depth int) *testpb.TestAllTypes {
+	m := &testpb.TestAllTypes{OptionalString: scalar.String("leaf")}
+	for i := 0; i < depth; i++ {
+		m = &testpb.TestAllTypes{
+			OptionalInt32: scalar.Int32(int32(i)),
+			OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+				A:           scalar.Int32(int32(i)),
+				Corecursive: m,
+			},
+		}
+	}
+	return m
+}
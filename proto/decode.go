@@ -5,10 +5,16 @@
 package proto
 
 import (
-	"github.com/golang/protobuf/v2/internal/encoding/wire"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/v2/encoding/protowire"
 	"github.com/golang/protobuf/v2/internal/errors"
 	"github.com/golang/protobuf/v2/internal/pragma"
+	"github.com/golang/protobuf/v2/internal/strs"
 	"github.com/golang/protobuf/v2/reflect/protoreflect"
+	"github.com/golang/protobuf/v2/reflect/protoregistry"
 	"github.com/golang/protobuf/v2/runtime/protoiface"
 )
 
@@ -25,11 +31,58 @@ type UnmarshalOptions struct {
 	// If DiscardUnknown is set, unknown fields are ignored.
 	DiscardUnknown bool
 
+	// Merge merges the input into the destination message.
+	// If Merge is false (the default), the message is reset before
+	// unmarshaling, as if by calling Reset.
+	Merge bool
+
+	// Resolver is the registry used to look up extension fields which are
+	// not already known to the message being unmarshaled. Extensions found
+	// in the registry are registered with the message and decoded; others
+	// are treated as unknown fields. If Resolver is nil,
+	// protoregistry.GlobalTypes is used.
+	Resolver *protoregistry.Types
+
+	// RecursionLimit limits how deeply messages and groups may be nested.
+	// Unmarshal returns a RecursionLimitError if the limit is exceeded.
+	// If zero, DefaultRecursionLimit is used.
+	RecursionLimit int
+
+	// MaxSize limits the size in bytes of the input.
+	// Unmarshal returns ErrMaxSize if the limit is exceeded.
+	// If zero, the size of the input is not limited.
+	MaxSize int
+
+	// AliasBuffer permits bytes fields of the message to alias the input
+	// buffer rather than copying it. If set, the caller must not modify
+	// the input buffer for as long as the message is in use.
+	AliasBuffer bool
+
+	// AliasStrings additionally permits string fields of the message to
+	// alias the input buffer. It has no effect unless AliasBuffer is also set.
+	// Implementations which cannot share memory between a string and
+	// a []byte (e.g., when built without package unsafe) copy instead.
+	AliasStrings bool
+
 	pragma.NoUnkeyedLiterals
 }
 
 var _ = protoiface.UnmarshalOptions(UnmarshalOptions{})
 
+// DefaultRecursionLimit is the default maximum nesting depth of messages
+// and groups accepted by Unmarshal.
+const DefaultRecursionLimit = protowire.DefaultRecursionLimit
+
+var (
+	// ErrRecursionLimit is returned by Unmarshal when the input contains
+	// messages or groups nested more deeply than the recursion limit.
+	ErrRecursionLimit = errors.ErrRecursionLimit
+
+	// ErrMaxSize is returned by Unmarshal when the input is larger than
+	// the maximum size.
+	ErrMaxSize = errors.ErrMaxSize
+)
+
 // Unmarshal parses the wire-format message in b and places the result in m.
 func Unmarshal(b []byte, m Message) error {
 	return UnmarshalOptions{}.Unmarshal(b, m)
@@ -37,7 +90,18 @@ func Unmarshal(b []byte, m Message) error {
 
 // Unmarshal parses the wire-format message in b and places the result in m.
 func (o UnmarshalOptions) Unmarshal(b []byte, m Message) error {
-	// TODO: Reset m?
+	if o.MaxSize > 0 && len(b) > o.MaxSize {
+		return ErrMaxSize
+	}
+	if o.RecursionLimit == 0 {
+		o.RecursionLimit = DefaultRecursionLimit
+	}
+	if o.Resolver == nil {
+		o.Resolver = protoregistry.GlobalTypes
+	}
+	if !o.Merge {
+		Reset(m)
+	}
 	err := o.unmarshalMessageFast(b, m)
 	if err == errInternalNoFast {
 		err = o.unmarshalMessage(b, m.ProtoReflect())
@@ -61,16 +125,20 @@ func (o UnmarshalOptions) unmarshalMessageFast(b []byte, m Message) error {
 }
 
 func (o UnmarshalOptions) unmarshalMessage(b []byte, m protoreflect.Message) error {
+	o.RecursionLimit--
+	if o.RecursionLimit < 0 {
+		return ErrRecursionLimit
+	}
 	messageType := m.Type()
 	fieldTypes := messageType.Fields()
 	knownFields := m.KnownFields()
 	unknownFields := m.UnknownFields()
 	var nerr errors.NonFatal
-	for len(b) > 0 {
+	for pos := 0; len(b) > 0; {
 		// Parse the tag (field number and wire type).
-		num, wtyp, tagLen := wire.ConsumeTag(b)
+		num, wtyp, tagLen := protowire.ConsumeTag(b)
 		if tagLen < 0 {
-			return wire.ParseError(tagLen)
+			return errors.AddOffset(protowire.ParseError(tagLen), pos)
 		}
 
 		// Parse the field value.
@@ -78,6 +146,16 @@ func (o UnmarshalOptions) unmarshalMessage(b []byte, m protoreflect.Message) err
 		if fieldType == nil {
 			fieldType = knownFields.ExtensionTypes().ByNumber(num)
 		}
+		if fieldType == nil && messageType.ExtensionRanges().Has(num) {
+			xt, err := o.Resolver.FindExtensionByNumber(messageType.FullName(), num)
+			if err != nil && err != protoregistry.NotFound {
+				return errors.AddOffset(errors.New("unable to resolve extension %v: %v", num, err), pos)
+			}
+			if xt != nil {
+				knownFields.ExtensionTypes().Register(xt)
+				fieldType = xt
+			}
+		}
 		var err error
 		var valLen int
 		switch {
@@ -86,29 +164,51 @@ func (o UnmarshalOptions) unmarshalMessage(b []byte, m protoreflect.Message) err
 		case fieldType.Cardinality() != protoreflect.Repeated:
 			valLen, err = o.unmarshalScalarField(b[tagLen:], wtyp, num, knownFields, fieldType)
 		case !fieldType.IsMap():
-			valLen, err = o.unmarshalList(b[tagLen:], wtyp, num, knownFields.Get(num).List(), fieldType.Kind())
+			list := knownFields.Get(num).List()
+			if values := closedEnumValues(fieldType); values != nil {
+				var raw []byte
+				valLen, raw, err = o.unmarshalClosedEnumList(b[tagLen:], wtyp, num, list, values)
+				if raw != nil && !o.DiscardUnknown {
+					unknownFields.Set(num, append(unknownFields.Get(num), raw...))
+				}
+			} else {
+				valLen, err = o.unmarshalList(b[tagLen:], wtyp, num, list, fieldType.Kind())
+				if err == nil && enforceUTF8(fieldType) {
+					i := list.Len() - 1
+					err = errors.AddIndex(checkUTF8(fieldType, list.Get(i), valLen), strconv.Itoa(i))
+				}
+			}
 		default:
 			valLen, err = o.unmarshalMap(b[tagLen:], wtyp, num, knownFields.Get(num).Map(), fieldType)
 		}
 		if err == errUnknown {
-			valLen = wire.ConsumeFieldValue(num, wtyp, b[tagLen:])
+			valLen = protowire.ConsumeFieldValueDepth(num, wtyp, b[tagLen:], o.RecursionLimit)
 			if valLen < 0 {
-				return wire.ParseError(valLen)
+				return errors.AddOffset(protowire.ParseError(valLen), pos+tagLen)
+			}
+			if !o.DiscardUnknown {
+				unknownFields.Set(num, append(unknownFields.Get(num), b[:tagLen+valLen]...))
+			}
+		} else if err != nil {
+			err = errors.AddField(errors.AddOffset(err, pos+tagLen), fieldName(fieldType))
+			if !nerr.Merge(err) {
+				return err
 			}
-			unknownFields.Set(num, append(unknownFields.Get(num), b[:tagLen+valLen]...))
-		} else if !nerr.Merge(err) {
-			return err
 		}
 		b = b[tagLen+valLen:]
+		pos += tagLen + valLen
 	}
 	return nerr.E
 }
 
-func (o UnmarshalOptions) unmarshalScalarField(b []byte, wtyp wire.Type, num wire.Number, knownFields protoreflect.KnownFields, field protoreflect.FieldDescriptor) (n int, err error) {
+func (o UnmarshalOptions) unmarshalScalarField(b []byte, wtyp protowire.Type, num protowire.Number, knownFields protoreflect.KnownFields, field protoreflect.FieldDescriptor) (n int, err error) {
 	v, n, err := o.unmarshalScalar(b, wtyp, num, field.Kind())
 	if err != nil {
 		return 0, err
 	}
+	if values := closedEnumValues(field); values != nil && values.ByNumber(v.Enum()) == nil {
+		return 0, errUnknown
+	}
 	switch field.Kind() {
 	case protoreflect.GroupKind, protoreflect.MessageKind:
 		// Messages are merged with any existing message value,
@@ -125,20 +225,24 @@ func (o UnmarshalOptions) unmarshalScalarField(b []byte, wtyp wire.Type, num wir
 		}
 		// Pass up errors (fatal and otherwise).
 		err = o.unmarshalMessage(v.Bytes(), m)
+		if field.Kind() == protoreflect.MessageKind {
+			err = errors.AddOffset(err, n-len(v.Bytes()))
+		}
 	default:
 		// Non-message scalars replace the previous value.
 		knownFields.Set(num, v)
+		err = checkUTF8(field, v, n)
 	}
 	return n, err
 }
 
-func (o UnmarshalOptions) unmarshalMap(b []byte, wtyp wire.Type, num wire.Number, mapv protoreflect.Map, field protoreflect.FieldDescriptor) (n int, err error) {
-	if wtyp != wire.BytesType {
+func (o UnmarshalOptions) unmarshalMap(b []byte, wtyp protowire.Type, num protowire.Number, mapv protoreflect.Map, field protoreflect.FieldDescriptor) (n int, err error) {
+	if wtyp != protowire.BytesType {
 		return 0, errUnknown
 	}
-	b, n = wire.ConsumeBytes(b)
+	b, n = protowire.ConsumeBytes(b)
 	if n < 0 {
-		return 0, wire.ParseError(n)
+		return 0, protowire.ParseError(n)
 	}
 	var (
 		keyField = field.MessageType().Fields().ByNumber(1)
@@ -155,12 +259,13 @@ func (o UnmarshalOptions) unmarshalMap(b []byte, wtyp wire.Type, num wire.Number
 	// Map entries are represented as a two-element message with fields
 	// containing the key and value.
 	var nerr errors.NonFatal
-	for len(b) > 0 {
-		num, wtyp, n := wire.ConsumeTag(b)
+	for pos := n - len(b); len(b) > 0; {
+		num, wtyp, n := protowire.ConsumeTag(b)
 		if n < 0 {
-			return 0, wire.ParseError(n)
+			return 0, errors.AddOffset(protowire.ParseError(n), pos)
 		}
 		b = b[n:]
+		pos += n
 		err = errUnknown
 		switch num {
 		case 1:
@@ -168,6 +273,7 @@ func (o UnmarshalOptions) unmarshalMap(b []byte, wtyp wire.Type, num wire.Number
 			if err != nil {
 				break
 			}
+			err = checkUTF8(keyField, key, n)
 			haveKey = true
 		case 2:
 			var v protoreflect.Value
@@ -177,23 +283,34 @@ func (o UnmarshalOptions) unmarshalMap(b []byte, wtyp wire.Type, num wire.Number
 			}
 			switch valField.Kind() {
 			case protoreflect.GroupKind, protoreflect.MessageKind:
-				if err := o.unmarshalMessage(v.Bytes(), val.Message()); !nerr.Merge(err) {
+				err := o.unmarshalMessage(v.Bytes(), val.Message())
+				if valField.Kind() == protoreflect.MessageKind {
+					err = errors.AddOffset(err, n-len(v.Bytes()))
+				}
+				if err := errors.AddOffset(err, pos); !nerr.Merge(err) {
 					return 0, err
 				}
 			default:
+				if values := closedEnumValues(valField); values != nil && values.ByNumber(v.Enum()) == nil {
+					// The entire entry is unknown if the value is not
+					// a member of a closed enum.
+					return 0, errUnknown
+				}
 				val = v
+				err = checkUTF8(valField, v, n)
 			}
 			haveVal = true
 		}
 		if err == errUnknown {
-			n = wire.ConsumeFieldValue(num, wtyp, b)
+			n = protowire.ConsumeFieldValue(num, wtyp, b)
 			if n < 0 {
-				return 0, wire.ParseError(n)
+				return 0, errors.AddOffset(protowire.ParseError(n), pos)
 			}
-		} else if !nerr.Merge(err) {
+		} else if err := errors.AddOffset(err, pos); !nerr.Merge(err) {
 			return 0, err
 		}
 		b = b[n:]
+		pos += n
 	}
 	// Every map entry should have entries for key and value, but this is not strictly required.
 	if !haveKey {
@@ -207,7 +324,107 @@ func (o UnmarshalOptions) unmarshalMap(b []byte, wtyp wire.Type, num wire.Number
 		}
 	}
 	mapv.Set(key.MapKey(), val)
-	return n, nerr.E
+	return n, errors.AddIndex(nerr.E, fmt.Sprint(key.MapKey()))
+}
+
+// unmarshalClosedEnumList decodes a record of a repeated field of a closed
+// enum type. Values which are not members of the enum are returned in raw,
+// encoded as unpacked records of the field, to be placed in the unknown
+// fields of the message. A single unpacked value which is not a member
+// results in errUnknown.
+func (o UnmarshalOptions) unmarshalClosedEnumList(b []byte, wtyp protowire.Type, num protowire.Number, list protoreflect.List, values protoreflect.EnumValueDescriptors) (n int, raw []byte, err error) {
+	switch wtyp {
+	case protowire.VarintType:
+		v, n := protowire.ConsumeVarint(b)
+		if n < 0 {
+			return 0, nil, protowire.ParseError(n)
+		}
+		if values.ByNumber(protoreflect.EnumNumber(v)) == nil {
+			return 0, nil, errUnknown
+		}
+		list.Append(protoreflect.ValueOf(protoreflect.EnumNumber(v)))
+		return n, nil, nil
+	case protowire.BytesType:
+		buf, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return 0, nil, protowire.ParseError(n)
+		}
+		for len(buf) > 0 {
+			v, n := protowire.ConsumeVarint(buf)
+			if n < 0 {
+				return 0, nil, protowire.ParseError(n)
+			}
+			buf = buf[n:]
+			if values.ByNumber(protoreflect.EnumNumber(v)) == nil {
+				raw = protowire.AppendTag(raw, num, protowire.VarintType)
+				raw = protowire.AppendVarint(raw, v)
+				continue
+			}
+			list.Append(protoreflect.ValueOf(protoreflect.EnumNumber(v)))
+		}
+		return n, raw, nil
+	default:
+		return 0, nil, errUnknown
+	}
+}
+
+// closedEnumValues returns the values of the enum type of fd if it is a
+// closed enum, or nil otherwise. Enums declared in proto2 files are closed:
+// values which are not members of the enum are treated as unknown fields.
+func closedEnumValues(fd protoreflect.FieldDescriptor) protoreflect.EnumValueDescriptors {
+	if fd.Kind() != protoreflect.EnumKind {
+		return nil
+	}
+	ed := fd.EnumType()
+	if ed == nil || ed.IsPlaceholder() || ed.Syntax() != protoreflect.Proto2 {
+		return nil
+	}
+	return ed.Values()
+}
+
+// enforceUTF8 reports whether the values of fd must be valid UTF-8,
+// which is the case for string fields declared in proto3 files.
+func enforceUTF8(fd protoreflect.FieldDescriptor) bool {
+	return fd.Kind() == protoreflect.StringKind && fd.Syntax() == protoreflect.Proto3
+}
+
+// checkUTF8 returns a non-fatal InvalidUTF8Error if fd requires valid UTF-8
+// and the decoded value v does not contain it. The value ends at offset n in
+// the input of the field.
+func checkUTF8(fd protoreflect.FieldDescriptor, v protoreflect.Value, n int) error {
+	if !enforceUTF8(fd) {
+		return nil
+	}
+	if s := v.String(); !utf8.ValidString(s) {
+		return &errors.InvalidUTF8Error{Offset: n - len(s)}
+	}
+	return nil
+}
+
+// fieldName returns the name of fd as it appears in the path of an error.
+func fieldName(fd protoreflect.FieldDescriptor) string {
+	if fd.ExtendedType() != nil {
+		return "(" + string(fd.FullName()) + ")"
+	}
+	return string(fd.Name())
+}
+
+// toBytes returns the value of a decoded bytes field.
+func (o UnmarshalOptions) toBytes(v []byte) []byte {
+	if o.AliasBuffer {
+		// Limit the capacity so that appending to the field
+		// does not overwrite the remainder of the input.
+		return v[:len(v):len(v)]
+	}
+	return append(([]byte)(nil), v...)
+}
+
+// toString returns the value of a decoded string field.
+func (o UnmarshalOptions) toString(v []byte) string {
+	if o.AliasBuffer && o.AliasStrings {
+		return strs.UnsafeString(v)
+	}
+	return string(v)
 }
 
 // errUnknown is used internally to indicate fields which should be added
@@ -8,8 +8,9 @@ package proto
 
 import (
 	"math"
+	"strconv"
 
-	"github.com/golang/protobuf/v2/internal/encoding/wire"
+	"github.com/golang/protobuf/v2/encoding/protowire"
 	"github.com/golang/protobuf/v2/internal/errors"
 	"github.com/golang/protobuf/v2/reflect/protoreflect"
 )
@@ -17,168 +18,168 @@ import (
 // unmarshalScalar decodes a value of the given kind.
 //
 // Message values are decoded into a []byte which aliases the input data.
-func (o UnmarshalOptions) unmarshalScalar(b []byte, wtyp wire.Type, num wire.Number, kind protoreflect.Kind) (val protoreflect.Value, n int, err error) {
+func (o UnmarshalOptions) unmarshalScalar(b []byte, wtyp protowire.Type, num protowire.Number, kind protoreflect.Kind) (val protoreflect.Value, n int, err error) {
 	switch kind {
 	case protoreflect.BoolKind:
-		if wtyp != wire.VarintType {
+		if wtyp != protowire.VarintType {
 			return val, 0, errUnknown
 		}
-		v, n := wire.ConsumeVarint(b)
+		v, n := protowire.ConsumeVarint(b)
 		if n < 0 {
-			return val, 0, wire.ParseError(n)
+			return val, 0, protowire.ParseError(n)
 		}
-		return protoreflect.ValueOf(wire.DecodeBool(v)), n, nil
+		return protoreflect.ValueOf(protowire.DecodeBool(v)), n, nil
 	case protoreflect.EnumKind:
-		if wtyp != wire.VarintType {
+		if wtyp != protowire.VarintType {
 			return val, 0, errUnknown
 		}
-		v, n := wire.ConsumeVarint(b)
+		v, n := protowire.ConsumeVarint(b)
 		if n < 0 {
-			return val, 0, wire.ParseError(n)
+			return val, 0, protowire.ParseError(n)
 		}
 		return protoreflect.ValueOf(protoreflect.EnumNumber(v)), n, nil
 	case protoreflect.Int32Kind:
-		if wtyp != wire.VarintType {
+		if wtyp != protowire.VarintType {
 			return val, 0, errUnknown
 		}
-		v, n := wire.ConsumeVarint(b)
+		v, n := protowire.ConsumeVarint(b)
 		if n < 0 {
-			return val, 0, wire.ParseError(n)
+			return val, 0, protowire.ParseError(n)
 		}
 		return protoreflect.ValueOf(int32(v)), n, nil
 	case protoreflect.Sint32Kind:
-		if wtyp != wire.VarintType {
+		if wtyp != protowire.VarintType {
 			return val, 0, errUnknown
 		}
-		v, n := wire.ConsumeVarint(b)
+		v, n := protowire.ConsumeVarint(b)
 		if n < 0 {
-			return val, 0, wire.ParseError(n)
+			return val, 0, protowire.ParseError(n)
 		}
-		return protoreflect.ValueOf(int32(wire.DecodeZigZag(v & math.MaxUint32))), n, nil
+		return protoreflect.ValueOf(int32(protowire.DecodeZigZag(v & math.MaxUint32))), n, nil
 	case protoreflect.Uint32Kind:
-		if wtyp != wire.VarintType {
+		if wtyp != protowire.VarintType {
 			return val, 0, errUnknown
 		}
-		v, n := wire.ConsumeVarint(b)
+		v, n := protowire.ConsumeVarint(b)
 		if n < 0 {
-			return val, 0, wire.ParseError(n)
+			return val, 0, protowire.ParseError(n)
 		}
 		return protoreflect.ValueOf(uint32(v)), n, nil
 	case protoreflect.Int64Kind:
-		if wtyp != wire.VarintType {
+		if wtyp != protowire.VarintType {
 			return val, 0, errUnknown
 		}
-		v, n := wire.ConsumeVarint(b)
+		v, n := protowire.ConsumeVarint(b)
 		if n < 0 {
-			return val, 0, wire.ParseError(n)
+			return val, 0, protowire.ParseError(n)
 		}
 		return protoreflect.ValueOf(int64(v)), n, nil
 	case protoreflect.Sint64Kind:
-		if wtyp != wire.VarintType {
+		if wtyp != protowire.VarintType {
 			return val, 0, errUnknown
 		}
-		v, n := wire.ConsumeVarint(b)
+		v, n := protowire.ConsumeVarint(b)
 		if n < 0 {
-			return val, 0, wire.ParseError(n)
+			return val, 0, protowire.ParseError(n)
 		}
-		return protoreflect.ValueOf(wire.DecodeZigZag(v)), n, nil
+		return protoreflect.ValueOf(protowire.DecodeZigZag(v)), n, nil
 	case protoreflect.Uint64Kind:
-		if wtyp != wire.VarintType {
+		if wtyp != protowire.VarintType {
 			return val, 0, errUnknown
 		}
-		v, n := wire.ConsumeVarint(b)
+		v, n := protowire.ConsumeVarint(b)
 		if n < 0 {
-			return val, 0, wire.ParseError(n)
+			return val, 0, protowire.ParseError(n)
 		}
 		return protoreflect.ValueOf(v), n, nil
 	case protoreflect.Sfixed32Kind:
-		if wtyp != wire.Fixed32Type {
+		if wtyp != protowire.Fixed32Type {
 			return val, 0, errUnknown
 		}
-		v, n := wire.ConsumeFixed32(b)
+		v, n := protowire.ConsumeFixed32(b)
 		if n < 0 {
-			return val, 0, wire.ParseError(n)
+			return val, 0, protowire.ParseError(n)
 		}
 		return protoreflect.ValueOf(int32(v)), n, nil
 	case protoreflect.Fixed32Kind:
-		if wtyp != wire.Fixed32Type {
+		if wtyp != protowire.Fixed32Type {
 			return val, 0, errUnknown
 		}
-		v, n := wire.ConsumeFixed32(b)
+		v, n := protowire.ConsumeFixed32(b)
 		if n < 0 {
-			return val, 0, wire.ParseError(n)
+			return val, 0, protowire.ParseError(n)
 		}
 		return protoreflect.ValueOf(uint32(v)), n, nil
 	case protoreflect.FloatKind:
-		if wtyp != wire.Fixed32Type {
+		if wtyp != protowire.Fixed32Type {
 			return val, 0, errUnknown
 		}
-		v, n := wire.ConsumeFixed32(b)
+		v, n := protowire.ConsumeFixed32(b)
 		if n < 0 {
-			return val, 0, wire.ParseError(n)
+			return val, 0, protowire.ParseError(n)
 		}
 		return protoreflect.ValueOf(math.Float32frombits(uint32(v))), n, nil
 	case protoreflect.Sfixed64Kind:
-		if wtyp != wire.Fixed64Type {
+		if wtyp != protowire.Fixed64Type {
 			return val, 0, errUnknown
 		}
-		v, n := wire.ConsumeFixed64(b)
+		v, n := protowire.ConsumeFixed64(b)
 		if n < 0 {
-			return val, 0, wire.ParseError(n)
+			return val, 0, protowire.ParseError(n)
 		}
 		return protoreflect.ValueOf(int64(v)), n, nil
 	case protoreflect.Fixed64Kind:
-		if wtyp != wire.Fixed64Type {
+		if wtyp != protowire.Fixed64Type {
 			return val, 0, errUnknown
 		}
-		v, n := wire.ConsumeFixed64(b)
+		v, n := protowire.ConsumeFixed64(b)
 		if n < 0 {
-			return val, 0, wire.ParseError(n)
+			return val, 0, protowire.ParseError(n)
 		}
 		return protoreflect.ValueOf(v), n, nil
 	case protoreflect.DoubleKind:
-		if wtyp != wire.Fixed64Type {
+		if wtyp != protowire.Fixed64Type {
 			return val, 0, errUnknown
 		}
-		v, n := wire.ConsumeFixed64(b)
+		v, n := protowire.ConsumeFixed64(b)
 		if n < 0 {
-			return val, 0, wire.ParseError(n)
+			return val, 0, protowire.ParseError(n)
 		}
 		return protoreflect.ValueOf(math.Float64frombits(v)), n, nil
 	case protoreflect.StringKind:
-		if wtyp != wire.BytesType {
+		if wtyp != protowire.BytesType {
 			return val, 0, errUnknown
 		}
-		v, n := wire.ConsumeBytes(b)
+		v, n := protowire.ConsumeBytes(b)
 		if n < 0 {
-			return val, 0, wire.ParseError(n)
+			return val, 0, protowire.ParseError(n)
 		}
-		return protoreflect.ValueOf(string(v)), n, nil
+		return protoreflect.ValueOf(o.toString(v)), n, nil
 	case protoreflect.BytesKind:
-		if wtyp != wire.BytesType {
+		if wtyp != protowire.BytesType {
 			return val, 0, errUnknown
 		}
-		v, n := wire.ConsumeBytes(b)
+		v, n := protowire.ConsumeBytes(b)
 		if n < 0 {
-			return val, 0, wire.ParseError(n)
+			return val, 0, protowire.ParseError(n)
 		}
-		return protoreflect.ValueOf(append(([]byte)(nil), v...)), n, nil
+		return protoreflect.ValueOf(o.toBytes(v)), n, nil
 	case protoreflect.MessageKind:
-		if wtyp != wire.BytesType {
+		if wtyp != protowire.BytesType {
 			return val, 0, errUnknown
 		}
-		v, n := wire.ConsumeBytes(b)
+		v, n := protowire.ConsumeBytes(b)
 		if n < 0 {
-			return val, 0, wire.ParseError(n)
+			return val, 0, protowire.ParseError(n)
 		}
 		return protoreflect.ValueOf(v), n, nil
 	case protoreflect.GroupKind:
-		if wtyp != wire.StartGroupType {
+		if wtyp != protowire.StartGroupType {
 			return val, 0, errUnknown
 		}
-		v, n := wire.ConsumeGroup(num, b)
+		v, n := protowire.ConsumeGroup(num, b)
 		if n < 0 {
-			return val, 0, wire.ParseError(n)
+			return val, 0, protowire.ParseError(n)
 		}
 		return protoreflect.ValueOf(v), n, nil
 	default:
@@ -186,403 +187,406 @@ func (o UnmarshalOptions) unmarshalScalar(b []byte, wtyp wire.Type, num wire.Num
 	}
 }
 
-func (o UnmarshalOptions) unmarshalList(b []byte, wtyp wire.Type, num wire.Number, list protoreflect.List, kind protoreflect.Kind) (n int, err error) {
+func (o UnmarshalOptions) unmarshalList(b []byte, wtyp protowire.Type, num protowire.Number, list protoreflect.List, kind protoreflect.Kind) (n int, err error) {
 	var nerr errors.NonFatal
 	switch kind {
 	case protoreflect.BoolKind:
-		if wtyp == wire.BytesType {
-			buf, n := wire.ConsumeBytes(b)
+		if wtyp == protowire.BytesType {
+			buf, n := protowire.ConsumeBytes(b)
 			if n < 0 {
-				return 0, wire.ParseError(n)
+				return 0, protowire.ParseError(n)
 			}
 			for len(buf) > 0 {
-				v, n := wire.ConsumeVarint(buf)
+				v, n := protowire.ConsumeVarint(buf)
 				if n < 0 {
-					return 0, wire.ParseError(n)
+					return 0, protowire.ParseError(n)
 				}
 				buf = buf[n:]
-				list.Append(protoreflect.ValueOf(wire.DecodeBool(v)))
+				list.Append(protoreflect.ValueOf(protowire.DecodeBool(v)))
 			}
 			return n, nil
 		}
-		if wtyp != wire.VarintType {
+		if wtyp != protowire.VarintType {
 			return 0, errUnknown
 		}
-		v, n := wire.ConsumeVarint(b)
+		v, n := protowire.ConsumeVarint(b)
 		if n < 0 {
-			return 0, wire.ParseError(n)
+			return 0, protowire.ParseError(n)
 		}
-		list.Append(protoreflect.ValueOf(wire.DecodeBool(v)))
+		list.Append(protoreflect.ValueOf(protowire.DecodeBool(v)))
 		return n, nerr.E
 	case protoreflect.EnumKind:
-		if wtyp == wire.BytesType {
-			buf, n := wire.ConsumeBytes(b)
+		if wtyp == protowire.BytesType {
+			buf, n := protowire.ConsumeBytes(b)
 			if n < 0 {
-				return 0, wire.ParseError(n)
+				return 0, protowire.ParseError(n)
 			}
 			for len(buf) > 0 {
-				v, n := wire.ConsumeVarint(buf)
+				v, n := protowire.ConsumeVarint(buf)
 				if n < 0 {
-					return 0, wire.ParseError(n)
+					return 0, protowire.ParseError(n)
 				}
 				buf = buf[n:]
 				list.Append(protoreflect.ValueOf(protoreflect.EnumNumber(v)))
 			}
 			return n, nil
 		}
-		if wtyp != wire.VarintType {
+		if wtyp != protowire.VarintType {
 			return 0, errUnknown
 		}
-		v, n := wire.ConsumeVarint(b)
+		v, n := protowire.ConsumeVarint(b)
 		if n < 0 {
-			return 0, wire.ParseError(n)
+			return 0, protowire.ParseError(n)
 		}
 		list.Append(protoreflect.ValueOf(protoreflect.EnumNumber(v)))
 		return n, nerr.E
 	case protoreflect.Int32Kind:
-		if wtyp == wire.BytesType {
-			buf, n := wire.ConsumeBytes(b)
+		if wtyp == protowire.BytesType {
+			buf, n := protowire.ConsumeBytes(b)
 			if n < 0 {
-				return 0, wire.ParseError(n)
+				return 0, protowire.ParseError(n)
 			}
 			for len(buf) > 0 {
-				v, n := wire.ConsumeVarint(buf)
+				v, n := protowire.ConsumeVarint(buf)
 				if n < 0 {
-					return 0, wire.ParseError(n)
+					return 0, protowire.ParseError(n)
 				}
 				buf = buf[n:]
 				list.Append(protoreflect.ValueOf(int32(v)))
 			}
 			return n, nil
 		}
-		if wtyp != wire.VarintType {
+		if wtyp != protowire.VarintType {
 			return 0, errUnknown
 		}
-		v, n := wire.ConsumeVarint(b)
+		v, n := protowire.ConsumeVarint(b)
 		if n < 0 {
-			return 0, wire.ParseError(n)
+			return 0, protowire.ParseError(n)
 		}
 		list.Append(protoreflect.ValueOf(int32(v)))
 		return n, nerr.E
 	case protoreflect.Sint32Kind:
-		if wtyp == wire.BytesType {
-			buf, n := wire.ConsumeBytes(b)
+		if wtyp == protowire.BytesType {
+			buf, n := protowire.ConsumeBytes(b)
 			if n < 0 {
-				return 0, wire.ParseError(n)
+				return 0, protowire.ParseError(n)
 			}
 			for len(buf) > 0 {
-				v, n := wire.ConsumeVarint(buf)
+				v, n := protowire.ConsumeVarint(buf)
 				if n < 0 {
-					return 0, wire.ParseError(n)
+					return 0, protowire.ParseError(n)
 				}
 				buf = buf[n:]
-				list.Append(protoreflect.ValueOf(int32(wire.DecodeZigZag(v & math.MaxUint32))))
+				list.Append(protoreflect.ValueOf(int32(protowire.DecodeZigZag(v & math.MaxUint32))))
 			}
 			return n, nil
 		}
-		if wtyp != wire.VarintType {
+		if wtyp != protowire.VarintType {
 			return 0, errUnknown
 		}
-		v, n := wire.ConsumeVarint(b)
+		v, n := protowire.ConsumeVarint(b)
 		if n < 0 {
-			return 0, wire.ParseError(n)
+			return 0, protowire.ParseError(n)
 		}
-		list.Append(protoreflect.ValueOf(int32(wire.DecodeZigZag(v & math.MaxUint32))))
+		list.Append(protoreflect.ValueOf(int32(protowire.DecodeZigZag(v & math.MaxUint32))))
 		return n, nerr.E
 	case protoreflect.Uint32Kind:
-		if wtyp == wire.BytesType {
-			buf, n := wire.ConsumeBytes(b)
+		if wtyp == protowire.BytesType {
+			buf, n := protowire.ConsumeBytes(b)
 			if n < 0 {
-				return 0, wire.ParseError(n)
+				return 0, protowire.ParseError(n)
 			}
 			for len(buf) > 0 {
-				v, n := wire.ConsumeVarint(buf)
+				v, n := protowire.ConsumeVarint(buf)
 				if n < 0 {
-					return 0, wire.ParseError(n)
+					return 0, protowire.ParseError(n)
 				}
 				buf = buf[n:]
 				list.Append(protoreflect.ValueOf(uint32(v)))
 			}
 			return n, nil
 		}
-		if wtyp != wire.VarintType {
+		if wtyp != protowire.VarintType {
 			return 0, errUnknown
 		}
-		v, n := wire.ConsumeVarint(b)
+		v, n := protowire.ConsumeVarint(b)
 		if n < 0 {
-			return 0, wire.ParseError(n)
+			return 0, protowire.ParseError(n)
 		}
 		list.Append(protoreflect.ValueOf(uint32(v)))
 		return n, nerr.E
 	case protoreflect.Int64Kind:
-		if wtyp == wire.BytesType {
-			buf, n := wire.ConsumeBytes(b)
+		if wtyp == protowire.BytesType {
+			buf, n := protowire.ConsumeBytes(b)
 			if n < 0 {
-				return 0, wire.ParseError(n)
+				return 0, protowire.ParseError(n)
 			}
 			for len(buf) > 0 {
-				v, n := wire.ConsumeVarint(buf)
+				v, n := protowire.ConsumeVarint(buf)
 				if n < 0 {
-					return 0, wire.ParseError(n)
+					return 0, protowire.ParseError(n)
 				}
 				buf = buf[n:]
 				list.Append(protoreflect.ValueOf(int64(v)))
 			}
 			return n, nil
 		}
-		if wtyp != wire.VarintType {
+		if wtyp != protowire.VarintType {
 			return 0, errUnknown
 		}
-		v, n := wire.ConsumeVarint(b)
+		v, n := protowire.ConsumeVarint(b)
 		if n < 0 {
-			return 0, wire.ParseError(n)
+			return 0, protowire.ParseError(n)
 		}
 		list.Append(protoreflect.ValueOf(int64(v)))
 		return n, nerr.E
 	case protoreflect.Sint64Kind:
-		if wtyp == wire.BytesType {
-			buf, n := wire.ConsumeBytes(b)
+		if wtyp == protowire.BytesType {
+			buf, n := protowire.ConsumeBytes(b)
 			if n < 0 {
-				return 0, wire.ParseError(n)
+				return 0, protowire.ParseError(n)
 			}
 			for len(buf) > 0 {
-				v, n := wire.ConsumeVarint(buf)
+				v, n := protowire.ConsumeVarint(buf)
 				if n < 0 {
-					return 0, wire.ParseError(n)
+					return 0, protowire.ParseError(n)
 				}
 				buf = buf[n:]
-				list.Append(protoreflect.ValueOf(wire.DecodeZigZag(v)))
+				list.Append(protoreflect.ValueOf(protowire.DecodeZigZag(v)))
 			}
 			return n, nil
 		}
-		if wtyp != wire.VarintType {
+		if wtyp != protowire.VarintType {
 			return 0, errUnknown
 		}
-		v, n := wire.ConsumeVarint(b)
+		v, n := protowire.ConsumeVarint(b)
 		if n < 0 {
-			return 0, wire.ParseError(n)
+			return 0, protowire.ParseError(n)
 		}
-		list.Append(protoreflect.ValueOf(wire.DecodeZigZag(v)))
+		list.Append(protoreflect.ValueOf(protowire.DecodeZigZag(v)))
 		return n, nerr.E
 	case protoreflect.Uint64Kind:
-		if wtyp == wire.BytesType {
-			buf, n := wire.ConsumeBytes(b)
+		if wtyp == protowire.BytesType {
+			buf, n := protowire.ConsumeBytes(b)
 			if n < 0 {
-				return 0, wire.ParseError(n)
+				return 0, protowire.ParseError(n)
 			}
 			for len(buf) > 0 {
-				v, n := wire.ConsumeVarint(buf)
+				v, n := protowire.ConsumeVarint(buf)
 				if n < 0 {
-					return 0, wire.ParseError(n)
+					return 0, protowire.ParseError(n)
 				}
 				buf = buf[n:]
 				list.Append(protoreflect.ValueOf(v))
 			}
 			return n, nil
 		}
-		if wtyp != wire.VarintType {
+		if wtyp != protowire.VarintType {
 			return 0, errUnknown
 		}
-		v, n := wire.ConsumeVarint(b)
+		v, n := protowire.ConsumeVarint(b)
 		if n < 0 {
-			return 0, wire.ParseError(n)
+			return 0, protowire.ParseError(n)
 		}
 		list.Append(protoreflect.ValueOf(v))
 		return n, nerr.E
 	case protoreflect.Sfixed32Kind:
-		if wtyp == wire.BytesType {
-			buf, n := wire.ConsumeBytes(b)
+		if wtyp == protowire.BytesType {
+			buf, n := protowire.ConsumeBytes(b)
 			if n < 0 {
-				return 0, wire.ParseError(n)
+				return 0, protowire.ParseError(n)
 			}
 			for len(buf) > 0 {
-				v, n := wire.ConsumeFixed32(buf)
+				v, n := protowire.ConsumeFixed32(buf)
 				if n < 0 {
-					return 0, wire.ParseError(n)
+					return 0, protowire.ParseError(n)
 				}
 				buf = buf[n:]
 				list.Append(protoreflect.ValueOf(int32(v)))
 			}
 			return n, nil
 		}
-		if wtyp != wire.Fixed32Type {
+		if wtyp != protowire.Fixed32Type {
 			return 0, errUnknown
 		}
-		v, n := wire.ConsumeFixed32(b)
+		v, n := protowire.ConsumeFixed32(b)
 		if n < 0 {
-			return 0, wire.ParseError(n)
+			return 0, protowire.ParseError(n)
 		}
 		list.Append(protoreflect.ValueOf(int32(v)))
 		return n, nerr.E
 	case protoreflect.Fixed32Kind:
-		if wtyp == wire.BytesType {
-			buf, n := wire.ConsumeBytes(b)
+		if wtyp == protowire.BytesType {
+			buf, n := protowire.ConsumeBytes(b)
 			if n < 0 {
-				return 0, wire.ParseError(n)
+				return 0, protowire.ParseError(n)
 			}
 			for len(buf) > 0 {
-				v, n := wire.ConsumeFixed32(buf)
+				v, n := protowire.ConsumeFixed32(buf)
 				if n < 0 {
-					return 0, wire.ParseError(n)
+					return 0, protowire.ParseError(n)
 				}
 				buf = buf[n:]
 				list.Append(protoreflect.ValueOf(uint32(v)))
 			}
 			return n, nil
 		}
-		if wtyp != wire.Fixed32Type {
+		if wtyp != protowire.Fixed32Type {
 			return 0, errUnknown
 		}
-		v, n := wire.ConsumeFixed32(b)
+		v, n := protowire.ConsumeFixed32(b)
 		if n < 0 {
-			return 0, wire.ParseError(n)
+			return 0, protowire.ParseError(n)
 		}
 		list.Append(protoreflect.ValueOf(uint32(v)))
 		return n, nerr.E
 	case protoreflect.FloatKind:
-		if wtyp == wire.BytesType {
-			buf, n := wire.ConsumeBytes(b)
+		if wtyp == protowire.BytesType {
+			buf, n := protowire.ConsumeBytes(b)
 			if n < 0 {
-				return 0, wire.ParseError(n)
+				return 0, protowire.ParseError(n)
 			}
 			for len(buf) > 0 {
-				v, n := wire.ConsumeFixed32(buf)
+				v, n := protowire.ConsumeFixed32(buf)
 				if n < 0 {
-					return 0, wire.ParseError(n)
+					return 0, protowire.ParseError(n)
 				}
 				buf = buf[n:]
 				list.Append(protoreflect.ValueOf(math.Float32frombits(uint32(v))))
 			}
 			return n, nil
 		}
-		if wtyp != wire.Fixed32Type {
+		if wtyp != protowire.Fixed32Type {
 			return 0, errUnknown
 		}
-		v, n := wire.ConsumeFixed32(b)
+		v, n := protowire.ConsumeFixed32(b)
 		if n < 0 {
-			return 0, wire.ParseError(n)
+			return 0, protowire.ParseError(n)
 		}
 		list.Append(protoreflect.ValueOf(math.Float32frombits(uint32(v))))
 		return n, nerr.E
 	case protoreflect.Sfixed64Kind:
-		if wtyp == wire.BytesType {
-			buf, n := wire.ConsumeBytes(b)
+		if wtyp == protowire.BytesType {
+			buf, n := protowire.ConsumeBytes(b)
 			if n < 0 {
-				return 0, wire.ParseError(n)
+				return 0, protowire.ParseError(n)
 			}
 			for len(buf) > 0 {
-				v, n := wire.ConsumeFixed64(buf)
+				v, n := protowire.ConsumeFixed64(buf)
 				if n < 0 {
-					return 0, wire.ParseError(n)
+					return 0, protowire.ParseError(n)
 				}
 				buf = buf[n:]
 				list.Append(protoreflect.ValueOf(int64(v)))
 			}
 			return n, nil
 		}
-		if wtyp != wire.Fixed64Type {
+		if wtyp != protowire.Fixed64Type {
 			return 0, errUnknown
 		}
-		v, n := wire.ConsumeFixed64(b)
+		v, n := protowire.ConsumeFixed64(b)
 		if n < 0 {
-			return 0, wire.ParseError(n)
+			return 0, protowire.ParseError(n)
 		}
 		list.Append(protoreflect.ValueOf(int64(v)))
 		return n, nerr.E
 	case protoreflect.Fixed64Kind:
-		if wtyp == wire.BytesType {
-			buf, n := wire.ConsumeBytes(b)
+		if wtyp == protowire.BytesType {
+			buf, n := protowire.ConsumeBytes(b)
 			if n < 0 {
-				return 0, wire.ParseError(n)
+				return 0, protowire.ParseError(n)
 			}
 			for len(buf) > 0 {
-				v, n := wire.ConsumeFixed64(buf)
+				v, n := protowire.ConsumeFixed64(buf)
 				if n < 0 {
-					return 0, wire.ParseError(n)
+					return 0, protowire.ParseError(n)
 				}
 				buf = buf[n:]
 				list.Append(protoreflect.ValueOf(v))
 			}
 			return n, nil
 		}
-		if wtyp != wire.Fixed64Type {
+		if wtyp != protowire.Fixed64Type {
 			return 0, errUnknown
 		}
-		v, n := wire.ConsumeFixed64(b)
+		v, n := protowire.ConsumeFixed64(b)
 		if n < 0 {
-			return 0, wire.ParseError(n)
+			return 0, protowire.ParseError(n)
 		}
 		list.Append(protoreflect.ValueOf(v))
 		return n, nerr.E
 	case protoreflect.DoubleKind:
-		if wtyp == wire.BytesType {
-			buf, n := wire.ConsumeBytes(b)
+		if wtyp == protowire.BytesType {
+			buf, n := protowire.ConsumeBytes(b)
 			if n < 0 {
-				return 0, wire.ParseError(n)
+				return 0, protowire.ParseError(n)
 			}
 			for len(buf) > 0 {
-				v, n := wire.ConsumeFixed64(buf)
+				v, n := protowire.ConsumeFixed64(buf)
 				if n < 0 {
-					return 0, wire.ParseError(n)
+					return 0, protowire.ParseError(n)
 				}
 				buf = buf[n:]
 				list.Append(protoreflect.ValueOf(math.Float64frombits(v)))
 			}
 			return n, nil
 		}
-		if wtyp != wire.Fixed64Type {
+		if wtyp != protowire.Fixed64Type {
 			return 0, errUnknown
 		}
-		v, n := wire.ConsumeFixed64(b)
+		v, n := protowire.ConsumeFixed64(b)
 		if n < 0 {
-			return 0, wire.ParseError(n)
+			return 0, protowire.ParseError(n)
 		}
 		list.Append(protoreflect.ValueOf(math.Float64frombits(v)))
 		return n, nerr.E
 	case protoreflect.StringKind:
-		if wtyp != wire.BytesType {
+		if wtyp != protowire.BytesType {
 			return 0, errUnknown
 		}
-		v, n := wire.ConsumeBytes(b)
+		v, n := protowire.ConsumeBytes(b)
 		if n < 0 {
-			return 0, wire.ParseError(n)
+			return 0, protowire.ParseError(n)
 		}
-		list.Append(protoreflect.ValueOf(string(v)))
+		list.Append(protoreflect.ValueOf(o.toString(v)))
 		return n, nerr.E
 	case protoreflect.BytesKind:
-		if wtyp != wire.BytesType {
+		if wtyp != protowire.BytesType {
 			return 0, errUnknown
 		}
-		v, n := wire.ConsumeBytes(b)
+		v, n := protowire.ConsumeBytes(b)
 		if n < 0 {
-			return 0, wire.ParseError(n)
+			return 0, protowire.ParseError(n)
 		}
-		list.Append(protoreflect.ValueOf(append(([]byte)(nil), v...)))
+		list.Append(protoreflect.ValueOf(o.toBytes(v)))
 		return n, nerr.E
 	case protoreflect.MessageKind:
-		if wtyp != wire.BytesType {
+		if wtyp != protowire.BytesType {
 			return 0, errUnknown
 		}
-		v, n := wire.ConsumeBytes(b)
+		v, n := protowire.ConsumeBytes(b)
 		if n < 0 {
-			return 0, wire.ParseError(n)
+			return 0, protowire.ParseError(n)
 		}
 		m := list.NewMessage()
-		if err := o.unmarshalMessage(v, m); !nerr.Merge(err) {
+		err := o.unmarshalMessage(v, m)
+		err = errors.AddOffset(err, n-len(v))
+		if err := errors.AddIndex(err, strconv.Itoa(list.Len())); !nerr.Merge(err) {
 			return 0, err
 		}
 		list.Append(protoreflect.ValueOf(m))
 		return n, nerr.E
 	case protoreflect.GroupKind:
-		if wtyp != wire.StartGroupType {
+		if wtyp != protowire.StartGroupType {
 			return 0, errUnknown
 		}
-		v, n := wire.ConsumeGroup(num, b)
+		v, n := protowire.ConsumeGroup(num, b)
 		if n < 0 {
-			return 0, wire.ParseError(n)
+			return 0, protowire.ParseError(n)
 		}
 		m := list.NewMessage()
-		if err := o.unmarshalMessage(v, m); !nerr.Merge(err) {
+		err := o.unmarshalMessage(v, m)
+		if err := errors.AddIndex(err, strconv.Itoa(list.Len())); !nerr.Merge(err) {
 			return 0, err
 		}
 		list.Append(protoreflect.ValueOf(m))
@@ -5,6 +5,7 @@
 package proto_test
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -15,6 +16,8 @@ import (
 	"github.com/golang/protobuf/v2/internal/scalar"
 	"github.com/golang/protobuf/v2/proto"
 	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+	"github.com/golang/protobuf/v2/reflect/protoregistry"
+	"github.com/golang/protobuf/v2/types/dynamicpb"
 
 	testpb "github.com/golang/protobuf/v2/internal/testprotos/test"
 	test3pb "github.com/golang/protobuf/v2/internal/testprotos/test3"
@@ -80,6 +83,371 @@ func TestDecodeRequiredFieldChecks(t *testing.T) {
 	}
 }
 
+func TestDecodeRecursionLimit(t *testing.T) {
+	// Each level of deepMessage nests two messages.
+	b, err := proto.Marshal(deepMessage(10))
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	m := &testpb.TestAllTypes{}
+	if err := (proto.UnmarshalOptions{RecursionLimit: 21}).Unmarshal(b, m); err != nil {
+		t.Errorf("Unmarshal with limit 21 = %v, want nil", err)
+	}
+	if err := (proto.UnmarshalOptions{RecursionLimit: 20}).Unmarshal(b, m); !errors.Is(err, proto.ErrRecursionLimit) {
+		t.Errorf("Unmarshal with limit 20 = %v, want ErrRecursionLimit", err)
+	}
+
+	// Unknown groups count toward the limit.
+	groups := nestedGroups(proto.DefaultRecursionLimit)
+	for _, m := range []proto.Message{&testpb.TestAllTypes{}, &testpb.TestAllExtensions{}} {
+		if err := proto.Unmarshal(nestedGroups(100), m); err != nil {
+			t.Errorf("%T: Unmarshal of 100 nested groups = %v, want nil", m, err)
+		}
+		if err := proto.Unmarshal(groups, m); !errors.Is(err, proto.ErrRecursionLimit) {
+			t.Errorf("%T: Unmarshal of %d nested groups = %v, want ErrRecursionLimit", m, proto.DefaultRecursionLimit, err)
+		}
+	}
+}
+
+// nestedGroups returns the wire encoding of an unknown group
+// with depth levels of nesting.
+func nestedGroups(depth int) []byte {
+	var msg pack.Message
+	for i := 0; i < depth; i++ {
+		msg = append(msg, pack.Tag{1000, pack.StartGroupType})
+	}
+	for i := 0; i < depth; i++ {
+		msg = append(msg, pack.Tag{1000, pack.EndGroupType})
+	}
+	return msg.Marshal()
+}
+
+func TestDecodeMaxSize(t *testing.T) {
+	b := pack.Message{
+		pack.Tag{1, pack.VarintType}, pack.Varint(1),
+	}.Marshal()
+	for _, m := range []proto.Message{&testpb.TestAllTypes{}, &testpb.TestAllExtensions{}} {
+		if err := (proto.UnmarshalOptions{MaxSize: len(b)}).Unmarshal(b, m); err != nil {
+			t.Errorf("%T: Unmarshal with MaxSize %d = %v, want nil", m, len(b), err)
+		}
+		if err := (proto.UnmarshalOptions{MaxSize: len(b) - 1}).Unmarshal(b, m); err != proto.ErrMaxSize {
+			t.Errorf("%T: Unmarshal with MaxSize %d = %v, want ErrMaxSize", m, len(b)-1, err)
+		}
+	}
+}
+
+func TestDecodeAliasBuffer(t *testing.T) {
+	want := &testpb.TestAllTypes{
+		OptionalString: scalar.String("string"),
+		OptionalBytes:  []byte("bytes"),
+		RepeatedBytes:  [][]byte{[]byte("a"), []byte("b")},
+		MapStringBytes: map[string][]byte{"k": []byte("v")},
+	}
+	for _, opts := range []proto.UnmarshalOptions{
+		{},
+		{AliasBuffer: true},
+		{AliasBuffer: true, AliasStrings: true},
+	} {
+		b, err := proto.Marshal(want)
+		if err != nil {
+			t.Fatalf("Marshal error: %v", err)
+		}
+		got := &testpb.TestAllTypes{}
+		if err := opts.Unmarshal(b, got); err != nil {
+			t.Fatalf("%+v: Unmarshal error: %v", opts, err)
+		}
+		if !proto.Equal(got, want) {
+			t.Errorf("%+v: Unmarshal() = %v, want %v", opts, marshalText(got), marshalText(want))
+		}
+		if n := cap(got.OptionalBytes); n != len(got.OptionalBytes) && opts.AliasBuffer {
+			t.Errorf("%+v: cap(OptionalBytes) = %v, want %v", opts, n, len(got.OptionalBytes))
+		}
+
+		// Modifying the input is only visible through aliased fields.
+		for i := range b {
+			b[i] = 'x'
+		}
+		if aliased := string(got.OptionalBytes) != "bytes"; aliased != opts.AliasBuffer {
+			t.Errorf("%+v: OptionalBytes aliases input = %v, want %v", opts, aliased, opts.AliasBuffer)
+		}
+		if aliased := string(got.RepeatedBytes[0]) != "a"; aliased != opts.AliasBuffer {
+			t.Errorf("%+v: RepeatedBytes aliases input = %v, want %v", opts, aliased, opts.AliasBuffer)
+		}
+		if got.GetOptionalString() != "string" && !opts.AliasStrings {
+			t.Errorf("%+v: OptionalString aliases input, want copy", opts)
+		}
+	}
+}
+
+func TestDecodeResolver(t *testing.T) {
+	xt := testpb.E_OptionalInt32Extension.Type
+	b, err := proto.Marshal(build(
+		&testpb.TestAllExtensions{},
+		extend(testpb.E_OptionalInt32Extension, scalar.Int32(1)),
+	))
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	for _, test := range []struct {
+		desc     string
+		resolver *protoregistry.Types
+		wantExt  bool
+	}{
+		{"default", nil, true},
+		{"global", protoregistry.GlobalTypes, true},
+		{"empty", protoregistry.NewTypes(), false},
+		{"custom", protoregistry.NewTypes(xt), true},
+		{"parent", &protoregistry.Types{Parent: protoregistry.NewTypes(xt)}, true},
+	} {
+		m := &testpb.TestAllExtensions{}
+		if err := (proto.UnmarshalOptions{Resolver: test.resolver}).Unmarshal(b, m); err != nil {
+			t.Errorf("%v: Unmarshal error: %v", test.desc, err)
+			continue
+		}
+		known := m.ProtoReflect().KnownFields()
+		if got := known.Has(xt.Number()); got != test.wantExt {
+			t.Errorf("%v: extension is set = %v, want %v", test.desc, got, test.wantExt)
+		}
+		unknown := m.ProtoReflect().UnknownFields().Get(xt.Number())
+		if got := unknown != nil; got == test.wantExt {
+			t.Errorf("%v: extension is an unknown field = %v, want %v", test.desc, got, !test.wantExt)
+		}
+		if test.wantExt && known.Get(xt.Number()).Int() != 1 {
+			t.Errorf("%v: extension = %v, want 1", test.desc, known.Get(xt.Number()))
+		}
+	}
+}
+
+func TestDecodeDiscardUnknown(t *testing.T) {
+	b := pack.Message{
+		pack.Tag{1, pack.VarintType}, pack.Varint(1),
+		pack.Tag{100000, pack.BytesType}, pack.String("unknown"),
+	}.Marshal()
+	md := (&testpb.TestAllTypes{}).ProtoReflect().Type()
+	for _, m := range []proto.Message{
+		&testpb.TestAllTypes{},
+		// Dynamic messages are always unmarshaled by the reflective implementation.
+		dynamicpb.NewMessage(md),
+	} {
+		if err := (proto.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(b, m); err != nil {
+			t.Errorf("%T: Unmarshal error: %v", m, err)
+			continue
+		}
+		if got := m.ProtoReflect().KnownFields().Get(1).Int(); got != 1 {
+			t.Errorf("%T: optional_int32 = %v, want 1", m, got)
+		}
+		if got := m.ProtoReflect().UnknownFields().Len(); got != 0 {
+			t.Errorf("%T: UnknownFields().Len() = %v, want 0", m, got)
+		}
+	}
+}
+
+func TestDecodeClosedEnum(t *testing.T) {
+	unknownEnum := func(num pref.FieldNumber, v int64) pack.Message {
+		return pack.Message{pack.Tag{num, pack.VarintType}, pack.Varint(v)}
+	}
+	unknownEntry := pack.Message{
+		pack.Tag{73, pack.BytesType}, pack.LengthPrefix(pack.Message{
+			pack.Tag{1, pack.BytesType}, pack.String("unknown"),
+			pack.Tag{2, pack.VarintType}, pack.Varint(8),
+		}),
+	}
+	b := pack.Message{
+		unknownEnum(21, 5),
+		pack.Tag{51, pack.VarintType}, pack.Varint(1),
+		unknownEnum(51, 6),
+		pack.Tag{51, pack.BytesType}, pack.LengthPrefix{pack.Varint(2), pack.Varint(7), pack.Varint(-1)},
+		unknownEntry,
+		pack.Tag{73, pack.BytesType}, pack.LengthPrefix(pack.Message{
+			pack.Tag{1, pack.BytesType}, pack.String("known"),
+			pack.Tag{2, pack.VarintType}, pack.Varint(1),
+		}),
+		unknownEnum(119, 9),
+	}.Marshal()
+
+	// Unknown values of the proto2 enums are placed in the unknown fields.
+	want := build(
+		&testpb.TestAllTypes{
+			RepeatedNestedEnum: []testpb.TestAllTypes_NestedEnum{
+				testpb.TestAllTypes_BAR,
+				testpb.TestAllTypes_BAZ,
+				testpb.TestAllTypes_NEG,
+			},
+			MapStringNestedEnum: map[string]testpb.TestAllTypes_NestedEnum{
+				"known": testpb.TestAllTypes_BAR,
+			},
+		},
+		unknown(21, unknownEnum(21, 5).Marshal()),
+		unknown(51, append(unknownEnum(51, 6).Marshal(), unknownEnum(51, 7).Marshal()...)),
+		unknown(73, unknownEntry.Marshal()),
+		unknown(119, unknownEnum(119, 9).Marshal()),
+	)
+	got := &testpb.TestAllTypes{}
+	if err := proto.Unmarshal(b, got); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("Unmarshal proto2 message:\ngot:\n%v\nwant:\n%v\ndiff:\n%v", marshalText(got), marshalText(want), proto.Diff(want, got))
+	}
+
+	// The unknown values survive a round trip.
+	b2, err := proto.Marshal(got)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	got2 := &testpb.TestAllTypes{}
+	if err := proto.Unmarshal(b2, got2); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if !proto.Equal(got2, want) {
+		t.Errorf("Unmarshal after round trip:\ngot:\n%v\nwant:\n%v", marshalText(got2), marshalText(want))
+	}
+
+	// Proto3 enums are open and hold unknown values.
+	want3 := &test3pb.TestAllTypes{
+		OptionalNestedEnum: 5,
+		RepeatedNestedEnum: []test3pb.TestAllTypes_NestedEnum{1, 6, 2, 7, -1},
+		MapStringNestedEnum: map[string]test3pb.TestAllTypes_NestedEnum{
+			"unknown": 8,
+			"known":   1,
+		},
+		OneofField: &test3pb.TestAllTypes_OneofEnum{OneofEnum: 9},
+	}
+	got3 := &test3pb.TestAllTypes{}
+	if err := proto.Unmarshal(b, got3); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if !proto.Equal(got3, want3) {
+		t.Errorf("Unmarshal proto3 message:\ngot:\n%v\nwant:\n%v", marshalText(got3), marshalText(want3))
+	}
+}
+
+// invalidUTF8Tests are messages containing strings which are not valid UTF-8,
+// and the path to the first such string if it is an error.
+var invalidUTF8Tests = []struct {
+	desc     string
+	m        proto.Message
+	wantPath string
+}{{
+	desc:     "singular",
+	m:        &test3pb.TestAllTypes{OptionalString: "abc\xff"},
+	wantPath: "optional_string",
+}, {
+	desc:     "repeated",
+	m:        &test3pb.TestAllTypes{RepeatedString: []string{"a", "b\xff"}},
+	wantPath: "repeated_string[1]",
+}, {
+	desc:     "map key",
+	m:        &test3pb.TestAllTypes{MapStringString: map[string]string{"\xff": "v"}},
+	wantPath: "map_string_string[\xff]",
+}, {
+	desc:     "map value",
+	m:        &test3pb.TestAllTypes{MapStringString: map[string]string{"k": "\xff"}},
+	wantPath: "map_string_string[k]",
+}, {
+	desc:     "oneof",
+	m:        &test3pb.TestAllTypes{OneofField: &test3pb.TestAllTypes_OneofString{OneofString: "\xff"}},
+	wantPath: "oneof_string",
+}, {
+	desc: "nested",
+	m: &test3pb.TestAllTypes{
+		OptionalNestedMessage: &test3pb.TestAllTypes_NestedMessage{
+			Corecursive: &test3pb.TestAllTypes{OptionalString: "\xff"},
+		},
+	},
+	wantPath: "optional_nested_message.corecursive.optional_string",
+}, {
+	desc: "proto2",
+	m:    &testpb.TestAllTypes{OptionalString: scalar.String("\xff")},
+}}
+
+func TestDecodeInvalidUTF8(t *testing.T) {
+	for _, test := range invalidUTF8Tests {
+		b, _ := proto.Marshal(test.m)
+		got := reflect.New(reflect.TypeOf(test.m).Elem()).Interface().(proto.Message)
+		err := proto.Unmarshal(b, got)
+		checkInvalidUTF8(t, "Unmarshal", test.desc, err, test.wantPath)
+		if !proto.Equal(got, test.m) {
+			t.Errorf("%v: Unmarshal() = %v, want %v", test.desc, marshalText(got), marshalText(test.m))
+		}
+	}
+
+	// The offset is that of the string data in the input.
+	var e *proto.InvalidUTF8Error
+	err := proto.Unmarshal([]byte("\x72\x02a\xff"), &test3pb.TestAllTypes{})
+	if !errors.As(err, &e) || e.Offset != 2 {
+		t.Errorf("Unmarshal() = %v, want InvalidUTF8Error at offset 2", err)
+	}
+}
+
+// checkInvalidUTF8 checks that err is a non-fatal InvalidUTF8Error with
+// the given path, or nil if the path is empty.
+func checkInvalidUTF8(t *testing.T, op, desc string, err error, wantPath string) {
+	t.Helper()
+	if wantPath == "" {
+		if err != nil {
+			t.Errorf("%v: %v() = %v, want nil", desc, op, err)
+		}
+		return
+	}
+	var e *proto.InvalidUTF8Error
+	if !errors.As(err, &e) {
+		t.Errorf("%v: %v() = %v, want InvalidUTF8Error", desc, op, err)
+		return
+	}
+	if e.Path != wantPath {
+		t.Errorf("%v: %v() error path = %q, want %q", desc, op, e.Path, wantPath)
+	}
+}
+
+func TestDecodeErrorPath(t *testing.T) {
+	b := pack.Message{
+		pack.Tag{48, pack.BytesType}, pack.LengthPrefix(pack.Message{
+			pack.Tag{1, pack.VarintType}, pack.Varint(1),
+		}),
+		pack.Tag{48, pack.BytesType}, pack.LengthPrefix(pack.Message{
+			pack.Tag{2, pack.BytesType}, pack.LengthPrefix(pack.Message{
+				pack.Tag{18, pack.BytesType}, pack.LengthPrefix(pack.Message{
+					pack.Tag{1, pack.VarintType}, pack.Raw{0x80},
+				}),
+			}),
+		}),
+	}.Marshal()
+	for _, test := range []struct {
+		m        proto.Message
+		wantPath string
+	}{
+		{&testpb.TestAllTypes{}, "repeated_nested_message[1].corecursive.optional_nested_message.a"},
+		{build(
+			&testpb.TestAllExtensions{},
+			extend(testpb.E_RepeatedNestedMessageExtension, []*testpb.TestAllTypes_NestedMessage{}),
+		), "(goproto.proto.test.repeated_nested_message_extension)[1].corecursive.optional_nested_message.a"},
+	} {
+		err := proto.Unmarshal(b, test.m)
+		var e *proto.ParseError
+		if !errors.As(err, &e) {
+			t.Errorf("%T: Unmarshal() = %v, want ParseError", test.m, err)
+			continue
+		}
+		if e.Path != test.wantPath {
+			t.Errorf("%T: ParseError.Path = %q, want %q", test.m, e.Path, test.wantPath)
+		}
+		if want := len(b) - 1; e.Offset != want {
+			t.Errorf("%T: ParseError.Offset = %v, want %v", test.m, e.Offset, want)
+		}
+	}
+
+	// Depth errors report the field at which the limit was exceeded.
+	err := proto.UnmarshalOptions{RecursionLimit: 2}.Unmarshal(b, &testpb.TestAllTypes{})
+	var e *proto.RecursionLimitError
+	if !errors.As(err, &e) {
+		t.Fatalf("Unmarshal with limit 2 = %v, want RecursionLimitError", err)
+	}
+	if want := "repeated_nested_message[1].corecursive"; e.Path != want {
+		t.Errorf("RecursionLimitError.Path = %q, want %q", e.Path, want)
+	}
+}
+
 var testProtos = []testProto{
 	{
 		desc: "basic scalar types",
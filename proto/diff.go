@@ -0,0 +1,270 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/v2/internal/mapsort"
+	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+)
+
+// DiffKind is the kind of a difference between two messages.
+type DiffKind int
+
+const (
+	// DiffAdded is a value present only in the second message.
+	DiffAdded DiffKind = iota + 1
+	// DiffRemoved is a value present only in the first message.
+	DiffRemoved
+	// DiffModified is a value present in both messages which differs.
+	DiffModified
+)
+
+func (k DiffKind) String() string {
+	switch k {
+	case DiffAdded:
+		return "added"
+	case DiffRemoved:
+		return "removed"
+	case DiffModified:
+		return "modified"
+	default:
+		return fmt.Sprintf("<unknown:%d>", k)
+	}
+}
+
+// Difference is a single difference between two messages.
+type Difference struct {
+	// Path is the path to the value which differs, such as "a.b[3].c".
+	// Lists are indexed by position and maps by key. Extension fields are
+	// named by their full name in parentheses, and unknown fields by their
+	// field number. The path is empty if the messages have different types.
+	Path string
+
+	// Kind is the kind of the difference.
+	Kind DiffKind
+
+	// Old and New are the values in the first and second message.
+	// Old is invalid for DiffAdded and New is invalid for DiffRemoved.
+	// The values of unknown fields are their raw wire encoding as bytes.
+	Old, New pref.Value
+}
+
+// String formats the difference as a single line.
+func (d Difference) String() string {
+	path := d.Path
+	if path == "" {
+		path = "<message>"
+	}
+	switch d.Kind {
+	case DiffAdded:
+		return fmt.Sprintf("added %v: %v", path, formatValue(d.New))
+	case DiffRemoved:
+		return fmt.Sprintf("removed %v: %v", path, formatValue(d.Old))
+	default:
+		return fmt.Sprintf("%v %v: %v -> %v", d.Kind, path, formatValue(d.Old), formatValue(d.New))
+	}
+}
+
+// Differences is a list of differences between two messages.
+type Differences []Difference
+
+// String formats the differences as a human-readable report
+// with one difference per line.
+func (ds Differences) String() string {
+	var b strings.Builder
+	for _, d := range ds {
+		b.WriteString(d.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Diff returns the differences between two messages, or nil if they are
+// equal as reported by Equal.
+//
+// Messages of different types differ as a whole. Otherwise, populated fields
+// are compared in field number order, recursing into message values present
+// in both messages. Lists are compared element by element, with elements past
+// the end of the shorter list reported as added or removed. Map entries are
+// compared by key. Unknown fields are compared per field number as raw bytes.
+func Diff(x, y Message) Differences {
+	var d differ
+	d.diffMessage("", x.ProtoReflect(), y.ProtoReflect())
+	return d.diffs
+}
+
+type differ struct {
+	diffs Differences
+}
+
+func (d *differ) add(path string, kind DiffKind, x, y pref.Value) {
+	d.diffs = append(d.diffs, Difference{Path: path, Kind: kind, Old: x, New: y})
+}
+
+func (d *differ) diffMessage(path string, mx, my pref.Message) {
+	if mx.Type().FullName() != my.Type().FullName() {
+		d.add(path, DiffModified, pref.ValueOf(mx), pref.ValueOf(my))
+		return
+	}
+	d.diffKnown(path, mx, my)
+	d.diffUnknown(path, mx.UnknownFields(), my.UnknownFields())
+}
+
+func (d *differ) diffKnown(path string, mx, my pref.Message) {
+	fields := mx.Type().Fields()
+	kx, ky := mx.KnownFields(), my.KnownFields()
+	var nums []pref.FieldNumber
+	kx.Range(func(num pref.FieldNumber, _ pref.Value) bool {
+		nums = append(nums, num)
+		return true
+	})
+	ky.Range(func(num pref.FieldNumber, _ pref.Value) bool {
+		if !kx.Has(num) {
+			nums = append(nums, num)
+		}
+		return true
+	})
+	sort.Slice(nums, func(i, j int) bool { return nums[i] < nums[j] })
+	for _, num := range nums {
+		field := fields.ByNumber(num)
+		if field == nil {
+			if field = kx.ExtensionTypes().ByNumber(num); field == nil {
+				field = ky.ExtensionTypes().ByNumber(num)
+			}
+			if field == nil {
+				panic(fmt.Errorf("no descriptor for field %d in %q", num, mx.Type().FullName()))
+			}
+		}
+		fieldPath := joinPath(path, fieldName(field))
+		switch {
+		case !ky.Has(num):
+			d.add(fieldPath, DiffRemoved, kx.Get(num), pref.Value{})
+		case !kx.Has(num):
+			d.add(fieldPath, DiffAdded, pref.Value{}, ky.Get(num))
+		default:
+			d.diffField(fieldPath, field, kx.Get(num), ky.Get(num))
+		}
+	}
+}
+
+func (d *differ) diffUnknown(path string, ux, uy pref.UnknownFields) {
+	var nums []pref.FieldNumber
+	ux.Range(func(num pref.FieldNumber, _ pref.RawFields) bool {
+		nums = append(nums, num)
+		return true
+	})
+	uy.Range(func(num pref.FieldNumber, _ pref.RawFields) bool {
+		if ux.Get(num) == nil {
+			nums = append(nums, num)
+		}
+		return true
+	})
+	sort.Slice(nums, func(i, j int) bool { return nums[i] < nums[j] })
+	for _, num := range nums {
+		rx, ry := ux.Get(num), uy.Get(num)
+		fieldPath := joinPath(path, strconv.Itoa(int(num)))
+		switch {
+		case ry == nil:
+			d.add(fieldPath, DiffRemoved, pref.ValueOf([]byte(rx)), pref.Value{})
+		case rx == nil:
+			d.add(fieldPath, DiffAdded, pref.Value{}, pref.ValueOf([]byte(ry)))
+		case !bytes.Equal(rx, ry):
+			d.add(fieldPath, DiffModified, pref.ValueOf([]byte(rx)), pref.ValueOf([]byte(ry)))
+		}
+	}
+}
+
+// diffField compares two values of the field described by fd.
+func (d *differ) diffField(path string, fd pref.FieldDescriptor, x, y pref.Value) {
+	switch {
+	case fd.IsMap():
+		d.diffMap(path, fd, x.Map(), y.Map())
+	case fd.Cardinality() == pref.Repeated:
+		d.diffList(path, fd, x.List(), y.List())
+	default:
+		d.diffValue(path, fd, x, y)
+	}
+}
+
+func (d *differ) diffMap(path string, fd pref.FieldDescriptor, x, y pref.Map) {
+	keyKind := fd.MessageType().Fields().ByNumber(1).Kind()
+	valField := fd.MessageType().Fields().ByNumber(2)
+	mapsort.Range(x, keyKind, func(k pref.MapKey, vx pref.Value) bool {
+		keyPath := path + "[" + fmt.Sprint(k) + "]"
+		if vy := y.Get(k); vy.IsValid() {
+			d.diffValue(keyPath, valField, vx, vy)
+		} else {
+			d.add(keyPath, DiffRemoved, vx, pref.Value{})
+		}
+		return true
+	})
+	mapsort.Range(y, keyKind, func(k pref.MapKey, vy pref.Value) bool {
+		if !x.Has(k) {
+			d.add(path+"["+fmt.Sprint(k)+"]", DiffAdded, pref.Value{}, vy)
+		}
+		return true
+	})
+}
+
+func (d *differ) diffList(path string, fd pref.FieldDescriptor, x, y pref.List) {
+	for i := 0; i < x.Len() || i < y.Len(); i++ {
+		elemPath := path + "[" + strconv.Itoa(i) + "]"
+		switch {
+		case i >= y.Len():
+			d.add(elemPath, DiffRemoved, x.Get(i), pref.Value{})
+		case i >= x.Len():
+			d.add(elemPath, DiffAdded, pref.Value{}, y.Get(i))
+		default:
+			d.diffValue(elemPath, fd, x.Get(i), y.Get(i))
+		}
+	}
+}
+
+// diffValue compares two singular values of the kind of fd.
+func (d *differ) diffValue(path string, fd pref.FieldDescriptor, x, y pref.Value) {
+	switch fd.Kind() {
+	case pref.MessageKind, pref.GroupKind:
+		d.diffMessage(path, x.Message(), y.Message())
+	default:
+		if !equalValue(fd, x, y) {
+			d.add(path, DiffModified, x, y)
+		}
+	}
+}
+
+// joinPath appends the field name to the path of the containing message.
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// formatValue formats a value in a difference report.
+func formatValue(v pref.Value) string {
+	switch x := v.Interface().(type) {
+	case nil:
+		return "<none>"
+	case string, []byte:
+		return fmt.Sprintf("%q", x)
+	case pref.Message:
+		if s, ok := x.Interface().(fmt.Stringer); ok {
+			return "{" + s.String() + "}"
+		}
+		return "{" + string(x.Type().FullName()) + "}"
+	case pref.List:
+		return fmt.Sprintf("<%d elements>", x.Len())
+	case pref.Map:
+		return fmt.Sprintf("<%d entries>", x.Len())
+	default:
+		return fmt.Sprint(x)
+	}
+}
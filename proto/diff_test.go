@@ -0,0 +1,120 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/v2/internal/encoding/pack"
+	"github.com/golang/protobuf/v2/internal/scalar"
+	"github.com/golang/protobuf/v2/proto"
+
+	testpb "github.com/golang/protobuf/v2/internal/testprotos/test"
+	test3pb "github.com/golang/protobuf/v2/internal/testprotos/test3"
+)
+
+func TestDiff(t *testing.T) {
+	for _, test := range []struct {
+		desc string
+		x, y proto.Message
+		want string
+	}{{
+		desc: "equal",
+		x:    &testpb.TestAllTypes{OptionalInt32: scalar.Int32(1)},
+		y:    &testpb.TestAllTypes{OptionalInt32: scalar.Int32(1)},
+		want: "",
+	}, {
+		desc: "different types",
+		x:    &testpb.TestAllTypes{},
+		y:    &test3pb.TestAllTypes{},
+		want: "modified <message>: {} -> {}\n",
+	}, {
+		desc: "scalars",
+		x: &testpb.TestAllTypes{
+			OptionalInt32:  scalar.Int32(1),
+			OptionalString: scalar.String("a"),
+		},
+		y: &testpb.TestAllTypes{
+			OptionalInt32: scalar.Int32(2),
+			OptionalBytes: []byte("b"),
+		},
+		want: "modified optional_int32: 1 -> 2\n" +
+			"removed optional_string: \"a\"\n" +
+			"added optional_bytes: \"b\"\n",
+	}, {
+		desc: "nested messages",
+		x: &testpb.TestAllTypes{
+			OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+				Corecursive: &testpb.TestAllTypes{OptionalInt32: scalar.Int32(1)},
+			},
+		},
+		y: &testpb.TestAllTypes{
+			OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+				A:           scalar.Int32(1),
+				Corecursive: &testpb.TestAllTypes{OptionalInt32: scalar.Int32(2)},
+			},
+		},
+		want: "added optional_nested_message.a: 1\n" +
+			"modified optional_nested_message.corecursive.optional_int32: 1 -> 2\n",
+	}, {
+		desc: "lists",
+		x:    &testpb.TestAllTypes{RepeatedInt32: []int32{1, 2, 3}},
+		y:    &testpb.TestAllTypes{RepeatedInt32: []int32{1, 5}},
+		want: "modified repeated_int32[1]: 2 -> 5\n" +
+			"removed repeated_int32[2]: 3\n",
+	}, {
+		desc: "maps",
+		x: &testpb.TestAllTypes{
+			MapInt32Int32: map[int32]int32{1: 1, 2: 2},
+			MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+				"k": {A: scalar.Int32(1)},
+			},
+		},
+		y: &testpb.TestAllTypes{
+			MapInt32Int32: map[int32]int32{2: 3, 4: 4},
+			MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+				"k": {A: scalar.Int32(2)},
+			},
+		},
+		want: "removed map_int32_int32[1]: 1\n" +
+			"modified map_int32_int32[2]: 2 -> 3\n" +
+			"added map_int32_int32[4]: 4\n" +
+			"modified map_string_nested_message[k].a: 1 -> 2\n",
+	}, {
+		desc: "extensions",
+		x: build(
+			&testpb.TestAllExtensions{},
+			extend(testpb.E_OptionalInt32Extension, scalar.Int32(1)),
+		),
+		y: build(
+			&testpb.TestAllExtensions{},
+			extend(testpb.E_OptionalInt32Extension, scalar.Int32(2)),
+			extend(testpb.E_RepeatedStringExtension, []string{"a"}),
+		),
+		want: "modified (goproto.proto.test.optional_int32_extension): 1 -> 2\n" +
+			"added (goproto.proto.test.repeated_string_extension): <1 elements>\n",
+	}, {
+		desc: "unknown fields",
+		x: build(
+			&testpb.TestAllTypes{},
+			unknown(100000, pack.Message{pack.Tag{100000, pack.VarintType}, pack.Varint(1)}.Marshal()),
+		),
+		y: build(
+			&testpb.TestAllTypes{},
+			unknown(100000, pack.Message{pack.Tag{100000, pack.VarintType}, pack.Varint(2)}.Marshal()),
+		),
+		want: "modified 100000: \"\\x80\\xea0\\x01\" -> \"\\x80\\xea0\\x02\"\n",
+	}} {
+		t.Run(test.desc, func(t *testing.T) {
+			diffs := proto.Diff(test.x, test.y)
+			if got := diffs.String(); got != test.want {
+				t.Errorf("Diff(x, y):\ngot:\n%v\nwant:\n%v", got, test.want)
+			}
+			if eq := proto.Equal(test.x, test.y); eq != (len(diffs) == 0) {
+				t.Errorf("Equal(x, y) = %v, but Diff(x, y) reported %d differences", eq, len(diffs))
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"fmt"
+
+	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+)
+
+// DiscardUnknown recursively discards the unknown fields of m and of every
+// message within it: the values of message fields, the elements of lists,
+// the values of maps, and populated extension fields.
+//
+// It has the same effect as the DiscardUnknown option of UnmarshalOptions,
+// applied to a message after it has been constructed.
+func DiscardUnknown(m Message) {
+	discardUnknown(m.ProtoReflect())
+}
+
+func discardUnknown(m pref.Message) {
+	// Visit the known fields first, which decodes any lazily decoded
+	// fields held in the unknown fields before they are discarded.
+	fields := m.Type().Fields()
+	knownFields := m.KnownFields()
+	knownFields.Range(func(num pref.FieldNumber, v pref.Value) bool {
+		field := fields.ByNumber(num)
+		if field == nil {
+			field = knownFields.ExtensionTypes().ByNumber(num)
+			if field == nil {
+				panic(fmt.Errorf("no descriptor for field %d in %q", num, m.Type().FullName()))
+			}
+		}
+		switch {
+		case field.IsMap():
+			if field.MessageType().Fields().ByNumber(2).MessageType() != nil {
+				v.Map().Range(func(_ pref.MapKey, v pref.Value) bool {
+					discardUnknown(v.Message())
+					return true
+				})
+			}
+		case field.MessageType() == nil:
+		case field.Cardinality() == pref.Repeated:
+			for i, list := 0, v.List(); i < list.Len(); i++ {
+				discardUnknown(list.Get(i).Message())
+			}
+		default:
+			discardUnknown(v.Message())
+		}
+		return true
+	})
+	unknownFields := m.UnknownFields()
+	unknownFields.Range(func(num pref.FieldNumber, _ pref.RawFields) bool {
+		unknownFields.Set(num, nil)
+		return true
+	})
+}
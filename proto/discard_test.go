@@ -0,0 +1,94 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/v2/internal/encoding/pack"
+	"github.com/golang/protobuf/v2/internal/scalar"
+	"github.com/golang/protobuf/v2/proto"
+
+	testpb "github.com/golang/protobuf/v2/internal/testprotos/test"
+)
+
+func TestDiscardUnknown(t *testing.T) {
+	raw := pack.Message{
+		pack.Tag{100000, pack.VarintType}, pack.Varint(1),
+	}.Marshal()
+	withUnknown := func(m proto.Message) proto.Message {
+		return build(m, unknown(100000, raw))
+	}
+
+	for _, test := range []struct {
+		desc string
+		in   proto.Message
+		want proto.Message
+	}{{
+		desc: "top level",
+		in:   withUnknown(&testpb.TestAllTypes{OptionalInt32: scalar.Int32(1)}),
+		want: &testpb.TestAllTypes{OptionalInt32: scalar.Int32(1)},
+	}, {
+		desc: "nested messages",
+		in: &testpb.TestAllTypes{
+			Optionalgroup: withUnknown(&testpb.TestAllTypes_OptionalGroup{}).(*testpb.TestAllTypes_OptionalGroup),
+			OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+				Corecursive: withUnknown(&testpb.TestAllTypes{}).(*testpb.TestAllTypes),
+			},
+		},
+		want: &testpb.TestAllTypes{
+			Optionalgroup: &testpb.TestAllTypes_OptionalGroup{},
+			OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+				Corecursive: &testpb.TestAllTypes{},
+			},
+		},
+	}, {
+		desc: "lists and maps",
+		in: &testpb.TestAllTypes{
+			RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{
+				{A: scalar.Int32(1)},
+				withUnknown(&testpb.TestAllTypes_NestedMessage{}).(*testpb.TestAllTypes_NestedMessage),
+			},
+			MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+				"k": withUnknown(&testpb.TestAllTypes_NestedMessage{A: scalar.Int32(2)}).(*testpb.TestAllTypes_NestedMessage),
+			},
+			OneofField: &testpb.TestAllTypes_OneofNestedMessage{
+				withUnknown(&testpb.TestAllTypes_NestedMessage{}).(*testpb.TestAllTypes_NestedMessage),
+			},
+		},
+		want: &testpb.TestAllTypes{
+			RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{
+				{A: scalar.Int32(1)},
+				{},
+			},
+			MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+				"k": {A: scalar.Int32(2)},
+			},
+			OneofField: &testpb.TestAllTypes_OneofNestedMessage{
+				&testpb.TestAllTypes_NestedMessage{},
+			},
+		},
+	}, {
+		desc: "extensions",
+		in: build(&testpb.TestAllExtensions{},
+			extend(testpb.E_OptionalNestedMessageExtension, withUnknown(&testpb.TestAllTypes_NestedMessage{})),
+			extend(testpb.E_RepeatedNestedMessageExtension, []*testpb.TestAllTypes_NestedMessage{
+				withUnknown(&testpb.TestAllTypes_NestedMessage{}).(*testpb.TestAllTypes_NestedMessage),
+			}),
+			unknown(100000, raw),
+		),
+		want: build(&testpb.TestAllExtensions{},
+			extend(testpb.E_OptionalNestedMessageExtension, &testpb.TestAllTypes_NestedMessage{}),
+			extend(testpb.E_RepeatedNestedMessageExtension, []*testpb.TestAllTypes_NestedMessage{{}}),
+		),
+	}} {
+		t.Run(test.desc, func(t *testing.T) {
+			proto.DiscardUnknown(test.in)
+			if !proto.Equal(test.in, test.want) {
+				t.Errorf("DiscardUnknown() result differs:\ngot:  %v\nwant: %v", marshalText(test.in), marshalText(test.want))
+			}
+		})
+	}
+}
@@ -7,8 +7,10 @@ package proto
 import (
 	"fmt"
 	"sort"
+	"strconv"
+	"unicode/utf8"
 
-	"github.com/golang/protobuf/v2/internal/encoding/wire"
+	"github.com/golang/protobuf/v2/encoding/protowire"
 	"github.com/golang/protobuf/v2/internal/errors"
 	"github.com/golang/protobuf/v2/internal/mapsort"
 	"github.com/golang/protobuf/v2/internal/pragma"
@@ -38,15 +40,28 @@ type MarshalOptions struct {
 	// languages. It is not guaranteed to remain stable over time. It is
 	// unstable across different builds with schema changes due to unknown
 	// fields. Users who need canonical serialization (e.g., persistent
-	// storage in a canonical form, fingerprinting, etc.) must define
-	// their own canonicalization specification and implement their own
-	// serializer rather than relying on this API.
+	// storage in a canonical form, fingerprinting, etc.) should use
+	// Canonical instead.
 	//
 	// If deterministic serialization is requested, map entries will be
 	// sorted by keys in lexographical order. This is an implementation
 	// detail and subject to change.
 	Deterministic bool
 
+	// Canonical requests a canonical serialization, such that equal messages
+	// are serialized to the same bytes regardless of which binary or version
+	// of the schema serializes them. It implies Deterministic.
+	//
+	// In canonical form, known fields, extension fields, and unknown fields
+	// are written in field number order, with unknown fields following any
+	// known field of the same number. Map entries are sorted by key, and
+	// repeated scalar fields always use the packed encoding. The contents of
+	// unknown fields are written unchanged.
+	//
+	// Canonical serialization always uses the reflective implementation,
+	// and is slower than the default serialization.
+	Canonical bool
+
 	// UseCachedSize indicates that the result of a previous Size call
 	// may be reused.
 	//
@@ -85,6 +100,9 @@ func (o MarshalOptions) Marshal(m Message) ([]byte, error) {
 // MarshalAppend appends the wire-format encoding of m to b,
 // returning the result.
 func (o MarshalOptions) MarshalAppend(b []byte, m Message) ([]byte, error) {
+	if o.Canonical {
+		o.Deterministic = true
+	}
 	out, err := o.marshalMessageFast(b, m)
 	if err == errInternalNoFast {
 		out, err = o.marshalMessage(b, m.ProtoReflect())
@@ -103,11 +121,14 @@ func (o MarshalOptions) marshalMessageFast(b []byte, m Message) ([]byte, error)
 	methods := protoMethods(m)
 	if methods == nil ||
 		methods.MarshalAppend == nil ||
+		o.Canonical ||
 		(o.Deterministic && methods.Flags&protoiface.MethodFlagDeterministicMarshal == 0) {
 		return nil, errInternalNoFast
 	}
 	if methods.Size != nil {
-		sz := methods.Size(m)
+		// If UseCachedSize is set, this reads the size cached by the
+		// caller's previous call to Size instead of recomputing it.
+		sz := methods.Size(m, protoiface.MarshalOptions(o))
 		if cap(b) < len(b)+sz {
 			x := make([]byte, len(b), len(b)+sz)
 			copy(x, b)
@@ -125,11 +146,29 @@ func (o MarshalOptions) marshalMessage(b []byte, m protoreflect.Message) ([]byte
 	// defined order.
 	//
 	// When using deterministic serialization, we sort the known fields by field number.
+	//
+	// When using canonical serialization, we additionally interleave the unknown
+	// fields with the known fields in field number order.
 	fields := m.Type().Fields()
 	knownFields := m.KnownFields()
+	unknownFields := m.UnknownFields()
+	var unknownNums []protoreflect.FieldNumber
+	if o.Canonical {
+		unknownFields.Range(func(num protoreflect.FieldNumber, _ protoreflect.RawFields) bool {
+			unknownNums = append(unknownNums, num)
+			return true
+		})
+		sort.Slice(unknownNums, func(a, b int) bool {
+			return unknownNums[a] < unknownNums[b]
+		})
+	}
 	var err error
 	var nerr errors.NonFatal
 	o.rangeKnown(knownFields, func(num protoreflect.FieldNumber, value protoreflect.Value) bool {
+		for len(unknownNums) > 0 && unknownNums[0] < num {
+			b = append(b, unknownFields.Get(unknownNums[0])...)
+			unknownNums = unknownNums[1:]
+		}
 		field := fields.ByNumber(num)
 		if field == nil {
 			field = knownFields.ExtensionTypes().ByNumber(num)
@@ -138,16 +177,24 @@ func (o MarshalOptions) marshalMessage(b []byte, m protoreflect.Message) ([]byte
 			}
 		}
 		b, err = o.marshalField(b, field, value)
-		if nerr.Merge(err) {
-			err = nil
-			return true
+		var ferr errors.NonFatal
+		if !ferr.Merge(err) {
+			return false
 		}
-		return false
+		nerr.Merge(errors.AddField(ferr.E, fieldName(field)))
+		err = nil
+		return true
 	})
 	if err != nil {
 		return b, err
 	}
-	m.UnknownFields().Range(func(_ protoreflect.FieldNumber, raw protoreflect.RawFields) bool {
+	if o.Canonical {
+		for _, num := range unknownNums {
+			b = append(b, unknownFields.Get(num)...)
+		}
+		return b, nerr.E
+	}
+	unknownFields.Range(func(_ protoreflect.FieldNumber, raw protoreflect.RawFields) bool {
 		b = append(b, raw...)
 		return true
 	})
@@ -181,35 +228,50 @@ func (o MarshalOptions) marshalField(b []byte, field protoreflect.FieldDescripto
 	kind := field.Kind()
 	switch {
 	case field.Cardinality() != protoreflect.Repeated:
-		b = wire.AppendTag(b, num, wireTypes[kind])
-		return o.marshalSingular(b, num, kind, value)
+		b = protowire.AppendTag(b, num, wireTypes[kind])
+		b, err := o.marshalSingular(b, num, kind, value)
+		if err == nil && enforceUTF8(field) && !utf8.ValidString(value.String()) {
+			err = &errors.InvalidUTF8Error{Offset: -1}
+		}
+		return b, err
 	case field.IsMap():
 		return o.marshalMap(b, num, kind, field.MessageType(), value.Map())
-	case field.IsPacked():
+	case field.IsPacked() || (o.Canonical && isPackable(kind)):
 		return o.marshalPacked(b, num, kind, value.List())
 	default:
-		return o.marshalList(b, num, kind, value.List())
+		return o.marshalList(b, field, value.List())
+	}
+}
+
+// isPackable reports whether repeated fields of the kind may be packed.
+func isPackable(kind protoreflect.Kind) bool {
+	switch wireTypes[kind] {
+	case protowire.VarintType, protowire.Fixed32Type, protowire.Fixed64Type:
+		return true
 	}
+	return false
 }
 
-func (o MarshalOptions) marshalMap(b []byte, num wire.Number, kind protoreflect.Kind, mdesc protoreflect.MessageDescriptor, mapv protoreflect.Map) ([]byte, error) {
+func (o MarshalOptions) marshalMap(b []byte, num protowire.Number, kind protoreflect.Kind, mdesc protoreflect.MessageDescriptor, mapv protoreflect.Map) ([]byte, error) {
 	keyf := mdesc.Fields().ByNumber(1)
 	valf := mdesc.Fields().ByNumber(2)
 	var nerr errors.NonFatal
 	var err error
 	o.rangeMap(mapv, keyf.Kind(), func(key protoreflect.MapKey, value protoreflect.Value) bool {
-		b = wire.AppendTag(b, num, wire.BytesType)
+		b = protowire.AppendTag(b, num, protowire.BytesType)
 		var pos int
 		b, pos = appendSpeculativeLength(b)
 
+		var eerr errors.NonFatal
 		b, err = o.marshalField(b, keyf, key.Value())
-		if !nerr.Merge(err) {
+		if !eerr.Merge(err) {
 			return false
 		}
 		b, err = o.marshalField(b, valf, value)
-		if !nerr.Merge(err) {
+		if !eerr.Merge(err) {
 			return false
 		}
+		nerr.Merge(errors.AddIndex(eerr.E, fmt.Sprint(key)))
 		err = nil
 
 		b = finishSpeculativeLength(b, pos)
@@ -229,8 +291,8 @@ func (o MarshalOptions) rangeMap(mapv protoreflect.Map, kind protoreflect.Kind,
 	mapsort.Range(mapv, kind, f)
 }
 
-func (o MarshalOptions) marshalPacked(b []byte, num wire.Number, kind protoreflect.Kind, list protoreflect.List) ([]byte, error) {
-	b = wire.AppendTag(b, num, wire.BytesType)
+func (o MarshalOptions) marshalPacked(b []byte, num protowire.Number, kind protoreflect.Kind, list protoreflect.List) ([]byte, error) {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
 	b, pos := appendSpeculativeLength(b)
 	var nerr errors.NonFatal
 	for i, llen := 0, list.Len(); i < llen; i++ {
@@ -244,15 +306,22 @@ func (o MarshalOptions) marshalPacked(b []byte, num wire.Number, kind protorefle
 	return b, nerr.E
 }
 
-func (o MarshalOptions) marshalList(b []byte, num wire.Number, kind protoreflect.Kind, list protoreflect.List) ([]byte, error) {
+func (o MarshalOptions) marshalList(b []byte, field protoreflect.FieldDescriptor, list protoreflect.List) ([]byte, error) {
+	num, kind := field.Number(), field.Kind()
 	var nerr errors.NonFatal
 	for i, llen := 0, list.Len(); i < llen; i++ {
 		var err error
-		b = wire.AppendTag(b, num, wireTypes[kind])
-		b, err = o.marshalSingular(b, num, kind, list.Get(i))
-		if !nerr.Merge(err) {
+		v := list.Get(i)
+		b = protowire.AppendTag(b, num, wireTypes[kind])
+		b, err = o.marshalSingular(b, num, kind, v)
+		if err == nil && enforceUTF8(field) && !utf8.ValidString(v.String()) {
+			err = &errors.InvalidUTF8Error{Offset: -1}
+		}
+		var eerr errors.NonFatal
+		if !eerr.Merge(err) {
 			return b, err
 		}
+		nerr.Merge(errors.AddIndex(eerr.E, strconv.Itoa(i)))
 	}
 	return b, nerr.E
 }
@@ -270,7 +339,7 @@ func appendSpeculativeLength(b []byte) ([]byte, int) {
 
 func finishSpeculativeLength(b []byte, pos int) []byte {
 	mlen := len(b) - pos - speculativeLength
-	msiz := wire.SizeVarint(uint64(mlen))
+	msiz := protowire.SizeVarint(uint64(mlen))
 	if msiz != speculativeLength {
 		for i := 0; i < msiz-speculativeLength; i++ {
 			b = append(b, 0)
@@ -278,6 +347,6 @@ func finishSpeculativeLength(b []byte, pos int) []byte {
 		copy(b[pos+msiz:], b[pos+speculativeLength:])
 		b = b[:pos+msiz+mlen]
 	}
-	wire.AppendVarint(b[:pos], uint64(mlen))
+	protowire.AppendVarint(b[:pos], uint64(mlen))
 	return b
 }
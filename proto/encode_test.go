@@ -7,9 +7,12 @@ import (
 	"testing"
 
 	protoV1 "github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/v2/internal/encoding/pack"
+	"github.com/golang/protobuf/v2/internal/scalar"
 	"github.com/golang/protobuf/v2/proto"
 	"github.com/google/go-cmp/cmp"
 
+	testpb "github.com/golang/protobuf/v2/internal/testprotos/test"
 	test3pb "github.com/golang/protobuf/v2/internal/testprotos/test3"
 )
 
@@ -92,6 +95,80 @@ func TestEncodeDeterministic(t *testing.T) {
 	}
 }
 
+func TestEncodeCanonical(t *testing.T) {
+	for _, test := range []struct {
+		desc string
+		m    proto.Message
+		want pack.Message
+	}{{
+		desc: "known and unknown fields",
+		m: build(
+			&testpb.TestAllTypes{
+				OptionalInt32: scalar.Int32(1),
+				RepeatedInt32: []int32{1, 2},
+				MapInt32Int32: map[int32]int32{2: 3, 1: 4},
+			},
+			unknown(1000, pack.Message{
+				pack.Tag{1000, pack.VarintType}, pack.Varint(6),
+			}.Marshal()),
+			unknown(40, pack.Message{
+				pack.Tag{40, pack.VarintType}, pack.Varint(5),
+			}.Marshal()),
+			unknown(1, pack.Message{
+				pack.Tag{1, pack.Fixed32Type}, pack.Uint32(7),
+			}.Marshal()),
+		),
+		want: pack.Message{
+			pack.Tag{1, pack.VarintType}, pack.Varint(1),
+			pack.Tag{1, pack.Fixed32Type}, pack.Uint32(7),
+			pack.Tag{31, pack.BytesType}, pack.LengthPrefix(pack.Message{
+				pack.Varint(1), pack.Varint(2),
+			}),
+			pack.Tag{40, pack.VarintType}, pack.Varint(5),
+			pack.Tag{56, pack.BytesType}, pack.LengthPrefix(pack.Message{
+				pack.Tag{1, pack.VarintType}, pack.Varint(1),
+				pack.Tag{2, pack.VarintType}, pack.Varint(4),
+			}),
+			pack.Tag{56, pack.BytesType}, pack.LengthPrefix(pack.Message{
+				pack.Tag{1, pack.VarintType}, pack.Varint(2),
+				pack.Tag{2, pack.VarintType}, pack.Varint(3),
+			}),
+			pack.Tag{1000, pack.VarintType}, pack.Varint(6),
+		},
+	}, {
+		desc: "extension and unknown fields",
+		m: build(
+			&testpb.TestAllExtensions{},
+			extend(testpb.E_RepeatedInt32Extension, []int32{3, 4}),
+			unknown(2, pack.Message{
+				pack.Tag{2, pack.VarintType}, pack.Varint(2),
+			}.Marshal()),
+			extend(testpb.E_OptionalInt32Extension, scalar.Int32(1)),
+		),
+		want: pack.Message{
+			pack.Tag{1, pack.VarintType}, pack.Varint(1),
+			pack.Tag{2, pack.VarintType}, pack.Varint(2),
+			pack.Tag{31, pack.BytesType}, pack.LengthPrefix(pack.Message{
+				pack.Varint(3), pack.Varint(4),
+			}),
+		},
+	}} {
+		t.Run(test.desc, func(t *testing.T) {
+			opts := proto.MarshalOptions{Canonical: true}
+			got, err := opts.Marshal(test.m)
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			if want := test.want.Marshal(); !bytes.Equal(got, want) {
+				t.Errorf("Marshal() = %x, want %x", got, want)
+			}
+			if size := opts.Size(test.m); size != len(got) {
+				t.Errorf("Size() = %v, want len(Marshal()) = %v", size, len(got))
+			}
+		})
+	}
+}
+
 func TestEncodeRequiredFieldChecks(t *testing.T) {
 	for _, test := range testProtos {
 		if !test.partial {
@@ -108,6 +185,18 @@ func TestEncodeRequiredFieldChecks(t *testing.T) {
 	}
 }
 
+func TestEncodeInvalidUTF8(t *testing.T) {
+	for _, test := range invalidUTF8Tests {
+		for _, opts := range []proto.MarshalOptions{{}, {Deterministic: true}, {Canonical: true}} {
+			b, err := opts.Marshal(test.m)
+			checkInvalidUTF8(t, "Marshal", test.desc, err, test.wantPath)
+			if len(b) == 0 {
+				t.Errorf("%v: Marshal() produced no output", test.desc)
+			}
+		}
+	}
+}
+
 func TestMarshalAppend(t *testing.T) {
 	want := []byte("prefix")
 	got := append([]byte(nil), want...)
@@ -0,0 +1,142 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+)
+
+// Equal reports whether two messages are equal.
+//
+// Two messages are equal if they have the same message type (by full name),
+// the same set of populated known and extension fields with equal values,
+// and the same unknown fields for each field number.
+//
+// Scalar values are compared with the equivalent of the == operator in Go,
+// except bytes values, which are compared using bytes.Equal,
+// and floating point values, where two NaNs are considered equal.
+// Message values are compared by recursively calling Equal.
+// Lists are equal if they have the same length and each pair of elements
+// is equal. Maps are equal if they have the same set of keys and the pair of
+// values for each key is equal.
+//
+// Unknown fields are compared per field number as raw bytes, such that the
+// relative ordering of unknown fields with different numbers is irrelevant.
+// Extension fields are compared by value and do not require the same set of
+// extension types to be registered on both messages.
+func Equal(x, y Message) bool {
+	return equalMessage(x.ProtoReflect(), y.ProtoReflect())
+}
+
+func equalMessage(mx, my pref.Message) bool {
+	if mx.Type().FullName() != my.Type().FullName() {
+		return false
+	}
+	return equalKnown(mx, my) && equalUnknown(mx.UnknownFields(), my.UnknownFields())
+}
+
+func equalKnown(mx, my pref.Message) bool {
+	fields := mx.Type().Fields()
+	kx, ky := mx.KnownFields(), my.KnownFields()
+	if kx.Len() != ky.Len() {
+		return false
+	}
+	equal := true
+	kx.Range(func(num pref.FieldNumber, vx pref.Value) bool {
+		field := fields.ByNumber(num)
+		if field == nil {
+			field = kx.ExtensionTypes().ByNumber(num)
+			if field == nil {
+				panic(fmt.Errorf("no descriptor for field %d in %q", num, mx.Type().FullName()))
+			}
+		}
+		equal = ky.Has(num) && equalField(field, vx, ky.Get(num))
+		return equal
+	})
+	return equal
+}
+
+func equalUnknown(ux, uy pref.UnknownFields) bool {
+	if ux.Len() != uy.Len() {
+		return false
+	}
+	equal := true
+	ux.Range(func(num pref.FieldNumber, raw pref.RawFields) bool {
+		equal = bytes.Equal(raw, uy.Get(num))
+		return equal
+	})
+	return equal
+}
+
+// equalField compares two values of the field described by fd.
+func equalField(fd pref.FieldDescriptor, x, y pref.Value) bool {
+	switch {
+	case fd.IsMap():
+		return equalMap(fd.MessageType().Fields().ByNumber(2), x.Map(), y.Map())
+	case fd.Cardinality() == pref.Repeated:
+		return equalList(fd, x.List(), y.List())
+	default:
+		return equalValue(fd, x, y)
+	}
+}
+
+func equalMap(valField pref.FieldDescriptor, x, y pref.Map) bool {
+	if x.Len() != y.Len() {
+		return false
+	}
+	equal := true
+	x.Range(func(k pref.MapKey, vx pref.Value) bool {
+		vy := y.Get(k)
+		equal = vy.IsValid() && equalValue(valField, vx, vy)
+		return equal
+	})
+	return equal
+}
+
+func equalList(fd pref.FieldDescriptor, x, y pref.List) bool {
+	if x.Len() != y.Len() {
+		return false
+	}
+	for i := x.Len() - 1; i >= 0; i-- {
+		if !equalValue(fd, x.Get(i), y.Get(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalValue compares two singular values of the kind of fd.
+func equalValue(fd pref.FieldDescriptor, x, y pref.Value) bool {
+	switch fd.Kind() {
+	case pref.BoolKind:
+		return x.Bool() == y.Bool()
+	case pref.EnumKind:
+		return x.Enum() == y.Enum()
+	case pref.Int32Kind, pref.Sint32Kind, pref.Sfixed32Kind,
+		pref.Int64Kind, pref.Sint64Kind, pref.Sfixed64Kind:
+		return x.Int() == y.Int()
+	case pref.Uint32Kind, pref.Fixed32Kind,
+		pref.Uint64Kind, pref.Fixed64Kind:
+		return x.Uint() == y.Uint()
+	case pref.FloatKind, pref.DoubleKind:
+		fx, fy := x.Float(), y.Float()
+		if math.IsNaN(fx) || math.IsNaN(fy) {
+			return math.IsNaN(fx) && math.IsNaN(fy)
+		}
+		return fx == fy
+	case pref.StringKind:
+		return x.String() == y.String()
+	case pref.BytesKind:
+		return bytes.Equal(x.Bytes(), y.Bytes())
+	case pref.MessageKind, pref.GroupKind:
+		return equalMessage(x.Message(), y.Message())
+	default:
+		panic(fmt.Sprintf("invalid kind %v", fd.Kind()))
+	}
+}
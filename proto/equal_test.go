@@ -0,0 +1,308 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/v2/internal/encoding/pack"
+	"github.com/golang/protobuf/v2/internal/legacy"
+	"github.com/golang/protobuf/v2/internal/scalar"
+	"github.com/golang/protobuf/v2/proto"
+	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+
+	legacy1pb "github.com/golang/protobuf/v2/internal/testprotos/legacy/proto2.v1.0.0-20180125-92554152"
+	testpb "github.com/golang/protobuf/v2/internal/testprotos/test"
+	test3pb "github.com/golang/protobuf/v2/internal/testprotos/test3"
+)
+
+func TestEqual(t *testing.T) {
+	for _, test := range []struct {
+		x, y proto.Message
+		eq   bool
+	}{
+		{&testpb.TestAllTypes{}, &testpb.TestAllTypes{}, true},
+		{&testpb.TestAllTypes{}, &test3pb.TestAllTypes{}, false},
+
+		// Scalars.
+		{
+			&testpb.TestAllTypes{OptionalInt32: scalar.Int32(1)},
+			&testpb.TestAllTypes{OptionalInt32: scalar.Int32(1)},
+			true,
+		},
+		{
+			&testpb.TestAllTypes{OptionalInt32: scalar.Int32(1)},
+			&testpb.TestAllTypes{OptionalInt32: scalar.Int32(2)},
+			false,
+		},
+		{
+			&testpb.TestAllTypes{OptionalInt32: scalar.Int32(0)},
+			&testpb.TestAllTypes{},
+			false,
+		},
+		{
+			&test3pb.TestAllTypes{OptionalInt32: 0},
+			&test3pb.TestAllTypes{},
+			true,
+		},
+		{
+			&testpb.TestAllTypes{OptionalBytes: []byte{}},
+			&testpb.TestAllTypes{OptionalBytes: []byte{}},
+			true,
+		},
+		{
+			&testpb.TestAllTypes{OptionalBytes: []byte("a")},
+			&testpb.TestAllTypes{OptionalBytes: []byte("b")},
+			false,
+		},
+		{
+			&testpb.TestAllTypes{OptionalDouble: scalar.Float64(math.NaN())},
+			&testpb.TestAllTypes{OptionalDouble: scalar.Float64(math.NaN())},
+			true,
+		},
+		{
+			&testpb.TestAllTypes{OptionalFloat: scalar.Float32(float32(math.NaN()))},
+			&testpb.TestAllTypes{OptionalFloat: scalar.Float32(0)},
+			false,
+		},
+		{
+			&testpb.TestAllTypes{OptionalNestedEnum: testpb.TestAllTypes_FOO.Enum()},
+			&testpb.TestAllTypes{OptionalNestedEnum: testpb.TestAllTypes_BAR.Enum()},
+			false,
+		},
+
+		// Messages.
+		{
+			&testpb.TestAllTypes{OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{}},
+			&testpb.TestAllTypes{},
+			false,
+		},
+		{
+			&testpb.TestAllTypes{OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+				A: scalar.Int32(1),
+			}},
+			&testpb.TestAllTypes{OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+				A: scalar.Int32(1),
+			}},
+			true,
+		},
+		{
+			&testpb.TestAllTypes{OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+				Corecursive: &testpb.TestAllTypes{OptionalInt32: scalar.Int32(1)},
+			}},
+			&testpb.TestAllTypes{OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+				Corecursive: &testpb.TestAllTypes{OptionalInt32: scalar.Int32(2)},
+			}},
+			false,
+		},
+
+		// Lists.
+		{
+			&testpb.TestAllTypes{RepeatedInt32: []int32{1, 2}},
+			&testpb.TestAllTypes{RepeatedInt32: []int32{1, 2}},
+			true,
+		},
+		{
+			&testpb.TestAllTypes{RepeatedInt32: []int32{1, 2}},
+			&testpb.TestAllTypes{RepeatedInt32: []int32{2, 1}},
+			false,
+		},
+		{
+			&testpb.TestAllTypes{RepeatedInt32: []int32{1}},
+			&testpb.TestAllTypes{RepeatedInt32: []int32{1, 1}},
+			false,
+		},
+		{
+			&testpb.TestAllTypes{RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{
+				{A: scalar.Int32(1)},
+			}},
+			&testpb.TestAllTypes{RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{
+				{A: scalar.Int32(2)},
+			}},
+			false,
+		},
+
+		// Maps.
+		{
+			&testpb.TestAllTypes{MapInt32Int32: map[int32]int32{1: 2, 3: 4}},
+			&testpb.TestAllTypes{MapInt32Int32: map[int32]int32{3: 4, 1: 2}},
+			true,
+		},
+		{
+			&testpb.TestAllTypes{MapInt32Int32: map[int32]int32{1: 2}},
+			&testpb.TestAllTypes{MapInt32Int32: map[int32]int32{2: 2}},
+			false,
+		},
+		{
+			&testpb.TestAllTypes{MapInt32Int32: map[int32]int32{1: 2}},
+			&testpb.TestAllTypes{MapInt32Int32: map[int32]int32{1: 3}},
+			false,
+		},
+		{
+			&testpb.TestAllTypes{MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+				"a": {A: scalar.Int32(1)},
+			}},
+			&testpb.TestAllTypes{MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+				"a": {A: scalar.Int32(1)},
+			}},
+			true,
+		},
+		{
+			&testpb.TestAllTypes{MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+				"a": {A: scalar.Int32(1)},
+			}},
+			&testpb.TestAllTypes{MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+				"a": {A: scalar.Int32(2)},
+			}},
+			false,
+		},
+
+		// Oneofs.
+		{
+			&testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofUint32{1}},
+			&testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofUint32{1}},
+			true,
+		},
+		{
+			&testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofUint32{1}},
+			&testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofUint64{1}},
+			false,
+		},
+
+		// Extensions.
+		{
+			build(&testpb.TestAllExtensions{},
+				extend(testpb.E_OptionalInt32Extension, scalar.Int32(1)),
+			),
+			build(&testpb.TestAllExtensions{},
+				extend(testpb.E_OptionalInt32Extension, scalar.Int32(1)),
+			),
+			true,
+		},
+		{
+			build(&testpb.TestAllExtensions{},
+				extend(testpb.E_OptionalInt32Extension, scalar.Int32(1)),
+			),
+			build(&testpb.TestAllExtensions{},
+				extend(testpb.E_OptionalInt32Extension, scalar.Int32(2)),
+			),
+			false,
+		},
+		{
+			build(&testpb.TestAllExtensions{},
+				extend(testpb.E_OptionalInt32Extension, scalar.Int32(1)),
+			),
+			&testpb.TestAllExtensions{},
+			false,
+		},
+		{
+			build(&testpb.TestAllExtensions{},
+				extend(testpb.E_RepeatedInt32Extension, []int32{1, 2}),
+			),
+			build(&testpb.TestAllExtensions{},
+				extend(testpb.E_RepeatedInt32Extension, []int32{1, 2}),
+			),
+			true,
+		},
+
+		// Unknown fields.
+		{
+			build(&testpb.TestAllTypes{}, unknown(100000, pack.Message{
+				pack.Tag{100000, pack.VarintType}, pack.Varint(1),
+			}.Marshal())),
+			build(&testpb.TestAllTypes{}, unknown(100000, pack.Message{
+				pack.Tag{100000, pack.VarintType}, pack.Varint(1),
+			}.Marshal())),
+			true,
+		},
+		{
+			build(&testpb.TestAllTypes{}, unknown(100000, pack.Message{
+				pack.Tag{100000, pack.VarintType}, pack.Varint(1),
+			}.Marshal())),
+			build(&testpb.TestAllTypes{}, unknown(100000, pack.Message{
+				pack.Tag{100000, pack.VarintType}, pack.Varint(2),
+			}.Marshal())),
+			false,
+		},
+		{
+			build(&testpb.TestAllTypes{}, unknown(100000, pack.Message{
+				pack.Tag{100000, pack.VarintType}, pack.Varint(1),
+			}.Marshal())),
+			&testpb.TestAllTypes{},
+			false,
+		},
+		{
+			build(&testpb.TestAllTypes{},
+				unknown(100000, pack.Message{
+					pack.Tag{100000, pack.VarintType}, pack.Varint(1),
+				}.Marshal()),
+				unknown(100001, pack.Message{
+					pack.Tag{100001, pack.VarintType}, pack.Varint(2),
+				}.Marshal()),
+			),
+			build(&testpb.TestAllTypes{},
+				unknown(100001, pack.Message{
+					pack.Tag{100001, pack.VarintType}, pack.Varint(2),
+				}.Marshal()),
+				unknown(100000, pack.Message{
+					pack.Tag{100000, pack.VarintType}, pack.Varint(1),
+				}.Marshal()),
+			),
+			true,
+		},
+
+		// Legacy messages.
+		{
+			legacyMessage(&legacy1pb.Message{OptionalInt32: scalar.Int32(1)}),
+			legacyMessage(&legacy1pb.Message{OptionalInt32: scalar.Int32(1)}),
+			true,
+		},
+		{
+			legacyMessage(&legacy1pb.Message{OptionalInt32: scalar.Int32(1)}),
+			legacyMessage(&legacy1pb.Message{OptionalInt32: scalar.Int32(2)}),
+			false,
+		},
+	} {
+		if got := proto.Equal(test.x, test.y); got != test.eq {
+			t.Errorf("Equal(x, y) = %v, want %v\nx: %v\ny: %v", got, test.eq, marshalText(test.x), marshalText(test.y))
+		}
+		if got := proto.Equal(test.y, test.x); got != test.eq {
+			t.Errorf("Equal(y, x) = %v, want %v\nx: %v\ny: %v", got, test.eq, marshalText(test.x), marshalText(test.y))
+		}
+	}
+}
+
+func TestEqualDecoded(t *testing.T) {
+	for _, test := range testProtos {
+		for _, want := range test.decodeTo {
+			t.Run(fmt.Sprintf("%s (%T)", test.desc, want), func(t *testing.T) {
+				got := reflect.New(reflect.TypeOf(want).Elem()).Interface().(proto.Message)
+				xtypes := got.ProtoReflect().KnownFields().ExtensionTypes()
+				want.ProtoReflect().KnownFields().ExtensionTypes().Range(func(xt pref.ExtensionType) bool {
+					xtypes.Register(xt)
+					return true
+				})
+				opts := proto.UnmarshalOptions{AllowPartial: true}
+				if err := opts.Unmarshal(test.wire, got); err != nil {
+					t.Fatalf("Unmarshal error: %v", err)
+				}
+				if test.invalidExtensions {
+					// Extensions are left in the unknown fields.
+					return
+				}
+				if !proto.Equal(got, want) {
+					t.Errorf("Equal(Unmarshal(wire), want) = false\ngot:\n%v\nwant:\n%v", marshalText(got), marshalText(want))
+				}
+			})
+		}
+	}
+}
+
+func legacyMessage(m interface{}) proto.Message {
+	return legacy.Export{}.MessageOf(m).Interface()
+}
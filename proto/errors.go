@@ -0,0 +1,44 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import "github.com/golang/protobuf/v2/internal/errors"
+
+// The error types below are returned by this package and by the jsonpb and
+// textpb packages. Each records the path to the field involved, such as
+// "a.b[3].c", where extension fields are named by their full name
+// in parentheses and map entries are indexed by key.
+//
+// Non-fatal errors (RequiredNotSetError and InvalidUTF8Error) are usually
+// returned in a list together with other non-fatal errors. Use errors.As
+// in the standard library to test for a specific kind of error:
+//
+//	var e *proto.RequiredNotSetError
+//	if errors.As(err, &e) {
+//		log.Printf("missing field %v", e.Path)
+//	}
+type (
+	// RequiredNotSetError is a non-fatal error reporting that
+	// a required field is not set.
+	RequiredNotSetError = errors.RequiredNotSetError
+
+	// InvalidUTF8Error is a non-fatal error reporting that
+	// a string field contains invalid UTF-8. Marshal and Unmarshal
+	// report it for string fields declared in proto3 files,
+	// after encoding or storing the string as is.
+	InvalidUTF8Error = errors.InvalidUTF8Error
+
+	// ParseError reports that the input is malformed.
+	ParseError = errors.ParseError
+
+	// UnknownEnumError reports that the input contains
+	// an unknown enum value name.
+	UnknownEnumError = errors.UnknownEnumError
+
+	// RecursionLimitError reports that the input contains messages or groups
+	// nested more deeply than the recursion limit.
+	// It matches ErrRecursionLimit when tested with errors.Is.
+	RecursionLimitError = errors.RecursionLimitError
+)
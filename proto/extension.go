@@ -0,0 +1,154 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/v2/internal/errors"
+	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+	"github.com/golang/protobuf/v2/reflect/protoregistry"
+)
+
+// HasExtension reports whether the extension field xt is populated in m.
+//
+// An extension field whose type was not registered when m was unmarshaled
+// is populated if it is held in the unknown fields of m.
+func HasExtension(m Message, xt pref.ExtensionType) bool {
+	mr := m.ProtoReflect()
+	if !isRegistered(mr, xt) {
+		return len(mr.UnknownFields().Get(xt.Number())) > 0
+	}
+	return mr.KnownFields().Has(xt.Number())
+}
+
+// ClearExtension clears the extension field xt in m.
+func ClearExtension(m Message, xt pref.ExtensionType) {
+	mr := m.ProtoReflect()
+	if isRegistered(mr, xt) {
+		mr.KnownFields().Clear(xt.Number())
+	}
+	mr.UnknownFields().Set(xt.Number(), nil)
+}
+
+// GetExtension returns the value of the extension field xt in m,
+// of the Go type of xt as returned by xt.InterfaceOf.
+//
+// If the field is not populated, the default value of xt is returned,
+// which is a new empty value for repeated and message fields.
+//
+// GetExtension does not modify m. A value held in the unknown fields of m
+// is decoded into a new value, which does not alias m.
+func GetExtension(m Message, xt pref.ExtensionType) interface{} {
+	mr := m.ProtoReflect()
+	if isRegistered(mr, xt) {
+		knownFields := mr.KnownFields()
+		if !knownFields.Has(xt.Number()) {
+			return xt.InterfaceOf(xt.New())
+		}
+		return xt.InterfaceOf(knownFields.Get(xt.Number()))
+	}
+	v := xt.New()
+	if raw := mr.UnknownFields().Get(xt.Number()); len(raw) > 0 {
+		if v2 := decodeExtension(mr.Type(), xt, raw); v2.IsValid() {
+			v = v2
+		}
+	}
+	return xt.InterfaceOf(v)
+}
+
+// SetExtension sets the value of the extension field xt in m to v,
+// which must be of the Go type of xt as accepted by xt.ValueOf.
+// The extension type is registered with m if not already.
+func SetExtension(m Message, xt pref.ExtensionType, v interface{}) {
+	knownFields := registerExtension(m.ProtoReflect(), xt)
+	knownFields.Set(xt.Number(), xt.ValueOf(v))
+}
+
+// RangeExtensions calls f for each populated extension field in m
+// in field number order, with the value of the field of the Go type of xt.
+// Ranging stops when f returns false.
+//
+// Only extension fields whose types are registered with m are visited.
+func RangeExtensions(m Message, f func(xt pref.ExtensionType, v interface{}) bool) {
+	knownFields := m.ProtoReflect().KnownFields()
+	var xts []pref.ExtensionType
+	knownFields.ExtensionTypes().Range(func(xt pref.ExtensionType) bool {
+		if knownFields.Has(xt.Number()) {
+			xts = append(xts, xt)
+		}
+		return true
+	})
+	sort.Slice(xts, func(i, j int) bool { return xts[i].Number() < xts[j].Number() })
+	for _, xt := range xts {
+		if !f(xt, xt.InterfaceOf(knownFields.Get(xt.Number()))) {
+			return
+		}
+	}
+}
+
+// isRegistered reports whether the type of the extension field xt
+// is registered with m. It panics if xt does not extend m, or if another
+// extension field with the same number is registered.
+func isRegistered(m pref.Message, xt pref.ExtensionType) bool {
+	if got, want := xt.ExtendedType().FullName(), m.Type().FullName(); got != want {
+		panic(fmt.Sprintf("extension %v extends %v, not %v", xt.FullName(), got, want))
+	}
+	xt2 := m.KnownFields().ExtensionTypes().ByNumber(xt.Number())
+	if xt2 == nil {
+		return false
+	}
+	if xt2.FullName() != xt.FullName() {
+		panic(fmt.Sprintf("extension %v conflicts with registered extension %v", xt.FullName(), xt2.FullName()))
+	}
+	return true
+}
+
+// registerExtension registers the type of the extension field xt with m,
+// if not already, and returns the known fields of m. A value of the field
+// held in the unknown fields of m is decoded into the known fields.
+func registerExtension(m pref.Message, xt pref.ExtensionType) pref.KnownFields {
+	knownFields := m.KnownFields()
+	if isRegistered(m, xt) {
+		return knownFields
+	}
+	knownFields.ExtensionTypes().Register(xt)
+	unknownFields := m.UnknownFields()
+	raw := unknownFields.Get(xt.Number())
+	if len(raw) == 0 {
+		return knownFields
+	}
+	v := decodeExtension(m.Type(), xt, raw)
+	if !v.IsValid() {
+		// Keep the malformed field in the unknown fields.
+		return knownFields
+	}
+	unknownFields.Set(xt.Number(), nil)
+	knownFields.Set(xt.Number(), v)
+	return knownFields
+}
+
+// decodeExtension decodes the raw fields of the extension field xt,
+// which extends mt, into a new value. It returns an invalid value
+// if raw is malformed.
+func decodeExtension(mt pref.MessageType, xt pref.ExtensionType, raw pref.RawFields) pref.Value {
+	// Decode into an empty message of the extended type, so that m itself
+	// is not modified if the field is malformed.
+	m := mt.New()
+	knownFields := m.KnownFields()
+	knownFields.ExtensionTypes().Register(xt)
+	o := UnmarshalOptions{
+		AllowPartial:   true,
+		Merge:          true,
+		RecursionLimit: DefaultRecursionLimit,
+		Resolver:       protoregistry.GlobalTypes,
+	}
+	var nerr errors.NonFatal
+	if err := o.unmarshalMessage(raw, m); !nerr.Merge(err) {
+		return pref.Value{}
+	}
+	return knownFields.Get(xt.Number())
+}
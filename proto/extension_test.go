@@ -0,0 +1,123 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/v2/internal/encoding/pack"
+	"github.com/golang/protobuf/v2/internal/scalar"
+	"github.com/golang/protobuf/v2/proto"
+	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+
+	testpb "github.com/golang/protobuf/v2/internal/testprotos/test"
+)
+
+func TestExtensionFuncs(t *testing.T) {
+	for _, test := range []struct {
+		xt    pref.ExtensionType
+		value interface{}
+	}{
+		{testpb.E_OptionalInt32Extension.Type, int32(1)},
+		{testpb.E_OptionalNestedEnumExtension.Type, testpb.TestAllTypes_BAZ},
+		{testpb.E_OptionalNestedMessageExtension.Type, &testpb.TestAllTypes_NestedMessage{A: scalar.Int32(2)}},
+		{testpb.E_RepeatedStringExtension.Type, &[]string{"a", "b"}},
+	} {
+		xt := test.xt
+		t.Run(string(xt.Name()), func(t *testing.T) {
+			m := &testpb.TestAllExtensions{}
+			if proto.HasExtension(m, xt) {
+				t.Errorf("HasExtension(empty) = true, want false")
+			}
+			if got, want := proto.GetExtension(m, xt), xt.InterfaceOf(xt.New()); !reflect.DeepEqual(got, want) {
+				t.Errorf("GetExtension(empty) = %v, want %v", got, want)
+			}
+
+			proto.SetExtension(m, xt, test.value)
+			if !proto.HasExtension(m, xt) {
+				t.Errorf("HasExtension() = false after SetExtension, want true")
+			}
+			if got := proto.GetExtension(m, xt); !reflect.DeepEqual(got, test.value) {
+				t.Errorf("GetExtension() = %v, want %v", got, test.value)
+			}
+			var ranged []pref.ExtensionType
+			proto.RangeExtensions(m, func(xt pref.ExtensionType, v interface{}) bool {
+				ranged = append(ranged, xt)
+				if !reflect.DeepEqual(v, test.value) {
+					t.Errorf("RangeExtensions() visited %v with %v, want %v", xt.FullName(), v, test.value)
+				}
+				return true
+			})
+			if len(ranged) != 1 || ranged[0].FullName() != xt.FullName() {
+				t.Errorf("RangeExtensions() visited %v, want only %v", ranged, xt.FullName())
+			}
+
+			proto.ClearExtension(m, xt)
+			if proto.HasExtension(m, xt) {
+				t.Errorf("HasExtension() = true after ClearExtension, want false")
+			}
+			// The extension type remains registered with m.
+			if got, want := proto.GetExtension(m, xt), xt.InterfaceOf(xt.New()); !reflect.DeepEqual(got, want) {
+				t.Errorf("GetExtension() after ClearExtension = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestExtensionFuncsUnregistered(t *testing.T) {
+	// The extension is held in the unknown fields,
+	// as if it were unmarshaled without the extension type registered.
+	xt := testpb.E_OptionalInt32Extension.Type
+	raw := pack.Message{pack.Tag{1, pack.VarintType}, pack.Varint(5)}.Marshal()
+	m := build(&testpb.TestAllExtensions{}, unknown(1, raw)).(*testpb.TestAllExtensions)
+	if !proto.HasExtension(m, xt) {
+		t.Errorf("HasExtension() = false, want true")
+	}
+	if m.ProtoReflect().KnownFields().ExtensionTypes().Len() != 0 {
+		t.Errorf("HasExtension() registered the extension type")
+	}
+	if got, want := proto.GetExtension(m, xt), int32(5); got != want {
+		t.Errorf("GetExtension() = %v, want %v", got, want)
+	}
+	if got := m.ProtoReflect().UnknownFields().Get(1); !bytes.Equal(got, raw) {
+		t.Errorf("unknown fields after GetExtension = %x, want %x", got, raw)
+	}
+	if m.ProtoReflect().KnownFields().ExtensionTypes().Len() != 0 {
+		t.Errorf("GetExtension() registered the extension type")
+	}
+
+	// SetExtension registers the extension type,
+	// which moves the field out of the unknown fields.
+	proto.SetExtension(m, xt, proto.GetExtension(m, xt))
+	if got := m.ProtoReflect().UnknownFields().Get(1); len(got) != 0 {
+		t.Errorf("unknown fields after SetExtension = %x, want none", got)
+	}
+	if got, want := proto.GetExtension(m, xt), int32(5); got != want {
+		t.Errorf("GetExtension() after SetExtension = %v, want %v", got, want)
+	}
+
+	m = build(&testpb.TestAllExtensions{}, unknown(1, raw)).(*testpb.TestAllExtensions)
+	proto.ClearExtension(m, xt)
+	if proto.HasExtension(m, xt) {
+		t.Errorf("HasExtension() = true after ClearExtension, want false")
+	}
+}
+
+func TestRangeExtensionsOrder(t *testing.T) {
+	m := &testpb.TestAllExtensions{}
+	proto.SetExtension(m, testpb.E_RepeatedStringExtension.Type, &[]string{"a"})
+	proto.SetExtension(m, testpb.E_OptionalInt32Extension.Type, int32(1))
+	proto.SetExtension(m, testpb.E_OptionalStringExtension.Type, "s")
+	var got []pref.FieldNumber
+	proto.RangeExtensions(m, func(xt pref.ExtensionType, _ interface{}) bool {
+		got = append(got, xt.Number())
+		return len(got) < 2
+	})
+	if want := []pref.FieldNumber{1, 14}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RangeExtensions() visited %v, want %v", got, want)
+	}
+}
@@ -5,90 +5,60 @@
 package proto
 
 import (
-	"bytes"
-	"fmt"
-
 	"github.com/golang/protobuf/v2/internal/errors"
 	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+	"github.com/golang/protobuf/v2/reflect/protorange"
 )
 
 // IsInitialized returns an error if any required fields in m are not set.
 func IsInitialized(m Message) error {
 	if methods := protoMethods(m); methods != nil && methods.IsInitialized != nil {
 		// TODO: Do we need a way to disable the fast path here?
-		//
-		// TODO: Should detailed information about missing
-		// fields always be provided by the slow-but-informative
-		// reflective implementation?
-		return methods.IsInitialized(m)
+		if err := methods.IsInitialized(m); err == nil {
+			return nil
+		}
+		// Fall back to the slow-but-informative reflective implementation
+		// to report the full path of the missing field.
 	}
-	return isInitialized(m.ProtoReflect(), nil)
+	return isInitialized(m.ProtoReflect())
 }
 
-// IsInitialized returns an error if any required fields in m are not set.
-func isInitialized(m pref.Message, stack []interface{}) error {
-	md := m.Type()
-	known := m.KnownFields()
-	fields := md.Fields()
-	for i, nums := 0, md.RequiredNumbers(); i < nums.Len(); i++ {
-		num := nums.Get(i)
-		if !known.Has(num) {
-			stack = append(stack, fields.ByNumber(num).Name())
-			return newRequiredNotSetError(stack)
-		}
-	}
-	var err error
-	known.Range(func(num pref.FieldNumber, v pref.Value) bool {
-		field := fields.ByNumber(num)
-		if field == nil {
-			field = known.ExtensionTypes().ByNumber(num)
-		}
-		if field == nil {
-			panic(fmt.Errorf("no descriptor for field %d in %q", num, md.FullName()))
-		}
-		// Look for fields containing a message: Messages, groups, and maps
-		// with a message or group value.
-		ft := field.MessageType()
-		if ft == nil {
-			return true
-		}
-		if field.IsMap() {
-			if ft.Fields().ByNumber(2).MessageType() == nil {
-				return true
+// isInitialized returns an error if any required fields in m are not set.
+// The path of the error is relative to m.
+func isInitialized(m pref.Message) error {
+	var nerr errors.NonFatal
+	protorange.Options{}.Range(m, func(p protorange.Path, v pref.Value) error {
+		// Skip values which cannot contain a message: scalars, and lists
+		// and maps of scalars. Messages in lists and maps are visited.
+		if len(p) > 0 {
+			if s := p[len(p)-1]; s.Kind == protorange.FieldStep {
+				fd := s.Field
+				if fd.IsMap() {
+					fd = fd.MessageType().Fields().ByNumber(2)
+				}
+				if fd.MessageType() == nil {
+					return protorange.Break
+				}
 			}
 		}
-		// Recurse into the field
-		stack := append(stack, field.Name())
-		switch {
-		case field.IsMap():
-			v.Map().Range(func(key pref.MapKey, v pref.Value) bool {
-				stack := append(stack, "[", key, "].")
-				err = isInitialized(v.Message(), stack)
-				return err == nil
-			})
-		case field.Cardinality() == pref.Repeated:
-			for i, list := 0, v.List(); i < list.Len(); i++ {
-				stack := append(stack, "[", i, "].")
-				err = isInitialized(list.Get(i).Message(), stack)
-				if err != nil {
-					break
+		m, ok := v.Interface().(pref.Message)
+		if !ok {
+			return nil
+		}
+		md := m.Type()
+		known := m.KnownFields()
+		for i, nums := 0, md.RequiredNumbers(); i < nums.Len(); i++ {
+			num := nums.Get(i)
+			if !known.Has(num) {
+				path := string(md.Fields().ByNumber(num).Name())
+				if len(p) > 0 {
+					path = p.String() + "." + path
 				}
+				nerr.AppendRequiredNotSet(path)
+				return protorange.Terminate
 			}
-		default:
-			stack := append(stack, ".")
-			err = isInitialized(v.Message(), stack)
 		}
-		return err == nil
-	})
-	return err
-}
-
-func newRequiredNotSetError(stack []interface{}) error {
-	var buf bytes.Buffer
-	for _, s := range stack {
-		fmt.Fprint(&buf, s)
-	}
-	var nerr errors.NonFatal
-	nerr.AppendRequiredNotSet(buf.String())
+		return nil
+	}, nil)
 	return nerr.E
 }
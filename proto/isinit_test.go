@@ -5,6 +5,7 @@
 package proto_test
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -16,18 +17,21 @@ import (
 
 func TestIsInitializedErrors(t *testing.T) {
 	for _, test := range []struct {
-		m    proto.Message
-		want string
+		m        proto.Message
+		want     string
+		wantPath string
 	}{
 		{
 			&testpb.TestRequired{},
 			`proto: required field required_field not set`,
+			"required_field",
 		},
 		{
 			&testpb.TestRequiredForeign{
 				OptionalMessage: &testpb.TestRequired{},
 			},
 			`proto: required field optional_message.required_field not set`,
+			"optional_message.required_field",
 		},
 		{
 			&testpb.TestRequiredForeign{
@@ -37,6 +41,7 @@ func TestIsInitializedErrors(t *testing.T) {
 				},
 			},
 			`proto: required field repeated_message[1].required_field not set`,
+			"repeated_message[1].required_field",
 		},
 		{
 			&testpb.TestRequiredForeign{
@@ -45,6 +50,7 @@ func TestIsInitializedErrors(t *testing.T) {
 				},
 			},
 			`proto: required field map_message[1].required_field not set`,
+			"map_message[1].required_field",
 		},
 	} {
 		err := proto.IsInitialized(test.m)
@@ -56,5 +62,11 @@ func TestIsInitializedErrors(t *testing.T) {
 		if got != want {
 			t.Errorf("IsInitialized(m):\n got: %v\nwant: %v\nMessage:\n%v", got, want, marshalText(test.m))
 		}
+		var e *proto.RequiredNotSetError
+		if !errors.As(err, &e) {
+			t.Errorf("IsInitialized(m) = %v, want RequiredNotSetError", err)
+		} else if e.Path != test.wantPath {
+			t.Errorf("IsInitialized(m): RequiredNotSetError.Path = %q, want %q", e.Path, test.wantPath)
+		}
 	}
 }
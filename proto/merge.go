@@ -0,0 +1,121 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"fmt"
+
+	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+)
+
+// Clone returns a deep copy of m.
+// If the top-level message is nil, it returns nil.
+func Clone(m Message) Message {
+	if m == nil {
+		return nil
+	}
+	src := m.ProtoReflect()
+	dst := src.Type().New()
+	mergeMessage(dst, src)
+	return dst.Interface()
+}
+
+// Merge merges src into dst, which must be messages of the same type.
+//
+// Populated scalar fields in src are copied to dst, while populated
+// singular messages in src are merged into dst by recursively calling Merge.
+// The elements of every list field in src are appended to the corresponding
+// list fields in dst. The entries of every map field in src are copied into
+// the corresponding map field in dst, possibly replacing existing entries.
+// Setting a member of a oneof in dst clears any other member of that oneof
+// that was previously set; if the same message member is set in both,
+// the two messages are merged. Populated extension fields in src are merged
+// as above, registering the extension type with dst as needed.
+// The unknown fields of src are appended to the unknown fields of dst.
+//
+// The resulting value in dst never aliases any memory of src.
+func Merge(dst, src Message) {
+	mergeMessage(dst.ProtoReflect(), src.ProtoReflect())
+}
+
+func mergeMessage(dst, src pref.Message) {
+	if dst.Type().FullName() != src.Type().FullName() {
+		panic(fmt.Sprintf("mismatching message types: %v != %v", dst.Type().FullName(), src.Type().FullName()))
+	}
+	fields := src.Type().Fields()
+	dstKnown, srcKnown := dst.KnownFields(), src.KnownFields()
+	srcKnown.Range(func(num pref.FieldNumber, v pref.Value) bool {
+		field := fields.ByNumber(num)
+		if field == nil {
+			xt := srcKnown.ExtensionTypes().ByNumber(num)
+			if xt == nil {
+				panic(fmt.Errorf("no descriptor for field %d in %q", num, src.Type().FullName()))
+			}
+			if dstKnown.ExtensionTypes().ByNumber(num) == nil {
+				dstKnown.ExtensionTypes().Register(xt)
+			}
+			field = xt
+		}
+		switch {
+		case field.IsMap():
+			valField := field.MessageType().Fields().ByNumber(2)
+			mergeMap(dstKnown.Get(num).Map(), v.Map(), valField)
+		case field.Cardinality() == pref.Repeated:
+			mergeList(dstKnown.Get(num).List(), v.List(), field)
+		case field.Kind() == pref.MessageKind, field.Kind() == pref.GroupKind:
+			if dstKnown.Has(num) {
+				mergeMessage(dstKnown.Get(num).Message(), v.Message())
+				break
+			}
+			m := dstKnown.NewMessage(num)
+			mergeMessage(m, v.Message())
+			dstKnown.Set(num, pref.ValueOf(m))
+		default:
+			dstKnown.Set(num, cloneScalar(field, v))
+		}
+		return true
+	})
+
+	dstUnknown := dst.UnknownFields()
+	src.UnknownFields().Range(func(num pref.FieldNumber, raw pref.RawFields) bool {
+		dstUnknown.Set(num, append(append(pref.RawFields(nil), dstUnknown.Get(num)...), raw...))
+		return true
+	})
+}
+
+func mergeList(dst, src pref.List, field pref.FieldDescriptor) {
+	for i, n := 0, src.Len(); i < n; i++ {
+		switch v := src.Get(i); field.Kind() {
+		case pref.MessageKind, pref.GroupKind:
+			m := dst.NewMessage()
+			mergeMessage(m, v.Message())
+			dst.Append(pref.ValueOf(m))
+		default:
+			dst.Append(cloneScalar(field, v))
+		}
+	}
+}
+
+func mergeMap(dst, src pref.Map, valField pref.FieldDescriptor) {
+	src.Range(func(k pref.MapKey, v pref.Value) bool {
+		switch valField.Kind() {
+		case pref.MessageKind, pref.GroupKind:
+			m := dst.NewMessage()
+			mergeMessage(m, v.Message())
+			dst.Set(k, pref.ValueOf(m))
+		default:
+			dst.Set(k, cloneScalar(valField, v))
+		}
+		return true
+	})
+}
+
+// cloneScalar returns a copy of v that does not alias any memory of v.
+func cloneScalar(field pref.FieldDescriptor, v pref.Value) pref.Value {
+	if field.Kind() == pref.BytesKind {
+		return pref.ValueOf(append([]byte{}, v.Bytes()...))
+	}
+	return v
+}
@@ -0,0 +1,231 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/v2/internal/encoding/pack"
+	"github.com/golang/protobuf/v2/internal/scalar"
+	"github.com/golang/protobuf/v2/proto"
+
+	testpb "github.com/golang/protobuf/v2/internal/testprotos/test"
+	test3pb "github.com/golang/protobuf/v2/internal/testprotos/test3"
+)
+
+func TestMerge(t *testing.T) {
+	for _, test := range []struct {
+		desc     string
+		dst, src proto.Message
+		want     proto.Message
+	}{
+		{
+			desc: "scalars overwrite",
+			dst: &testpb.TestAllTypes{
+				OptionalInt32:  scalar.Int32(1),
+				OptionalString: scalar.String("a"),
+			},
+			src: &testpb.TestAllTypes{
+				OptionalInt32: scalar.Int32(2),
+				OptionalBytes: []byte("b"),
+			},
+			want: &testpb.TestAllTypes{
+				OptionalInt32:  scalar.Int32(2),
+				OptionalString: scalar.String("a"),
+				OptionalBytes:  []byte("b"),
+			},
+		},
+		{
+			desc: "proto3 zero values are not copied",
+			dst:  &test3pb.TestAllTypes{OptionalInt32: 1},
+			src:  &test3pb.TestAllTypes{OptionalInt64: 2},
+			want: &test3pb.TestAllTypes{OptionalInt32: 1, OptionalInt64: 2},
+		},
+		{
+			desc: "messages merge",
+			dst: &testpb.TestAllTypes{OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+				A: scalar.Int32(1),
+			}},
+			src: &testpb.TestAllTypes{OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+				Corecursive: &testpb.TestAllTypes{OptionalInt32: scalar.Int32(2)},
+			}},
+			want: &testpb.TestAllTypes{OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+				A:           scalar.Int32(1),
+				Corecursive: &testpb.TestAllTypes{OptionalInt32: scalar.Int32(2)},
+			}},
+		},
+		{
+			desc: "groups merge",
+			dst:  &testpb.TestAllTypes{Optionalgroup: &testpb.TestAllTypes_OptionalGroup{}},
+			src: &testpb.TestAllTypes{Optionalgroup: &testpb.TestAllTypes_OptionalGroup{
+				A: scalar.Int32(1),
+			}},
+			want: &testpb.TestAllTypes{Optionalgroup: &testpb.TestAllTypes_OptionalGroup{
+				A: scalar.Int32(1),
+			}},
+		},
+		{
+			desc: "lists append",
+			dst: &testpb.TestAllTypes{
+				RepeatedInt32: []int32{1, 2},
+				RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{
+					{A: scalar.Int32(1)},
+				},
+			},
+			src: &testpb.TestAllTypes{
+				RepeatedInt32: []int32{3},
+				RepeatedBytes: [][]byte{[]byte("a")},
+				RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{
+					{A: scalar.Int32(2)},
+				},
+			},
+			want: &testpb.TestAllTypes{
+				RepeatedInt32: []int32{1, 2, 3},
+				RepeatedBytes: [][]byte{[]byte("a")},
+				RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{
+					{A: scalar.Int32(1)},
+					{A: scalar.Int32(2)},
+				},
+			},
+		},
+		{
+			desc: "maps replace per key",
+			dst: &testpb.TestAllTypes{
+				MapInt32Int32: map[int32]int32{1: 1, 2: 2},
+				MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+					"a": {A: scalar.Int32(1)},
+					"b": {A: scalar.Int32(2)},
+				},
+			},
+			src: &testpb.TestAllTypes{
+				MapInt32Int32: map[int32]int32{2: 3, 4: 4},
+				MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+					"b": {Corecursive: &testpb.TestAllTypes{}},
+				},
+			},
+			want: &testpb.TestAllTypes{
+				MapInt32Int32: map[int32]int32{1: 1, 2: 3, 4: 4},
+				MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+					"a": {A: scalar.Int32(1)},
+					"b": {Corecursive: &testpb.TestAllTypes{}},
+				},
+			},
+		},
+		{
+			desc: "oneof switches member",
+			dst:  &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofUint32{1}},
+			src:  &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofString{"a"}},
+			want: &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofString{"a"}},
+		},
+		{
+			desc: "oneof switches to message",
+			dst:  &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofUint32{1}},
+			src: &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofNestedMessage{
+				&testpb.TestAllTypes_NestedMessage{A: scalar.Int32(1)},
+			}},
+			want: &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofNestedMessage{
+				&testpb.TestAllTypes_NestedMessage{A: scalar.Int32(1)},
+			}},
+		},
+		{
+			desc: "oneof merges same message member",
+			dst: &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofNestedMessage{
+				&testpb.TestAllTypes_NestedMessage{A: scalar.Int32(1)},
+			}},
+			src: &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofNestedMessage{
+				&testpb.TestAllTypes_NestedMessage{Corecursive: &testpb.TestAllTypes{}},
+			}},
+			want: &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofNestedMessage{
+				&testpb.TestAllTypes_NestedMessage{
+					A:           scalar.Int32(1),
+					Corecursive: &testpb.TestAllTypes{},
+				},
+			}},
+		},
+		{
+			desc: "extensions",
+			dst: build(&testpb.TestAllExtensions{},
+				extend(testpb.E_OptionalInt32Extension, scalar.Int32(1)),
+				extend(testpb.E_RepeatedInt32Extension, []int32{1}),
+			),
+			src: build(&testpb.TestAllExtensions{},
+				extend(testpb.E_OptionalInt64Extension, scalar.Int64(2)),
+				extend(testpb.E_RepeatedInt32Extension, []int32{2}),
+				extend(testpb.E_OptionalNestedMessageExtension, &testpb.TestAllTypes_NestedMessage{
+					A: scalar.Int32(3),
+				}),
+			),
+			want: build(&testpb.TestAllExtensions{},
+				extend(testpb.E_OptionalInt32Extension, scalar.Int32(1)),
+				extend(testpb.E_OptionalInt64Extension, scalar.Int64(2)),
+				extend(testpb.E_RepeatedInt32Extension, []int32{1, 2}),
+				extend(testpb.E_OptionalNestedMessageExtension, &testpb.TestAllTypes_NestedMessage{
+					A: scalar.Int32(3),
+				}),
+			),
+		},
+		{
+			desc: "unknown fields append",
+			dst: build(&testpb.TestAllTypes{}, unknown(100000, pack.Message{
+				pack.Tag{100000, pack.VarintType}, pack.Varint(1),
+			}.Marshal())),
+			src: build(&testpb.TestAllTypes{},
+				unknown(100000, pack.Message{
+					pack.Tag{100000, pack.VarintType}, pack.Varint(2),
+				}.Marshal()),
+				unknown(100001, pack.Message{
+					pack.Tag{100001, pack.VarintType}, pack.Varint(3),
+				}.Marshal()),
+			),
+			want: build(&testpb.TestAllTypes{},
+				unknown(100000, pack.Message{
+					pack.Tag{100000, pack.VarintType}, pack.Varint(1),
+					pack.Tag{100000, pack.VarintType}, pack.Varint(2),
+				}.Marshal()),
+				unknown(100001, pack.Message{
+					pack.Tag{100001, pack.VarintType}, pack.Varint(3),
+				}.Marshal()),
+			),
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			src := proto.Clone(test.src)
+			proto.Merge(test.dst, test.src)
+			if !proto.Equal(test.dst, test.want) {
+				t.Fatalf("Merge() mismatch:\ngot:\n%v\nwant:\n%v", marshalText(test.dst), marshalText(test.want))
+			}
+			if !proto.Equal(test.src, src) {
+				t.Fatalf("Merge() modified src:\ngot:\n%v\nwant:\n%v", marshalText(test.src), marshalText(src))
+			}
+		})
+	}
+}
+
+func TestClone(t *testing.T) {
+	for _, test := range testProtos {
+		for _, want := range test.decodeTo {
+			t.Run(fmt.Sprintf("%s (%T)", test.desc, want), func(t *testing.T) {
+				got := proto.Clone(want)
+				if !proto.Equal(got, want) {
+					t.Fatalf("Clone() mismatch:\ngot:\n%v\nwant:\n%v", marshalText(got), marshalText(want))
+				}
+
+				// Aliasing check: Merging into the clone must not affect the original.
+				before := proto.Clone(want)
+				proto.Merge(got, want)
+				if !proto.Equal(want, before) {
+					t.Errorf("Merge into clone modified the original:\ngot:\n%v\nwant:\n%v", marshalText(want), marshalText(before))
+				}
+			})
+		}
+	}
+}
+
+func TestCloneNil(t *testing.T) {
+	if got := proto.Clone(nil); got != nil {
+		t.Errorf("Clone(nil) = %v, want nil", got)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/v2/internal/encoding/pack"
+	"github.com/golang/protobuf/v2/internal/scalar"
+	"github.com/golang/protobuf/v2/proto"
+
+	testpb "github.com/golang/protobuf/v2/internal/testprotos/test"
+)
+
+func TestReset(t *testing.T) {
+	m := build(&testpb.TestAllExtensions{},
+		extend(testpb.E_OptionalInt32Extension, scalar.Int32(1)),
+		extend(testpb.E_RepeatedInt32Extension, []int32{1, 2}),
+		unknown(100000, pack.Message{
+			pack.Tag{100000, pack.VarintType}, pack.Varint(1),
+		}.Marshal()),
+	)
+	proto.Reset(m)
+	if !proto.Equal(m, &testpb.TestAllExtensions{}) {
+		t.Errorf("Reset() did not clear message:\n%v", marshalText(m))
+	}
+	if xt := m.ProtoReflect().KnownFields().ExtensionTypes().ByNumber(1); xt == nil {
+		t.Errorf("Reset() removed registered extension type")
+	}
+}
+
+func TestUnmarshalMerge(t *testing.T) {
+	wire := pack.Message{
+		pack.Tag{2, pack.VarintType}, pack.Varint(2),
+		pack.Tag{31, pack.VarintType}, pack.Varint(2),
+		pack.Tag{18, pack.BytesType}, pack.LengthPrefix(pack.Message{
+			pack.Tag{1, pack.VarintType}, pack.Varint(2),
+		}),
+	}.Marshal()
+	newMessage := func() *testpb.TestAllTypes {
+		return &testpb.TestAllTypes{
+			OptionalInt32: scalar.Int32(1),
+			RepeatedInt32: []int32{1},
+			OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+				Corecursive: &testpb.TestAllTypes{},
+			},
+		}
+	}
+
+	for _, test := range []struct {
+		desc string
+		opts proto.UnmarshalOptions
+		want proto.Message
+	}{{
+		desc: "reset",
+		opts: proto.UnmarshalOptions{},
+		want: &testpb.TestAllTypes{
+			OptionalInt64: scalar.Int64(2),
+			RepeatedInt32: []int32{2},
+			OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+				A: scalar.Int32(2),
+			},
+		},
+	}, {
+		desc: "merge",
+		opts: proto.UnmarshalOptions{Merge: true},
+		want: &testpb.TestAllTypes{
+			OptionalInt32: scalar.Int32(1),
+			OptionalInt64: scalar.Int64(2),
+			RepeatedInt32: []int32{1, 2},
+			OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+				A:           scalar.Int32(2),
+				Corecursive: &testpb.TestAllTypes{},
+			},
+		},
+	}} {
+		t.Run(test.desc, func(t *testing.T) {
+			got := newMessage()
+			if err := test.opts.Unmarshal(wire, got); err != nil {
+				t.Fatalf("Unmarshal error: %v", err)
+			}
+			if !proto.Equal(got, test.want) {
+				t.Errorf("Unmarshal returned unexpected result; got:\n%v\nwant:\n%v", marshalText(got), marshalText(test.want))
+			}
+		})
+	}
+}
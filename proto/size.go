@@ -7,8 +7,9 @@ package proto
 import (
 	"fmt"
 
-	"github.com/golang/protobuf/v2/internal/encoding/wire"
+	"github.com/golang/protobuf/v2/encoding/protowire"
 	"github.com/golang/protobuf/v2/reflect/protoreflect"
+	"github.com/golang/protobuf/v2/runtime/protoiface"
 )
 
 // Size returns the size in bytes of the wire-format encoding of m.
@@ -18,22 +19,23 @@ func Size(m Message) int {
 
 // Size returns the size in bytes of the wire-format encoding of m.
 func (o MarshalOptions) Size(m Message) int {
-	if size, err := sizeMessageFast(m); err == nil {
+	if size, err := o.sizeMessageFast(m); err == nil {
 		return size
 	}
-	return sizeMessage(m.ProtoReflect())
+	return o.sizeMessage(m.ProtoReflect())
 }
 
-func sizeMessageFast(m Message) (int, error) {
-	// TODO: Pass MarshalOptions to size to permit disabling fast path?
+func (o MarshalOptions) sizeMessageFast(m Message) (int, error) {
 	methods := protoMethods(m)
-	if methods == nil || methods.Size == nil {
+	// Canonical serialization always uses the reflective implementation,
+	// which packs every packable repeated field.
+	if methods == nil || methods.Size == nil || o.Canonical {
 		return 0, errInternalNoFast
 	}
-	return methods.Size(m), nil
+	return methods.Size(m, protoiface.MarshalOptions(o)), nil
 }
 
-func sizeMessage(m protoreflect.Message) (size int) {
+func (o MarshalOptions) sizeMessage(m protoreflect.Message) (size int) {
 	fields := m.Type().Fields()
 	knownFields := m.KnownFields()
 	m.KnownFields().Range(func(num protoreflect.FieldNumber, value protoreflect.Value) bool {
@@ -44,7 +46,7 @@ func sizeMessage(m protoreflect.Message) (size int) {
 				panic(fmt.Errorf("no descriptor for field %d in %q", num, m.Type().FullName()))
 			}
 		}
-		size += sizeField(field, value)
+		size += o.sizeField(field, value)
 		return true
 	})
 	m.UnknownFields().Range(func(_ protoreflect.FieldNumber, raw protoreflect.RawFields) bool {
@@ -54,43 +56,43 @@ func sizeMessage(m protoreflect.Message) (size int) {
 	return size
 }
 
-func sizeField(field protoreflect.FieldDescriptor, value protoreflect.Value) (size int) {
+func (o MarshalOptions) sizeField(field protoreflect.FieldDescriptor, value protoreflect.Value) (size int) {
 	num := field.Number()
 	kind := field.Kind()
 	switch {
 	case field.Cardinality() != protoreflect.Repeated:
-		return wire.SizeTag(num) + sizeSingular(num, kind, value)
+		return protowire.SizeTag(num) + o.sizeSingular(num, kind, value)
 	case field.IsMap():
-		return sizeMap(num, kind, field.MessageType(), value.Map())
-	case field.IsPacked():
-		return sizePacked(num, kind, value.List())
+		return o.sizeMap(num, kind, field.MessageType(), value.Map())
+	case field.IsPacked() || (o.Canonical && isPackable(kind)):
+		return o.sizePacked(num, kind, value.List())
 	default:
-		return sizeList(num, kind, value.List())
+		return o.sizeList(num, kind, value.List())
 	}
 }
 
-func sizeMap(num wire.Number, kind protoreflect.Kind, mdesc protoreflect.MessageDescriptor, mapv protoreflect.Map) (size int) {
+func (o MarshalOptions) sizeMap(num protowire.Number, kind protoreflect.Kind, mdesc protoreflect.MessageDescriptor, mapv protoreflect.Map) (size int) {
 	keyf := mdesc.Fields().ByNumber(1)
 	valf := mdesc.Fields().ByNumber(2)
 	mapv.Range(func(key protoreflect.MapKey, value protoreflect.Value) bool {
-		size += wire.SizeTag(num)
-		size += wire.SizeBytes(sizeField(keyf, key.Value()) + sizeField(valf, value))
+		size += protowire.SizeTag(num)
+		size += protowire.SizeBytes(o.sizeField(keyf, key.Value()) + o.sizeField(valf, value))
 		return true
 	})
 	return size
 }
 
-func sizePacked(num wire.Number, kind protoreflect.Kind, list protoreflect.List) (size int) {
+func (o MarshalOptions) sizePacked(num protowire.Number, kind protoreflect.Kind, list protoreflect.List) (size int) {
 	content := 0
 	for i, llen := 0, list.Len(); i < llen; i++ {
-		content += sizeSingular(num, kind, list.Get(i))
+		content += o.sizeSingular(num, kind, list.Get(i))
 	}
-	return wire.SizeTag(num) + wire.SizeBytes(content)
+	return protowire.SizeTag(num) + protowire.SizeBytes(content)
 }
 
-func sizeList(num wire.Number, kind protoreflect.Kind, list protoreflect.List) (size int) {
+func (o MarshalOptions) sizeList(num protowire.Number, kind protoreflect.Kind, list protoreflect.List) (size int) {
 	for i, llen := 0, list.Len(); i < llen; i++ {
-		size += wire.SizeTag(num) + sizeSingular(num, kind, list.Get(i))
+		size += protowire.SizeTag(num) + o.sizeSingular(num, kind, list.Get(i))
 	}
 	return size
 }
@@ -0,0 +1,133 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/v2/encoding/protowire"
+	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+)
+
+// FieldSize is the number of bytes taken by a field in the wire-format
+// encoding of a message.
+type FieldSize struct {
+	// Path is the path to the field, such as "a.b.c". Lists and maps are not
+	// indexed: the elements of a list and the entries of a map are aggregated
+	// under the path of their field, and so are the fields of message values
+	// within them. Extension fields are named by their full name in
+	// parentheses, and unknown fields by their field number.
+	Path string
+
+	// Size is the number of bytes taken by the field, including its tags
+	// and length prefixes. The size of a message field includes the sizes
+	// of the fields within it.
+	Size int
+
+	// Count is the number of values of the field: one for a singular field,
+	// the number of elements or entries for a list or map, and the number of
+	// wire-format records for an unknown field.
+	Count int
+}
+
+// FieldSizes is a list of field sizes, sorted by path.
+type FieldSizes []FieldSize
+
+// String formats the field sizes as a human-readable report
+// with one field per line.
+func (fs FieldSizes) String() string {
+	var b strings.Builder
+	for _, f := range fs {
+		fmt.Fprintf(&b, "%v: %d bytes in %d values\n", f.Path, f.Size, f.Count)
+	}
+	return b.String()
+}
+
+// SizeByField returns the number of bytes taken by each field in the
+// wire-format encoding of m.
+//
+// Every populated field is reported, including the fields of nested
+// messages. The sizes of the fields at the top level of m add up to Size(m).
+func SizeByField(m Message) FieldSizes {
+	s := fieldSizer{index: make(map[string]int)}
+	s.sizeMessage("", m.ProtoReflect())
+	sort.Slice(s.sizes, func(i, j int) bool { return s.sizes[i].Path < s.sizes[j].Path })
+	return s.sizes
+}
+
+type fieldSizer struct {
+	sizes FieldSizes
+	index map[string]int // index into sizes by path
+}
+
+func (s *fieldSizer) add(path string, size, count int) {
+	i, ok := s.index[path]
+	if !ok {
+		i = len(s.sizes)
+		s.index[path] = i
+		s.sizes = append(s.sizes, FieldSize{Path: path})
+	}
+	s.sizes[i].Size += size
+	s.sizes[i].Count += count
+}
+
+func (s *fieldSizer) sizeMessage(path string, m pref.Message) {
+	fields := m.Type().Fields()
+	knownFields := m.KnownFields()
+	knownFields.Range(func(num pref.FieldNumber, value pref.Value) bool {
+		field := fields.ByNumber(num)
+		if field == nil {
+			field = knownFields.ExtensionTypes().ByNumber(num)
+			if field == nil {
+				panic(fmt.Errorf("no descriptor for field %d in %q", num, m.Type().FullName()))
+			}
+		}
+		s.sizeField(joinPath(path, fieldName(field)), field, value)
+		return true
+	})
+	m.UnknownFields().Range(func(num pref.FieldNumber, raw pref.RawFields) bool {
+		count := 0
+		for b := raw; len(b) > 0; count++ {
+			_, _, n := protowire.ConsumeField(b)
+			if n < 0 {
+				break
+			}
+			b = b[n:]
+		}
+		s.add(joinPath(path, strconv.Itoa(int(num))), len(raw), count)
+		return true
+	})
+}
+
+func (s *fieldSizer) sizeField(path string, field pref.FieldDescriptor, value pref.Value) {
+	size := MarshalOptions{}.sizeField(field, value)
+	switch {
+	case field.IsMap():
+		mapv := value.Map()
+		s.add(path, size, mapv.Len())
+		if valf := field.MessageType().Fields().ByNumber(2); valf.MessageType() != nil {
+			mapv.Range(func(_ pref.MapKey, v pref.Value) bool {
+				s.sizeMessage(path, v.Message())
+				return true
+			})
+		}
+	case field.Cardinality() == pref.Repeated:
+		list := value.List()
+		s.add(path, size, list.Len())
+		if field.MessageType() != nil {
+			for i, llen := 0, list.Len(); i < llen; i++ {
+				s.sizeMessage(path, list.Get(i).Message())
+			}
+		}
+	default:
+		s.add(path, size, 1)
+		if field.MessageType() != nil {
+			s.sizeMessage(path, value.Message())
+		}
+	}
+}
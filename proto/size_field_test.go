@@ -0,0 +1,119 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/v2/internal/encoding/pack"
+	"github.com/golang/protobuf/v2/internal/scalar"
+	"github.com/golang/protobuf/v2/proto"
+
+	testpb "github.com/golang/protobuf/v2/internal/testprotos/test"
+)
+
+func TestSizeByField(t *testing.T) {
+	for _, test := range []struct {
+		desc string
+		m    proto.Message
+		want string
+	}{{
+		desc: "empty",
+		m:    &testpb.TestAllTypes{},
+		want: "",
+	}, {
+		desc: "scalars",
+		m: &testpb.TestAllTypes{
+			OptionalInt32:  scalar.Int32(1),
+			OptionalString: scalar.String("abc"),
+		},
+		want: "optional_int32: 2 bytes in 1 values\n" +
+			"optional_string: 5 bytes in 1 values\n",
+	}, {
+		desc: "nested messages",
+		m: &testpb.TestAllTypes{
+			Optionalgroup: &testpb.TestAllTypes_OptionalGroup{A: scalar.Int32(1)},
+			OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+				Corecursive: &testpb.TestAllTypes{OptionalInt32: scalar.Int32(1)},
+			},
+		},
+		want: "optional_nested_message: 7 bytes in 1 values\n" +
+			"optional_nested_message.corecursive: 4 bytes in 1 values\n" +
+			"optional_nested_message.corecursive.optional_int32: 2 bytes in 1 values\n" +
+			"optionalgroup: 7 bytes in 1 values\n" +
+			"optionalgroup.a: 3 bytes in 1 values\n",
+	}, {
+		desc: "lists",
+		m: &testpb.TestAllTypes{
+			RepeatedInt32: []int32{1, 2, 3},
+			RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{
+				{A: scalar.Int32(1)},
+				{},
+				{A: scalar.Int32(300)},
+			},
+		},
+		want: "repeated_int32: 9 bytes in 3 values\n" +
+			"repeated_nested_message: 14 bytes in 3 values\n" +
+			"repeated_nested_message.a: 5 bytes in 2 values\n",
+	}, {
+		desc: "maps",
+		m: &testpb.TestAllTypes{
+			MapInt32Int32: map[int32]int32{1: 2, 3: 4},
+			MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+				"a": {A: scalar.Int32(1)},
+				"b": {A: scalar.Int32(2)},
+			},
+		},
+		want: "map_int32_int32: 14 bytes in 2 values\n" +
+			"map_string_nested_message: 20 bytes in 2 values\n" +
+			"map_string_nested_message.a: 4 bytes in 2 values\n",
+	}, {
+		desc: "extensions",
+		m: build(
+			&testpb.TestAllExtensions{},
+			extend(testpb.E_OptionalInt32Extension, scalar.Int32(1)),
+			extend(testpb.E_OptionalNestedMessageExtension, &testpb.TestAllTypes_NestedMessage{A: scalar.Int32(1)}),
+		),
+		want: "(goproto.proto.test.optional_int32_extension): 2 bytes in 1 values\n" +
+			"(goproto.proto.test.optional_nested_message_extension): 5 bytes in 1 values\n" +
+			"(goproto.proto.test.optional_nested_message_extension).a: 2 bytes in 1 values\n",
+	}, {
+		desc: "unknown fields",
+		m: build(
+			&testpb.TestAllTypes{},
+			unknown(100000, pack.Message{
+				pack.Tag{100000, pack.VarintType}, pack.Varint(1),
+				pack.Tag{100000, pack.BytesType}, pack.Bytes("abc"),
+			}.Marshal()),
+		),
+		want: "100000: 11 bytes in 2 values\n",
+	}} {
+		t.Run(test.desc, func(t *testing.T) {
+			sizes := proto.SizeByField(test.m)
+			if got := sizes.String(); got != test.want {
+				t.Errorf("SizeByField(m):\ngot:\n%v\nwant:\n%v", got, test.want)
+			}
+			total := 0
+			for _, f := range sizes {
+				if isTopLevel(f.Path) {
+					total += f.Size
+				}
+			}
+			if want := proto.Size(test.m); total != want {
+				t.Errorf("sum of top-level sizes = %v, want Size(m) = %v", total, want)
+			}
+		})
+	}
+}
+
+// isTopLevel reports whether the path names a field at the top level
+// of a message, allowing for the dots in the names of extensions.
+func isTopLevel(path string) bool {
+	if strings.HasPrefix(path, "(") {
+		path = path[strings.Index(path, ")")+1:]
+	}
+	return !strings.Contains(path, ".")
+}
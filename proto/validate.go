@@ -0,0 +1,286 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/v2/encoding/protowire"
+	"github.com/golang/protobuf/v2/internal/errors"
+	"github.com/golang/protobuf/v2/reflect/protoreflect"
+	"github.com/golang/protobuf/v2/reflect/protoregistry"
+)
+
+// Validate checks that b is a valid wire-format encoding of a message
+// described by md, without unmarshaling it.
+func Validate(b []byte, md protoreflect.MessageDescriptor) error {
+	return UnmarshalOptions{}.Validate(b, md)
+}
+
+// Validate checks that b is a valid wire-format encoding of a message
+// described by md, without unmarshaling it or allocating any messages.
+//
+// It reports the errors that Unmarshal reports with the same options:
+// malformed input, input larger than MaxSize, messages nested more deeply
+// than RecursionLimit, invalid UTF-8 in proto3 string fields, and missing
+// required fields unless AllowPartial is set. In addition, Validate reports
+// fields whose wire type does not match their declared type, which Unmarshal
+// places in the unknown fields. Extension fields are resolved with Resolver.
+// Unknown fields and unresolved extensions are only checked to be well-formed.
+//
+// Required fields within a singular message field are not reported if the
+// field occurs in more than one record, since Unmarshal merges the records
+// into a single message.
+func (o UnmarshalOptions) Validate(b []byte, md protoreflect.MessageDescriptor) error {
+	if o.MaxSize > 0 && len(b) > o.MaxSize {
+		return ErrMaxSize
+	}
+	if o.RecursionLimit == 0 {
+		o.RecursionLimit = DefaultRecursionLimit
+	}
+	if o.Resolver == nil {
+		o.Resolver = protoregistry.GlobalTypes
+	}
+	_, err := o.validateMessage(b, md, 0)
+	return err
+}
+
+// validateMessage validates the fields of a message of type md in b.
+// If groupNum is non-zero, the message is the value of a group field with
+// that number, and the returned length includes the end group marker.
+func (o UnmarshalOptions) validateMessage(b []byte, md protoreflect.MessageDescriptor, groupNum protowire.Number) (n int, err error) {
+	o.RecursionLimit--
+	if o.RecursionLimit < 0 {
+		return 0, ErrRecursionLimit
+	}
+	fields := md.Fields()
+	required := md.RequiredNumbers()
+	var seen []bool
+	if required.Len() > 0 && !o.AllowPartial {
+		seen = make([]bool, required.Len())
+	}
+	var nerr errors.NonFatal
+
+	// Missing required fields within singular message fields are reported
+	// only if the field occurs once, as later records are merged into it.
+	// A nil entry in pending marks a field which occurred more than once.
+	var pending map[protowire.Number]error
+	var pendingNums []protowire.Number
+
+	pos := 0
+	for {
+		if pos == len(b) {
+			if groupNum != 0 {
+				return 0, errors.AddOffset(io.ErrUnexpectedEOF, pos)
+			}
+			break
+		}
+		num, wtyp, tagLen := protowire.ConsumeTag(b[pos:])
+		if tagLen < 0 {
+			return 0, errors.AddOffset(protowire.ParseError(tagLen), pos)
+		}
+		if wtyp == protowire.EndGroupType {
+			if num != groupNum {
+				return 0, errors.AddOffset(errors.New("mismatching end group marker"), pos)
+			}
+			pos += tagLen
+			break
+		}
+
+		fd := fields.ByNumber(num)
+		if fd == nil && md.ExtensionRanges().Has(num) {
+			xt, err := o.Resolver.FindExtensionByNumber(md.FullName(), num)
+			if err != nil && err != protoregistry.NotFound {
+				return 0, errors.AddOffset(errors.New("unable to resolve extension %v: %v", num, err), pos)
+			}
+			if xt != nil {
+				fd = xt
+			}
+		}
+		var valLen int
+		if fd == nil || fd.IsWeak() {
+			valLen = protowire.ConsumeFieldValueDepth(num, wtyp, b[pos+tagLen:], o.RecursionLimit)
+			if valLen < 0 {
+				return 0, errors.AddOffset(protowire.ParseError(valLen), pos+tagLen)
+			}
+		} else {
+			valLen, err = o.validateField(b[pos+tagLen:], wtyp, fd)
+			if err != nil {
+				err = errors.AddField(errors.AddOffset(err, pos+tagLen), fieldName(fd))
+				var ferr errors.NonFatal
+				if !ferr.Merge(err) {
+					return 0, err
+				}
+				err = ferr.E
+			}
+			if od := fd.OneofType(); od != nil {
+				// Setting a member of a oneof clears the others.
+				for i := 0; i < od.Fields().Len(); i++ {
+					if n := od.Fields().Get(i).Number(); n != num {
+						delete(pending, n)
+					}
+				}
+			}
+			isMessage := fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind
+			if isMessage && fd.Cardinality() != protoreflect.Repeated {
+				reqErr, otherErr := splitRequiredNotSet(err)
+				nerr.Merge(otherErr)
+				if pending == nil {
+					pending = make(map[protowire.Number]error)
+				}
+				if _, ok := pending[num]; ok {
+					pending[num] = nil
+				} else {
+					pending[num] = reqErr
+					pendingNums = append(pendingNums, num)
+				}
+			} else {
+				nerr.Merge(err)
+			}
+			if seen != nil && fd.Cardinality() == protoreflect.Required {
+				for i := 0; i < required.Len(); i++ {
+					if required.Get(i) == num {
+						seen[i] = true
+					}
+				}
+			}
+		}
+		pos += tagLen + valLen
+	}
+	for _, num := range pendingNums {
+		nerr.Merge(pending[num])
+	}
+	for i, ok := range seen {
+		if !ok {
+			nerr.AppendRequiredNotSet(string(fields.ByNumber(required.Get(i)).Name()))
+		}
+	}
+	return pos, nerr.E
+}
+
+// validateField validates a record of the field fd, starting after the tag.
+func (o UnmarshalOptions) validateField(b []byte, wtyp protowire.Type, fd protoreflect.FieldDescriptor) (n int, err error) {
+	switch {
+	case fd.IsMap():
+		if wtyp != protowire.BytesType {
+			return 0, wireTypeError(wtyp, protowire.BytesType)
+		}
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return 0, protowire.ParseError(n)
+		}
+		return n, errors.AddOffset(o.validateMapEntry(v, fd.MessageType()), n-len(v))
+	case fd.Cardinality() == protoreflect.Repeated && wtyp == protowire.BytesType && isPackable(fd.Kind()):
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return 0, protowire.ParseError(n)
+		}
+		for elemType := wireTypes[fd.Kind()]; len(v) > 0; {
+			m := protowire.ConsumeFieldValue(fd.Number(), elemType, v)
+			if m < 0 {
+				return 0, errors.AddOffset(protowire.ParseError(m), n-len(v))
+			}
+			v = v[m:]
+		}
+		return n, nil
+	default:
+		return o.validateValue(b, wtyp, fd)
+	}
+}
+
+// validateMapEntry validates the entry of a map with the entry type md.
+// Errors are indexed by the key of the entry, if present.
+func (o UnmarshalOptions) validateMapEntry(b []byte, md protoreflect.MessageDescriptor) error {
+	// The key is only decoded to report an error.
+	var keyField protoreflect.FieldDescriptor
+	var keyBytes []byte
+	var nerr errors.NonFatal
+	index := func(err error) error {
+		if err == nil || keyField == nil {
+			return err
+		}
+		key, _, _ := o.unmarshalScalar(keyBytes, wireTypes[keyField.Kind()], 1, keyField.Kind())
+		return errors.AddIndex(err, fmt.Sprint(key.MapKey()))
+	}
+	for pos := 0; pos < len(b); {
+		num, wtyp, tagLen := protowire.ConsumeTag(b[pos:])
+		if tagLen < 0 {
+			return index(errors.AddOffset(protowire.ParseError(tagLen), pos))
+		}
+		var valLen int
+		var err error
+		if fd := md.Fields().ByNumber(num); fd != nil {
+			valLen, err = o.validateValue(b[pos+tagLen:], wtyp, fd)
+			if err == nil && num == 1 {
+				keyField, keyBytes = fd, b[pos+tagLen:pos+tagLen+valLen]
+			}
+		} else {
+			valLen = protowire.ConsumeFieldValueDepth(num, wtyp, b[pos+tagLen:], o.RecursionLimit)
+			err = protowire.ParseError(valLen)
+		}
+		if err := errors.AddOffset(err, pos+tagLen); !nerr.Merge(err) {
+			return index(err)
+		}
+		pos += tagLen + valLen
+	}
+	return index(nerr.E)
+}
+
+// validateValue validates a singular value of the field fd.
+func (o UnmarshalOptions) validateValue(b []byte, wtyp protowire.Type, fd protoreflect.FieldDescriptor) (n int, err error) {
+	if want := wireTypes[fd.Kind()]; wtyp != want {
+		return 0, wireTypeError(wtyp, want)
+	}
+	switch fd.Kind() {
+	case protoreflect.MessageKind:
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return 0, protowire.ParseError(n)
+		}
+		if md := fd.MessageType(); !md.IsPlaceholder() {
+			_, err = o.validateMessage(v, md, 0)
+		}
+		return n, errors.AddOffset(err, n-len(v))
+	case protoreflect.GroupKind:
+		if md := fd.MessageType(); !md.IsPlaceholder() {
+			return o.validateMessage(b, md, fd.Number())
+		}
+	case protoreflect.StringKind:
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return 0, protowire.ParseError(n)
+		}
+		if enforceUTF8(fd) && !utf8.Valid(v) {
+			return n, &errors.InvalidUTF8Error{Offset: n - len(v)}
+		}
+		return n, nil
+	}
+	n = protowire.ConsumeFieldValueDepth(fd.Number(), wtyp, b, o.RecursionLimit)
+	if n < 0 {
+		return 0, protowire.ParseError(n)
+	}
+	return n, nil
+}
+
+// splitRequiredNotSet splits a list of non-fatal errors into the errors
+// reporting missing required fields and all others.
+func splitRequiredNotSet(err error) (required, other error) {
+	var rerr, oerr errors.NonFatal
+	es, _ := err.(errors.NonFatalErrors)
+	for _, e := range es {
+		if _, ok := e.(*errors.RequiredNotSetError); ok {
+			rerr.Merge(e)
+		} else {
+			oerr.Merge(e)
+		}
+	}
+	return rerr.E, oerr.E
+}
+
+func wireTypeError(got, want protowire.Type) error {
+	return errors.New("invalid wire type %d, want %d", got, want)
+}
@@ -0,0 +1,196 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/v2/internal/encoding/pack"
+	"github.com/golang/protobuf/v2/proto"
+	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+
+	testpb "github.com/golang/protobuf/v2/internal/testprotos/test"
+	test3pb "github.com/golang/protobuf/v2/internal/testprotos/test3"
+)
+
+func TestValidate(t *testing.T) {
+	for _, test := range testProtos {
+		if strings.Contains(test.desc, "mismatch") {
+			// Validate reports fields with the wrong wire type,
+			// which Unmarshal places in the unknown fields.
+			continue
+		}
+		for _, m := range test.decodeTo {
+			t.Run(fmt.Sprintf("%s (%T)", test.desc, m), func(t *testing.T) {
+				md := m.ProtoReflect().Type()
+				opts := proto.UnmarshalOptions{AllowPartial: test.partial}
+				if err := opts.Validate(test.wire, md); err != nil {
+					t.Errorf("Validate error: %v", err)
+				}
+				if test.partial {
+					var e *proto.RequiredNotSetError
+					if err := proto.Validate(test.wire, md); !errors.As(err, &e) {
+						t.Errorf("Validate without AllowPartial = %v, want RequiredNotSetError", err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	type errorCheck func(error) bool
+	isErr := func(target error) errorCheck {
+		return func(err error) bool { return errors.Is(err, target) }
+	}
+	isParseError := func(path string) errorCheck {
+		return func(err error) bool {
+			var e *proto.ParseError
+			return errors.As(err, &e) && e.Path == path
+		}
+	}
+	isInvalidUTF8 := func(path string) errorCheck {
+		return func(err error) bool {
+			var e *proto.InvalidUTF8Error
+			return errors.As(err, &e) && e.Path == path
+		}
+	}
+	isRequiredNotSet := func(path string) errorCheck {
+		return func(err error) bool {
+			var e *proto.RequiredNotSetError
+			return errors.As(err, &e) && e.Path == path
+		}
+	}
+	for _, test := range []struct {
+		desc  string
+		opts  proto.UnmarshalOptions
+		md    pref.MessageDescriptor
+		wire  []byte
+		check errorCheck
+	}{{
+		desc: "truncated varint",
+		md:   (&testpb.TestAllTypes{}).ProtoReflect().Type(),
+		wire: pack.Message{
+			pack.Tag{1, pack.VarintType}, pack.Raw{0x80},
+		}.Marshal(),
+		check: isErr(io.ErrUnexpectedEOF),
+	}, {
+		desc: "wrong wire type",
+		md:   (&testpb.TestAllTypes{}).ProtoReflect().Type(),
+		wire: pack.Message{
+			pack.Tag{18, pack.BytesType}, pack.LengthPrefix(pack.Message{
+				pack.Tag{1, pack.Fixed32Type}, pack.Uint32(1),
+			}),
+		}.Marshal(),
+		check: isParseError("optional_nested_message.a"),
+	}, {
+		desc: "wrong wire type in map entry",
+		md:   (&testpb.TestAllTypes{}).ProtoReflect().Type(),
+		wire: pack.Message{
+			pack.Tag{56, pack.BytesType}, pack.LengthPrefix(pack.Message{
+				pack.Tag{1, pack.VarintType}, pack.Varint(1),
+				pack.Tag{2, pack.BytesType}, pack.Bytes("x"),
+			}),
+		}.Marshal(),
+		check: isParseError("map_int32_int32[1]"),
+	}, {
+		desc: "truncated packed field",
+		md:   (&testpb.TestAllTypes{}).ProtoReflect().Type(),
+		wire: pack.Message{
+			pack.Tag{31, pack.BytesType}, pack.LengthPrefix{pack.Varint(1), pack.Raw{0x80}},
+		}.Marshal(),
+		check: isErr(io.ErrUnexpectedEOF),
+	}, {
+		desc: "unterminated group",
+		md:   (&testpb.TestAllTypes{}).ProtoReflect().Type(),
+		wire: pack.Message{
+			pack.Tag{16, pack.StartGroupType},
+			pack.Tag{17, pack.VarintType}, pack.Varint(1),
+		}.Marshal(),
+		check: isErr(io.ErrUnexpectedEOF),
+	}, {
+		desc: "mismatching end group",
+		md:   (&testpb.TestAllTypes{}).ProtoReflect().Type(),
+		wire: pack.Message{
+			pack.Tag{16, pack.StartGroupType},
+			pack.Tag{17, pack.EndGroupType},
+		}.Marshal(),
+		check: isParseError("optionalgroup"),
+	}, {
+		desc: "invalid UTF-8",
+		md:   (&test3pb.TestAllTypes{}).ProtoReflect().Type(),
+		wire: pack.Message{
+			pack.Tag{44, pack.BytesType}, pack.String("\xff"),
+		}.Marshal(),
+		check: isInvalidUTF8("repeated_string"),
+	}, {
+		desc: "invalid UTF-8 in map value",
+		md:   (&test3pb.TestAllTypes{}).ProtoReflect().Type(),
+		wire: pack.Message{
+			pack.Tag{69, pack.BytesType}, pack.LengthPrefix(pack.Message{
+				pack.Tag{1, pack.BytesType}, pack.String("k"),
+				pack.Tag{2, pack.BytesType}, pack.String("\xff"),
+			}),
+		}.Marshal(),
+		check: isInvalidUTF8("map_string_string[k]"),
+	}, {
+		desc: "proto2 string with invalid UTF-8",
+		md:   (&testpb.TestAllTypes{}).ProtoReflect().Type(),
+		wire: pack.Message{
+			pack.Tag{14, pack.BytesType}, pack.String("\xff"),
+		}.Marshal(),
+		check: isErr(nil),
+	}, {
+		desc: "required field not set",
+		md:   (&testpb.TestRequiredForeign{}).ProtoReflect().Type(),
+		wire: pack.Message{
+			pack.Tag{1, pack.BytesType}, pack.LengthPrefix(pack.Message{}),
+		}.Marshal(),
+		check: isRequiredNotSet("optional_message.required_field"),
+	}, {
+		desc: "recursion limit",
+		opts: proto.UnmarshalOptions{RecursionLimit: 2},
+		md:   (&testpb.TestAllTypes{}).ProtoReflect().Type(),
+		wire: pack.Message{
+			pack.Tag{18, pack.BytesType}, pack.LengthPrefix(pack.Message{
+				pack.Tag{2, pack.BytesType}, pack.LengthPrefix(pack.Message{}),
+			}),
+		}.Marshal(),
+		check: isErr(proto.ErrRecursionLimit),
+	}, {
+		desc:  "max size",
+		opts:  proto.UnmarshalOptions{MaxSize: 1},
+		md:    (&testpb.TestAllTypes{}).ProtoReflect().Type(),
+		wire:  pack.Message{pack.Tag{1, pack.VarintType}, pack.Varint(1)}.Marshal(),
+		check: isErr(proto.ErrMaxSize),
+	}} {
+		err := test.opts.Validate(test.wire, test.md)
+		if !test.check(err) {
+			t.Errorf("%v: Validate() = %v, want different error", test.desc, err)
+		}
+	}
+}
+
+func TestValidateMapAllocs(t *testing.T) {
+	b := pack.Message{
+		pack.Tag{69, pack.BytesType}, pack.LengthPrefix(pack.Message{
+			pack.Tag{1, pack.BytesType}, pack.String("key"),
+			pack.Tag{2, pack.BytesType}, pack.String("value"),
+		}),
+	}.Marshal()
+	md := (&test3pb.TestAllTypes{}).ProtoReflect().Type()
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := proto.Validate(b, md); err != nil {
+			t.Fatalf("Validate error: %v", err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Validate of a map entry allocated %v times, want 0", allocs)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protodesc
+
+import (
+	"reflect"
+
+	"github.com/golang/protobuf/v2/internal/errors"
+	"github.com/golang/protobuf/v2/proto"
+	"github.com/golang/protobuf/v2/reflect/protoreflect"
+)
+
+// GetOption returns the value of the custom option xt in the options of d,
+// where xt extends the options message for the kind of d
+// (e.g., google.protobuf.FieldOptions for a FieldDescriptor).
+//
+// The value is of the Go type of xt, as returned by xt.InterfaceOf.
+// If the option is not set, the default value of xt is returned.
+//
+// The option is found regardless of whether xt was registered when the options
+// were parsed, such that this works for descriptors obtained from generated
+// code, from prototype, and from NewFile alike.
+func GetOption(d protoreflect.Descriptor, xt protoreflect.ExtensionType) (interface{}, error) {
+	return getOption(d.Options(), xt)
+}
+
+// GetExtensionRangeOption returns the value of the custom option xt in the
+// options of the i-th extension range of md, where xt extends
+// google.protobuf.ExtensionRangeOptions. See GetOption for details.
+func GetExtensionRangeOption(md protoreflect.MessageDescriptor, i int, xt protoreflect.ExtensionType) (interface{}, error) {
+	return getOption(md.ExtensionRangeOptions(i), xt)
+}
+
+func getOption(opts protoreflect.OptionsMessage, xt protoreflect.ExtensionType) (interface{}, error) {
+	m, ok := opts.(proto.Message)
+	if !ok {
+		return nil, errors.New("invalid options message: %T", opts)
+	}
+	mt := m.ProtoReflect().Type()
+	if got, want := mt.FullName(), xt.ExtendedType().FullName(); got != want {
+		return nil, errors.New("option %v extends %v, not %v", xt.FullName(), want, got)
+	}
+	if rv := reflect.ValueOf(opts); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return xt.InterfaceOf(xt.New()), nil
+	}
+
+	// The option is held either as a value of xt, in the unknown fields if
+	// no extension type for the field was registered when the options were
+	// parsed, or as a value of another extension type for the same field.
+	// In the first two cases, proto.GetExtension reads the value directly.
+	knownFields := m.ProtoReflect().KnownFields()
+	xt2 := knownFields.ExtensionTypes().ByNumber(xt.Number())
+	if xt2 == nil || xt2 == xt {
+		return proto.GetExtension(m, xt), nil
+	}
+	if !knownFields.Has(xt.Number()) {
+		return xt.InterfaceOf(xt.New()), nil
+	}
+
+	// Otherwise, marshal the value of the other extension type alone,
+	// and decode it as a value of xt.
+	m2 := mt.New()
+	m2.KnownFields().ExtensionTypes().Register(xt2)
+	m2.KnownFields().Set(xt.Number(), knownFields.Get(xt.Number()))
+	var nerr errors.NonFatal
+	b, err := proto.MarshalOptions{AllowPartial: true}.Marshal(m2.Interface())
+	if !nerr.Merge(err) {
+		return nil, err
+	}
+	m3 := mt.New()
+	m3.UnknownFields().Set(xt.Number(), b)
+	return proto.GetExtension(m3.Interface(), xt), nerr.E
+}
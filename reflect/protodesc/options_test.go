@@ -0,0 +1,188 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protodesc_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/v2/internal/encoding/pack"
+	_ "github.com/golang/protobuf/v2/internal/legacy"
+	"github.com/golang/protobuf/v2/internal/scalar"
+	"github.com/golang/protobuf/v2/proto"
+	"github.com/golang/protobuf/v2/reflect/protodesc"
+	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+	preg "github.com/golang/protobuf/v2/reflect/protoregistry"
+	"github.com/golang/protobuf/v2/types/dynamicpb"
+
+	testpb "github.com/golang/protobuf/v2/internal/testprotos/test"
+	descriptorpb "github.com/golang/protobuf/v2/types/descriptor"
+)
+
+// withOption returns m with the custom option numbered num
+// set to v as an unknown field.
+func withOption(m proto.Message, num pref.FieldNumber, v uint64) proto.Message {
+	m.ProtoReflect().UnknownFields().Set(num, pack.Message{
+		pack.Tag{num, pack.VarintType}, pack.Varint(v),
+	}.Marshal())
+	return m
+}
+
+func TestGetOption(t *testing.T) {
+	// Declare a custom option for each kind of descriptor.
+	optionsFile := &descriptorpb.FileDescriptorProto{
+		Name:       scalar.String("options.proto"),
+		Package:    scalar.String("test.options"),
+		Dependency: []string{"google/protobuf/descriptor.proto"},
+	}
+	for i, extendee := range []string{
+		"FileOptions",
+		"MessageOptions",
+		"FieldOptions",
+		"OneofOptions",
+		"ExtensionRangeOptions",
+		"EnumOptions",
+		"EnumValueOptions",
+		"ServiceOptions",
+		"MethodOptions",
+	} {
+		optionsFile.Extension = append(optionsFile.Extension, &descriptorpb.FieldDescriptorProto{
+			Name:     scalar.String(extendee),
+			Number:   scalar.Int32(int32(50000 + i)),
+			Label:    descriptorpb.FieldDescriptorProto_Label(pref.Optional).Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_Type(pref.Int32Kind).Enum(),
+			Extendee: scalar.String(".google.protobuf." + extendee),
+		})
+	}
+	ofd, err := protodesc.NewFile(optionsFile, preg.GlobalFiles)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile() error: %v", err)
+	}
+	option := func(name pref.Name) pref.ExtensionType {
+		return dynamicpb.NewExtensionType(ofd.Extensions().ByName(name))
+	}
+
+	// The message options are held as a value of another extension type
+	// for the same option, rather than as unknown fields.
+	messageOptions := &descriptorpb.MessageOptions{}
+	b, err := proto.Marshal(withOption(&descriptorpb.MessageOptions{}, 50001, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = proto.UnmarshalOptions{Resolver: preg.NewTypes(option("MessageOptions"))}.Unmarshal(b, messageOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// These message options are held as a value of the extension type
+	// passed to GetOption.
+	messageOption := option("MessageOptions")
+	registeredOptions := &descriptorpb.MessageOptions{}
+	proto.SetExtension(registeredOptions, messageOption, int32(10))
+
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    scalar.String("test.proto"),
+		Package: scalar.String("test"),
+		Options: withOption(&descriptorpb.FileOptions{}, 50000, 1).(*descriptorpb.FileOptions),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name:    scalar.String("M"),
+			Options: messageOptions,
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:       scalar.String("f"),
+				Number:     scalar.Int32(1),
+				Label:      descriptorpb.FieldDescriptorProto_Label(pref.Optional).Enum(),
+				Type:       descriptorpb.FieldDescriptorProto_Type(pref.Int32Kind).Enum(),
+				OneofIndex: scalar.Int32(0),
+				Options:    withOption(&descriptorpb.FieldOptions{}, 50002, 3).(*descriptorpb.FieldOptions),
+			}, {
+				Name:   scalar.String("g"),
+				Number: scalar.Int32(2),
+				Label:  descriptorpb.FieldDescriptorProto_Label(pref.Optional).Enum(),
+				Type:   descriptorpb.FieldDescriptorProto_Type(pref.Int32Kind).Enum(),
+			}},
+			OneofDecl: []*descriptorpb.OneofDescriptorProto{{
+				Name:    scalar.String("o"),
+				Options: withOption(&descriptorpb.OneofOptions{}, 50003, 4).(*descriptorpb.OneofOptions),
+			}},
+			ExtensionRange: []*descriptorpb.DescriptorProto_ExtensionRange{{
+				Start:   scalar.Int32(100),
+				End:     scalar.Int32(200),
+				Options: withOption(&descriptorpb.ExtensionRangeOptions{}, 50004, 5).(*descriptorpb.ExtensionRangeOptions),
+			}},
+		}, {
+			Name:    scalar.String("N"),
+			Options: registeredOptions,
+		}},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name:    scalar.String("E"),
+			Options: withOption(&descriptorpb.EnumOptions{}, 50005, 6).(*descriptorpb.EnumOptions),
+			Value: []*descriptorpb.EnumValueDescriptorProto{{
+				Name:    scalar.String("V"),
+				Number:  scalar.Int32(0),
+				Options: withOption(&descriptorpb.EnumValueOptions{}, 50006, 7).(*descriptorpb.EnumValueOptions),
+			}},
+		}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name:    scalar.String("S"),
+			Options: withOption(&descriptorpb.ServiceOptions{}, 50007, 8).(*descriptorpb.ServiceOptions),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       scalar.String("Do"),
+				InputType:  scalar.String(".test.M"),
+				OutputType: scalar.String(".test.M"),
+				Options:    withOption(&descriptorpb.MethodOptions{}, 50008, 9).(*descriptorpb.MethodOptions),
+			}},
+		}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile() error: %v", err)
+	}
+	md := fd.Messages().Get(0)
+	ed := fd.Enums().Get(0)
+	sd := fd.Services().Get(0)
+
+	for _, test := range []struct {
+		desc   string
+		d      pref.Descriptor
+		option pref.Name
+		want   interface{}
+	}{
+		{"file", fd, "FileOptions", int32(1)},
+		{"message", md, "MessageOptions", int32(2)},
+		{"field", md.Fields().Get(0), "FieldOptions", int32(3)},
+		{"field without options", md.Fields().Get(1), "FieldOptions", int32(0)},
+		{"oneof", md.Oneofs().Get(0), "OneofOptions", int32(4)},
+		{"enum", ed, "EnumOptions", int32(6)},
+		{"enum value", ed.Values().Get(0), "EnumValueOptions", int32(7)},
+		{"service", sd, "ServiceOptions", int32(8)},
+		{"method", sd.Methods().Get(0), "MethodOptions", int32(9)},
+		{"generated message", (&testpb.TestAllTypes{}).ProtoReflect().Type(), "MessageOptions", int32(0)},
+	} {
+		got, err := protodesc.GetOption(test.d, option(test.option))
+		if err != nil {
+			t.Errorf("%v: GetOption() error: %v", test.desc, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%v: GetOption() = %v, want %v", test.desc, got, test.want)
+		}
+	}
+
+	got, err := protodesc.GetOption(fd.Messages().Get(1), messageOption)
+	if err != nil {
+		t.Errorf("GetOption() of a registered option error: %v", err)
+	} else if want := int32(10); got != want {
+		t.Errorf("GetOption() of a registered option = %v, want %v", got, want)
+	}
+
+	got, err = protodesc.GetExtensionRangeOption(md, 0, option("ExtensionRangeOptions"))
+	if err != nil {
+		t.Errorf("GetExtensionRangeOption() error: %v", err)
+	} else if want := int32(5); got != want {
+		t.Errorf("GetExtensionRangeOption() = %v, want %v", got, want)
+	}
+
+	if _, err := protodesc.GetOption(md, option("FieldOptions")); err == nil {
+		t.Errorf("GetOption() of a field option on a message = nil, want error")
+	}
+}
@@ -113,10 +113,35 @@ func NewFile(fd *descriptorpb.FileDescriptorProto, r *protoregistry.Files) (prot
 	if err != nil {
 		return nil, err
 	}
+	f.SourceLocations, err = sourceLocationsFromDescriptorProto(fd.GetSourceCodeInfo())
+	if err != nil {
+		return nil, err
+	}
 
 	return prototype.NewFile(&f)
 }
 
+func sourceLocationsFromDescriptorProto(sci *descriptorpb.SourceCodeInfo) (ls []protoreflect.SourceLocation, err error) {
+	for _, loc := range sci.GetLocation() {
+		var l protoreflect.SourceLocation
+		// The path of a location is non-nil, even if it refers to the file.
+		l.Path = append(protoreflect.SourcePath{}, loc.GetPath()...)
+		switch s := loc.GetSpan(); len(s) {
+		case 3:
+			l.StartLine, l.StartColumn, l.EndLine, l.EndColumn = int(s[0]), int(s[1]), int(s[0]), int(s[2])
+		case 4:
+			l.StartLine, l.StartColumn, l.EndLine, l.EndColumn = int(s[0]), int(s[1]), int(s[2]), int(s[3])
+		default:
+			return nil, errors.New("invalid span for source location %v: %v", loc.GetPath(), s)
+		}
+		l.LeadingDetachedComments = loc.GetLeadingDetachedComments()
+		l.LeadingComments = loc.GetLeadingComments()
+		l.TrailingComments = loc.GetTrailingComments()
+		ls = append(ls, l)
+	}
+	return ls, nil
+}
+
 func messagesFromDescriptorProto(mds []*descriptorpb.DescriptorProto, syntax protoreflect.Syntax, r *protoregistry.Files) (ms []prototype.Message, err error) {
 	for _, md := range mds {
 		var m prototype.Message
@@ -46,6 +46,33 @@ func ToFileDescriptorProto(file protoreflect.FileDescriptor) *descriptorpb.FileD
 	if syntax := file.Syntax(); syntax != protoreflect.Proto2 {
 		p.Syntax = scalar.String(file.Syntax().String())
 	}
+	if locs := file.SourceLocations(); locs.Len() > 0 {
+		p.SourceCodeInfo = &descriptorpb.SourceCodeInfo{}
+		for i := 0; i < locs.Len(); i++ {
+			p.SourceCodeInfo.Location = append(p.SourceCodeInfo.Location, toSourceLocationProto(locs.Get(i)))
+		}
+	}
+	return p
+}
+
+// toSourceLocationProto converts a SourceLocation to a
+// google.protobuf.SourceCodeInfo.Location.
+func toSourceLocationProto(l protoreflect.SourceLocation) *descriptorpb.SourceCodeInfo_Location {
+	p := &descriptorpb.SourceCodeInfo_Location{
+		Path:                    append([]int32{}, l.Path...),
+		LeadingDetachedComments: append([]string(nil), l.LeadingDetachedComments...),
+	}
+	if l.StartLine == l.EndLine {
+		p.Span = []int32{int32(l.StartLine), int32(l.StartColumn), int32(l.EndColumn)}
+	} else {
+		p.Span = []int32{int32(l.StartLine), int32(l.StartColumn), int32(l.EndLine), int32(l.EndColumn)}
+	}
+	if l.LeadingComments != "" {
+		p.LeadingComments = scalar.String(l.LeadingComments)
+	}
+	if l.TrailingComments != "" {
+		p.TrailingComments = scalar.String(l.TrailingComments)
+	}
 	return p
 }
 
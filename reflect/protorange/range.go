@@ -0,0 +1,260 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protorange traverses the populated values of a message.
+//
+// Range visits the message itself, then each populated field in field number
+// order, recursing into list elements, map values (in key order), and
+// messages. Each value is identified by its Path from the root message.
+package protorange
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	perrors "github.com/golang/protobuf/v2/internal/errors"
+	"github.com/golang/protobuf/v2/internal/fieldnum"
+	"github.com/golang/protobuf/v2/internal/mapsort"
+	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+	"github.com/golang/protobuf/v2/reflect/protoregistry"
+)
+
+// Break may be returned by a push function to skip the children of the
+// current value. The pop function is still called for the value.
+var Break = errors.New("break traversal of children in current value")
+
+// Terminate may be returned by a push or pop function to stop the traversal.
+// Range then returns nil.
+var Terminate = errors.New("terminate traversal")
+
+// StepKind is the kind of a step in a Path.
+type StepKind int
+
+const (
+	// FieldStep accesses the value of a field, which is a list or map
+	// for a repeated field.
+	FieldStep StepKind = iota + 1
+	// ListIndexStep accesses an element of a list.
+	ListIndexStep
+	// MapKeyStep accesses the value of a map entry.
+	MapKeyStep
+	// AnyExpandStep accesses the message packed in a google.protobuf.Any.
+	AnyExpandStep
+)
+
+func (k StepKind) String() string {
+	switch k {
+	case FieldStep:
+		return "field"
+	case ListIndexStep:
+		return "list index"
+	case MapKeyStep:
+		return "map key"
+	case AnyExpandStep:
+		return "any expand"
+	default:
+		return fmt.Sprintf("<unknown:%d>", k)
+	}
+}
+
+// Step is a single step in a Path.
+type Step struct {
+	Kind StepKind
+
+	// Field is the descriptor of the field accessed by a FieldStep.
+	Field pref.FieldDescriptor
+
+	// Index is the index of the element accessed by a ListIndexStep.
+	Index int
+
+	// Key is the key of the entry accessed by a MapKeyStep.
+	Key pref.MapKey
+
+	// TypeURL is the type URL of the message accessed by an AnyExpandStep.
+	TypeURL string
+}
+
+// String formats the step as it appears in a path.
+func (s Step) String() string {
+	switch s.Kind {
+	case FieldStep:
+		if s.Field.ExtendedType() != nil {
+			return "(" + string(s.Field.FullName()) + ")"
+		}
+		return string(s.Field.Name())
+	case ListIndexStep:
+		return "[" + strconv.Itoa(s.Index) + "]"
+	case MapKeyStep:
+		return "[" + fmt.Sprint(s.Key) + "]"
+	case AnyExpandStep:
+		return "[" + s.TypeURL + "]"
+	default:
+		return fmt.Sprintf("<unknown:%d>", s.Kind)
+	}
+}
+
+// Path is a sequence of steps from the root message to a value.
+// The path of the root message is empty.
+type Path []Step
+
+// String formats the path, such as "a.b[3].c". Extension fields are named by
+// their full name in parentheses. Expanded Any messages are named by their
+// type URL in brackets, such as "any.[type.googleapis.com/pkg.M].c".
+func (p Path) String() string {
+	var b strings.Builder
+	for i, s := range p {
+		if i > 0 && s.Kind != ListIndexStep && s.Kind != MapKeyStep {
+			b.WriteByte('.')
+		}
+		b.WriteString(s.String())
+	}
+	return b.String()
+}
+
+// Options configures the traversal of a message.
+type Options struct {
+	// Resolver and Unmarshal are used to expand google.protobuf.Any messages.
+	// If either is nil, or the type of the packed message is not found,
+	// the fields of an Any message are visited as those of any other message.
+	// Otherwise, the packed message is visited in their place
+	// with an AnyExpandStep.
+	Resolver *protoregistry.Types
+
+	// Unmarshal decodes the value of an Any message into m,
+	// such as proto.Unmarshal.
+	Unmarshal func(b []byte, m pref.ProtoMessage) error
+}
+
+// Range calls f for every populated value in m, including m itself,
+// in pre-order. See Options.Range for the handling of errors returned by f.
+func Range(m pref.Message, f func(Path, pref.Value) error) error {
+	return Options{}.Range(m, f, nil)
+}
+
+// Range traverses every populated value in m, including m itself.
+// For each value, push is called before its children are visited and pop
+// after, unless the traversal stops first. Either function may be nil.
+//
+// The Path passed to push and pop is only valid for the duration of the
+// call, and must be copied to be retained.
+//
+// If push returns Break, the children of the value are skipped.
+// If either function returns Terminate, the traversal stops and Range
+// returns nil. Any other error stops the traversal and is returned by Range.
+// An error unmarshaling the contents of an Any message is also returned.
+func (o Options) Range(m pref.Message, push, pop func(Path, pref.Value) error) error {
+	r := ranger{opts: o, push: push, pop: pop}
+	err := r.rangeValue(nil, nil, pref.ValueOf(m))
+	if err == Terminate {
+		return nil
+	}
+	return err
+}
+
+type ranger struct {
+	opts      Options
+	push, pop func(Path, pref.Value) error
+}
+
+// rangeValue visits v and its children. The field fd is the field
+// holding v (nil for the root message and expanded Any messages).
+func (r *ranger) rangeValue(p Path, fd pref.FieldDescriptor, v pref.Value) error {
+	if r.push != nil {
+		switch err := r.push(p, v); err {
+		case nil:
+			if err := r.rangeChildren(p, fd, v); err != nil {
+				return err
+			}
+		case Break:
+		default:
+			return err
+		}
+	} else if err := r.rangeChildren(p, fd, v); err != nil {
+		return err
+	}
+	if r.pop != nil {
+		return r.pop(p, v)
+	}
+	return nil
+}
+
+// rangeChildren visits the elements of a list or map and the fields
+// of a message. Other values have no children.
+func (r *ranger) rangeChildren(p Path, fd pref.FieldDescriptor, v pref.Value) error {
+	switch x := v.Interface().(type) {
+	case pref.Message:
+		return r.rangeMessage(p, x)
+	case pref.List:
+		for i := 0; i < x.Len(); i++ {
+			if err := r.rangeValue(append(p, Step{Kind: ListIndexStep, Index: i}), fd, x.Get(i)); err != nil {
+				return err
+			}
+		}
+	case pref.Map:
+		keyKind := fd.MessageType().Fields().ByNumber(1).Kind()
+		valField := fd.MessageType().Fields().ByNumber(2)
+		var err error
+		mapsort.Range(x, keyKind, func(k pref.MapKey, v pref.Value) bool {
+			err = r.rangeValue(append(p, Step{Kind: MapKeyStep, Key: k}), valField, v)
+			return err == nil
+		})
+		return err
+	}
+	return nil
+}
+
+func (r *ranger) rangeMessage(p Path, m pref.Message) error {
+	if m.Type().FullName() == "google.protobuf.Any" {
+		if em, url, err := r.expandAny(m); err != nil {
+			return err
+		} else if em != nil {
+			return r.rangeValue(append(p, Step{Kind: AnyExpandStep, TypeURL: url}), nil, pref.ValueOf(em))
+		}
+	}
+	fields := m.Type().Fields()
+	knownFields := m.KnownFields()
+	var nums []pref.FieldNumber
+	knownFields.Range(func(num pref.FieldNumber, _ pref.Value) bool {
+		nums = append(nums, num)
+		return true
+	})
+	sort.Slice(nums, func(i, j int) bool { return nums[i] < nums[j] })
+	for _, num := range nums {
+		field := fields.ByNumber(num)
+		if field == nil {
+			field = knownFields.ExtensionTypes().ByNumber(num)
+			if field == nil {
+				panic(fmt.Errorf("no descriptor for field %d in %q", num, m.Type().FullName()))
+			}
+		}
+		if err := r.rangeValue(append(p, Step{Kind: FieldStep, Field: field}), field, knownFields.Get(num)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandAny returns the message packed in the Any message m,
+// or nil if it is not to be expanded.
+func (r *ranger) expandAny(m pref.Message) (pref.Message, string, error) {
+	if r.opts.Resolver == nil || r.opts.Unmarshal == nil {
+		return nil, "", nil
+	}
+	knownFields := m.KnownFields()
+	url := knownFields.Get(fieldnum.Any_TypeUrl).String()
+	mt, err := r.opts.Resolver.FindMessageByURL(url)
+	if err != nil {
+		return nil, "", nil
+	}
+	em := mt.New().Interface()
+	err = r.opts.Unmarshal(knownFields.Get(fieldnum.Any_Value).Bytes(), em)
+	var nerr perrors.NonFatal
+	if !nerr.Merge(err) {
+		return nil, "", err
+	}
+	return em.ProtoReflect(), url, nil
+}
@@ -0,0 +1,239 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protorange_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	protoV1 "github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/v2/internal/scalar"
+	"github.com/golang/protobuf/v2/proto"
+	"github.com/golang/protobuf/v2/reflect/protorange"
+	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+	preg "github.com/golang/protobuf/v2/reflect/protoregistry"
+
+	testpb "github.com/golang/protobuf/v2/internal/testprotos/test"
+	knownpb "github.com/golang/protobuf/v2/types/known"
+)
+
+// trace records the paths and values visited by a traversal.
+type trace struct {
+	lines []string
+}
+
+func (t *trace) push(p protorange.Path, v pref.Value) error {
+	t.lines = append(t.lines, fmt.Sprintf("push %v: %v", p, formatValue(v)))
+	return nil
+}
+
+func (t *trace) pop(p protorange.Path, v pref.Value) error {
+	t.lines = append(t.lines, fmt.Sprintf("pop %v", p))
+	return nil
+}
+
+func (t *trace) String() string {
+	return strings.Join(t.lines, "\n")
+}
+
+func formatValue(v pref.Value) string {
+	switch x := v.Interface().(type) {
+	case pref.Message:
+		return "{" + string(x.Type().FullName()) + "}"
+	case pref.List:
+		return fmt.Sprintf("<%d elements>", x.Len())
+	case pref.Map:
+		return fmt.Sprintf("<%d entries>", x.Len())
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+func TestRange(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		OptionalInt32:         scalar.Int32(1),
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: scalar.Int32(2)},
+		RepeatedInt32:         []int32{3, 4},
+		MapInt32Int32:         map[int32]int32{6: 7, 5: 6},
+	}
+	var tr trace
+	if err := (protorange.Options{}).Range(m.ProtoReflect(), tr.push, tr.pop); err != nil {
+		t.Fatalf("Range() error: %v", err)
+	}
+	want := strings.Join([]string{
+		"push : {goproto.proto.test.TestAllTypes}",
+		"push optional_int32: 1",
+		"pop optional_int32",
+		"push optional_nested_message: {goproto.proto.test.TestAllTypes.NestedMessage}",
+		"push optional_nested_message.a: 2",
+		"pop optional_nested_message.a",
+		"pop optional_nested_message",
+		"push repeated_int32: <2 elements>",
+		"push repeated_int32[0]: 3",
+		"pop repeated_int32[0]",
+		"push repeated_int32[1]: 4",
+		"pop repeated_int32[1]",
+		"pop repeated_int32",
+		"push map_int32_int32: <2 entries>",
+		"push map_int32_int32[5]: 6",
+		"pop map_int32_int32[5]",
+		"push map_int32_int32[6]: 7",
+		"pop map_int32_int32[6]",
+		"pop map_int32_int32",
+		"pop ",
+	}, "\n")
+	if got := tr.String(); got != want {
+		t.Errorf("Range() visited:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestRangePaths(t *testing.T) {
+	m := &testpb.TestAllExtensions{}
+	if err := protoV1.SetExtension(m, testpb.E_RepeatedNestedMessageExtension, []*testpb.TestAllTypes_NestedMessage{
+		{Corecursive: &testpb.TestAllTypes{
+			MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+				"k": {A: scalar.Int32(1)},
+			},
+		}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	err := protorange.Range(m.ProtoReflect(), func(p protorange.Path, _ pref.Value) error {
+		paths = append(paths, p.String())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Range() error: %v", err)
+	}
+	want := []string{
+		"",
+		"(goproto.proto.test.repeated_nested_message_extension)",
+		"(goproto.proto.test.repeated_nested_message_extension)[0]",
+		"(goproto.proto.test.repeated_nested_message_extension)[0].corecursive",
+		"(goproto.proto.test.repeated_nested_message_extension)[0].corecursive.map_string_nested_message",
+		"(goproto.proto.test.repeated_nested_message_extension)[0].corecursive.map_string_nested_message[k]",
+		"(goproto.proto.test.repeated_nested_message_extension)[0].corecursive.map_string_nested_message[k].a",
+	}
+	if got := strings.Join(paths, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("Range() visited paths:\n%v\nwant:\n%v", got, strings.Join(want, "\n"))
+	}
+}
+
+func TestRangeStop(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		OptionalInt32: scalar.Int32(1),
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+			A: scalar.Int32(2),
+		},
+		RepeatedInt32: []int32{3},
+	}
+	stopAt := func(path string, stop error) func(protorange.Path, pref.Value) error {
+		return func(p protorange.Path, _ pref.Value) error {
+			if p.String() == path {
+				return stop
+			}
+			return nil
+		}
+	}
+	errTest := errors.New("test error")
+	for _, test := range []struct {
+		desc    string
+		push    func(protorange.Path, pref.Value) error
+		want    []string
+		wantErr error
+	}{{
+		desc: "break",
+		push: stopAt("optional_nested_message", protorange.Break),
+		want: []string{"", "optional_int32", "optional_nested_message", "repeated_int32", "repeated_int32[0]"},
+	}, {
+		desc: "terminate",
+		push: stopAt("optional_nested_message.a", protorange.Terminate),
+		want: []string{"", "optional_int32", "optional_nested_message", "optional_nested_message.a"},
+	}, {
+		desc:    "error",
+		push:    stopAt("optional_int32", errTest),
+		want:    []string{"", "optional_int32"},
+		wantErr: errTest,
+	}} {
+		t.Run(test.desc, func(t *testing.T) {
+			var paths []string
+			err := protorange.Range(m.ProtoReflect(), func(p protorange.Path, v pref.Value) error {
+				paths = append(paths, p.String())
+				return test.push(p, v)
+			})
+			if err != test.wantErr {
+				t.Errorf("Range() = %v, want %v", err, test.wantErr)
+			}
+			if got, want := strings.Join(paths, ","), strings.Join(test.want, ","); got != want {
+				t.Errorf("Range() visited paths %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestRangeAny(t *testing.T) {
+	b, err := proto.Marshal(&testpb.TestAllTypes{OptionalInt32: scalar.Int32(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &knownpb.Any{
+		TypeUrl: "type.googleapis.com/goproto.proto.test.TestAllTypes",
+		Value:   b,
+	}
+	for _, test := range []struct {
+		desc string
+		opts protorange.Options
+		want []string
+	}{{
+		desc: "not expanded",
+		opts: protorange.Options{},
+		want: []string{"", "type_url", "value"},
+	}, {
+		desc: "expanded",
+		opts: protorange.Options{
+			Resolver:  preg.NewTypes((&testpb.TestAllTypes{}).ProtoReflect().Type()),
+			Unmarshal: proto.Unmarshal,
+		},
+		want: []string{
+			"",
+			"[type.googleapis.com/goproto.proto.test.TestAllTypes]",
+			"[type.googleapis.com/goproto.proto.test.TestAllTypes].optional_int32",
+		},
+	}, {
+		desc: "type not found",
+		opts: protorange.Options{
+			Resolver:  preg.NewTypes(),
+			Unmarshal: proto.Unmarshal,
+		},
+		want: []string{"", "type_url", "value"},
+	}} {
+		t.Run(test.desc, func(t *testing.T) {
+			var paths []string
+			err := test.opts.Range(m.ProtoReflect(), func(p protorange.Path, _ pref.Value) error {
+				paths = append(paths, p.String())
+				return nil
+			}, nil)
+			if err != nil {
+				t.Fatalf("Range() error: %v", err)
+			}
+			if got, want := strings.Join(paths, ","), strings.Join(test.want, ","); got != want {
+				t.Errorf("Range() visited paths %q, want %q", got, want)
+			}
+		})
+	}
+
+	// Errors unmarshaling the packed message are reported.
+	m.Value = []byte{0xff}
+	err = protorange.Options{
+		Resolver:  preg.NewTypes((&testpb.TestAllTypes{}).ProtoReflect().Type()),
+		Unmarshal: proto.Unmarshal,
+	}.Range(m.ProtoReflect(), nil, nil)
+	if err == nil {
+		t.Errorf("Range() of Any with invalid value = nil, want error")
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protoreadonly provides read-only views of messages.
+//
+// A read-only view provides the accessors of protoreflect.Message,
+// while every operation that would mutate the message panics.
+// This includes mutating the messages, lists, and maps within it,
+// which are themselves provided as read-only views.
+//
+// A view is also a proto.Message, and may be passed to functions that only
+// read a message, such as proto.Marshal, proto.Equal, or the encoding packages.
+package protoreadonly
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/v2/proto"
+	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+)
+
+// View returns a read-only view of m.
+//
+// The view reflects later changes to m made other than through the view.
+// Reading through the view is safe for concurrent use to the same extent
+// as reading m directly.
+func View(m pref.Message) pref.Message {
+	if m, ok := m.(*message); ok {
+		return m
+	}
+	return &message{m}
+}
+
+// Freeze returns a read-only view of a copy of m,
+// which is unaffected by later changes to m.
+func Freeze(m proto.Message) pref.Message {
+	return View(proto.Clone(m).ProtoReflect())
+}
+
+// IsReadOnly reports whether m is a read-only view.
+func IsReadOnly(m pref.Message) bool {
+	_, ok := m.(*message)
+	return ok
+}
+
+func panicReadOnly(op string, name pref.FullName) {
+	panic(fmt.Sprintf("invalid %v of read-only %v", op, name))
+}
+
+// view returns a read-only view of v if it is a message, list, or map.
+func view(v pref.Value) pref.Value {
+	if !v.IsValid() {
+		return v
+	}
+	switch x := v.Interface().(type) {
+	case pref.Message:
+		return pref.ValueOf(View(x))
+	case pref.List:
+		return pref.ValueOf(&list{x})
+	case pref.Map:
+		return pref.ValueOf(&mapView{x})
+	default:
+		return v
+	}
+}
+
+type message struct {
+	m pref.Message
+}
+
+func (m *message) ProtoReflect() pref.Message    { return m }
+func (m *message) Type() pref.MessageType        { return m.m.Type() }
+func (m *message) KnownFields() pref.KnownFields { return &knownFields{m.m.KnownFields(), m.m.Type()} }
+func (m *message) UnknownFields() pref.UnknownFields {
+	return &unknownFields{m.m.UnknownFields(), m.m.Type()}
+}
+func (m *message) Interface() pref.ProtoMessage { return m }
+
+type knownFields struct {
+	fs  pref.KnownFields
+	typ pref.MessageType
+}
+
+func (fs *knownFields) Len() int                          { return fs.fs.Len() }
+func (fs *knownFields) Has(n pref.FieldNumber) bool       { return fs.fs.Has(n) }
+func (fs *knownFields) Get(n pref.FieldNumber) pref.Value { return view(fs.fs.Get(n)) }
+func (fs *knownFields) Set(pref.FieldNumber, pref.Value)  { panicReadOnly("Set", fs.typ.FullName()) }
+func (fs *knownFields) Clear(pref.FieldNumber)            { panicReadOnly("Clear", fs.typ.FullName()) }
+func (fs *knownFields) WhichOneof(s pref.Name) pref.FieldNumber {
+	return fs.fs.WhichOneof(s)
+}
+func (fs *knownFields) Range(f func(pref.FieldNumber, pref.Value) bool) {
+	fs.fs.Range(func(n pref.FieldNumber, v pref.Value) bool {
+		return f(n, view(v))
+	})
+}
+func (fs *knownFields) NewMessage(n pref.FieldNumber) pref.Message {
+	return fs.fs.NewMessage(n)
+}
+func (fs *knownFields) ExtensionTypes() pref.ExtensionFieldTypes {
+	return &extensionTypes{fs.fs.ExtensionTypes(), fs.typ}
+}
+
+type extensionTypes struct {
+	xs  pref.ExtensionFieldTypes
+	typ pref.MessageType
+}
+
+func (xs *extensionTypes) Len() int                                       { return xs.xs.Len() }
+func (xs *extensionTypes) Register(pref.ExtensionType)                    { panicReadOnly("Register", xs.typ.FullName()) }
+func (xs *extensionTypes) Remove(pref.ExtensionType)                      { panicReadOnly("Remove", xs.typ.FullName()) }
+func (xs *extensionTypes) ByNumber(n pref.FieldNumber) pref.ExtensionType { return xs.xs.ByNumber(n) }
+func (xs *extensionTypes) ByName(s pref.FullName) pref.ExtensionType      { return xs.xs.ByName(s) }
+func (xs *extensionTypes) Range(f func(pref.ExtensionType) bool)          { xs.xs.Range(f) }
+
+// unknownFields reports that Set is not supported,
+// and panics if it is called regardless.
+type unknownFields struct {
+	fs  pref.UnknownFields
+	typ pref.MessageType
+}
+
+func (fs *unknownFields) Len() int                              { return fs.fs.Len() }
+func (fs *unknownFields) Get(n pref.FieldNumber) pref.RawFields { return fs.fs.Get(n) }
+func (fs *unknownFields) Set(pref.FieldNumber, pref.RawFields) {
+	panicReadOnly("Set", fs.typ.FullName())
+}
+func (fs *unknownFields) Range(f func(pref.FieldNumber, pref.RawFields) bool) { fs.fs.Range(f) }
+func (fs *unknownFields) IsSupported() bool                                   { return false }
+
+type list struct {
+	l pref.List
+}
+
+func (l *list) Len() int                 { return l.l.Len() }
+func (l *list) Get(i int) pref.Value     { return view(l.l.Get(i)) }
+func (l *list) Set(int, pref.Value)      { panicReadOnly("Set", "list") }
+func (l *list) Append(pref.Value)        { panicReadOnly("Append", "list") }
+func (l *list) Truncate(int)             { panicReadOnly("Truncate", "list") }
+func (l *list) NewMessage() pref.Message { return l.l.NewMessage() }
+
+type mapView struct {
+	m pref.Map
+}
+
+func (m *mapView) Len() int                     { return m.m.Len() }
+func (m *mapView) Has(k pref.MapKey) bool       { return m.m.Has(k) }
+func (m *mapView) Get(k pref.MapKey) pref.Value { return view(m.m.Get(k)) }
+func (m *mapView) Set(pref.MapKey, pref.Value)  { panicReadOnly("Set", "map") }
+func (m *mapView) Clear(pref.MapKey)            { panicReadOnly("Clear", "map") }
+func (m *mapView) NewMessage() pref.Message     { return m.m.NewMessage() }
+func (m *mapView) Range(f func(pref.MapKey, pref.Value) bool) {
+	m.m.Range(func(k pref.MapKey, v pref.Value) bool {
+		return f(k, view(v))
+	})
+}
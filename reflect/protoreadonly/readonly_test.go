@@ -0,0 +1,121 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoreadonly_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/v2/internal/encoding/pack"
+	"github.com/golang/protobuf/v2/internal/scalar"
+	"github.com/golang/protobuf/v2/proto"
+	"github.com/golang/protobuf/v2/reflect/protoreadonly"
+	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+
+	testpb "github.com/golang/protobuf/v2/internal/testprotos/test"
+)
+
+func newMessage() *testpb.TestAllTypes {
+	m := &testpb.TestAllTypes{
+		OptionalInt32:         scalar.Int32(1),
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: scalar.Int32(2)},
+		RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{{A: scalar.Int32(3)}},
+		MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+			"k": {A: scalar.Int32(4)},
+		},
+	}
+	m.ProtoReflect().UnknownFields().Set(100000, pack.Message{
+		pack.Tag{100000, pack.VarintType}, pack.Varint(5),
+	}.Marshal())
+	return m
+}
+
+func TestView(t *testing.T) {
+	m := newMessage()
+	v := protoreadonly.View(m.ProtoReflect())
+	if !protoreadonly.IsReadOnly(v) || protoreadonly.IsReadOnly(m.ProtoReflect()) {
+		t.Errorf("IsReadOnly(view), IsReadOnly(m) = %v, %v, want true, false",
+			protoreadonly.IsReadOnly(v), protoreadonly.IsReadOnly(m.ProtoReflect()))
+	}
+	if protoreadonly.View(v) != v {
+		t.Errorf("View(view) is not the view itself")
+	}
+
+	// Reading through the view is the same as reading the message.
+	if !proto.Equal(v.Interface(), m) {
+		t.Errorf("Equal(view, m) = false, want true")
+	}
+	want, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := proto.MarshalOptions{Deterministic: true}.Marshal(v.Interface())
+	if err != nil {
+		t.Fatalf("Marshal(view) error: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Marshal(view) = %x, want %x", got, want)
+	}
+	if v.UnknownFields().IsSupported() {
+		t.Errorf("UnknownFields().IsSupported() = true, want false")
+	}
+
+	// The view reflects changes to the message.
+	m.OptionalInt32 = scalar.Int32(10)
+	if got := v.KnownFields().Get(1).Int(); got != 10 {
+		t.Errorf("optional_int32 through view = %v, want 10", got)
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	m := newMessage()
+	v := protoreadonly.Freeze(m)
+	m.OptionalInt32 = scalar.Int32(10)
+	m.OptionalNestedMessage.A = scalar.Int32(20)
+	if got := v.KnownFields().Get(1).Int(); got != 1 {
+		t.Errorf("optional_int32 of frozen copy = %v, want 1", got)
+	}
+	nested := v.KnownFields().Get(18).Message()
+	if got := nested.KnownFields().Get(1).Int(); got != 2 {
+		t.Errorf("optional_nested_message.a of frozen copy = %v, want 2", got)
+	}
+	if !protoreadonly.IsReadOnly(nested) {
+		t.Errorf("IsReadOnly(optional_nested_message) = false, want true")
+	}
+}
+
+func TestMutationPanics(t *testing.T) {
+	v := protoreadonly.View(newMessage().ProtoReflect())
+	fs := v.KnownFields()
+	list := fs.Get(48).List()
+	mapv := fs.Get(71).Map()
+	nested := fs.Get(18).Message().KnownFields()
+	for _, test := range []struct {
+		desc   string
+		mutate func()
+	}{
+		{"KnownFields.Set", func() { fs.Set(1, pref.ValueOf(int32(2))) }},
+		{"KnownFields.Clear", func() { fs.Clear(1) }},
+		{"ExtensionTypes.Register", func() { fs.ExtensionTypes().Register(testpb.E_OptionalInt32Extension.Type) }},
+		{"UnknownFields.Set", func() { v.UnknownFields().Set(100000, nil) }},
+		{"List.Set", func() { list.Set(0, pref.ValueOf(list.NewMessage())) }},
+		{"List.Append", func() { list.Append(pref.ValueOf(list.NewMessage())) }},
+		{"List.Truncate", func() { list.Truncate(0) }},
+		{"Map.Set", func() { mapv.Set(pref.ValueOf("k2").MapKey(), pref.ValueOf(mapv.NewMessage())) }},
+		{"Map.Clear", func() { mapv.Clear(pref.ValueOf("k").MapKey()) }},
+		{"nested message", func() { nested.Set(1, pref.ValueOf(int32(5))) }},
+		{"list element", func() { list.Get(0).Message().KnownFields().Clear(1) }},
+		{"map value", func() { mapv.Get(pref.ValueOf("k").MapKey()).Message().KnownFields().Clear(1) }},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%v did not panic", test.desc)
+				}
+			}()
+			test.mutate()
+		})
+	}
+}
@@ -30,7 +30,7 @@ import (
 	"regexp"
 	"strings"
 
-	"github.com/golang/protobuf/v2/internal/encoding/wire"
+	"github.com/golang/protobuf/v2/encoding/protowire"
 	"github.com/golang/protobuf/v2/internal/pragma"
 )
 
@@ -266,7 +266,7 @@ func (k Kind) GoString() string {
 }
 
 // FieldNumber is the field number in a message.
-type FieldNumber = wire.Number
+type FieldNumber = protowire.Number
 
 // FieldNumbers represent a list of field numbers.
 type FieldNumbers interface {
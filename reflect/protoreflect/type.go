@@ -137,6 +137,12 @@ type FileDescriptor interface {
 	// by full name. It returns nil if not found.
 	DescriptorByName(FullName) Descriptor
 
+	// SourceLocations is a list of source locations within this file.
+	// It is empty if the file was constructed without source code information.
+	// In particular, protoc-gen-go strips the source code information from
+	// the descriptors it generates, so files in generated code have none.
+	SourceLocations() SourceLocations
+
 	isFileDescriptor
 }
 type isFileDescriptor interface{ ProtoType(FileDescriptor) }
@@ -174,6 +180,49 @@ type FileImport struct {
 	IsWeak bool
 }
 
+// SourceLocations is a list of source locations and
+// corresponds with the google.protobuf.SourceCodeInfo message.
+type SourceLocations interface {
+	// Len reports the number of source locations.
+	Len() int
+	// Get returns the ith SourceLocation. It panics if out of bounds.
+	Get(i int) SourceLocation
+	// ByDescriptor returns the SourceLocation for the given descriptor,
+	// which must be declared within the file (or be the file itself).
+	// It returns the zero SourceLocation if not found.
+	ByDescriptor(d Descriptor) SourceLocation
+
+	doNotImplement
+}
+
+// SourceLocation describes the location of a declaration in a proto file and
+// corresponds with the google.protobuf.SourceCodeInfo.Location message.
+type SourceLocation struct {
+	// Path is the path to the declaration within the file descriptor.
+	// It is nil for the zero SourceLocation.
+	Path SourcePath
+
+	// StartLine, StartColumn, EndLine, and EndColumn are the zero-based
+	// span of the declaration in the source file. The end is exclusive.
+	StartLine, StartColumn, EndLine, EndColumn int
+
+	// LeadingDetachedComments are the comment blocks before the declaration
+	// which are separated from it and from each other by blank lines.
+	LeadingDetachedComments []string
+	// LeadingComments is the comment block immediately before the declaration.
+	LeadingComments string
+	// TrailingComments is the comment immediately after the declaration.
+	TrailingComments string
+}
+
+// SourcePath identifies a part of a file descriptor by a sequence of
+// field numbers and list indexes within the google.protobuf.FileDescriptorProto
+// message. For example, the path [4, 3, 2, 7] refers to the field at index 7
+// of the message at index 3 of the messages at the top-level of the file:
+// FileDescriptorProto.message_type (4), index 3, then
+// DescriptorProto.field (2), index 7.
+type SourcePath []int32
+
 // MessageDescriptor describes a message and
 // corresponds with the google.protobuf.DescriptorProto message.
 //
@@ -399,6 +448,7 @@ type ExtensionDescriptors interface {
 // relative to the parent that it is declared within.
 //
 // For example:
+//
 //	syntax = "proto2";
 //	package example;
 //	message FooMessage {
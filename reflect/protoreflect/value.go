@@ -4,7 +4,7 @@
 
 package protoreflect
 
-import "github.com/golang/protobuf/v2/internal/encoding/wire"
+import "github.com/golang/protobuf/v2/encoding/protowire"
 
 // Enum is a reflection interface for a concrete enum value,
 // which provides type information and a getter for the enum number.
@@ -150,10 +150,11 @@ type UnknownFields interface {
 	Range(f func(FieldNumber, RawFields) bool)
 
 	// TODO: Should IsSupported be renamed as ReadOnly?
-	// TODO: Should IsSupported panic on Set instead of silently ignore?
 
-	// IsSupported reports whether this message supports unknown fields.
-	// If false, UnknownFields ignores all Set operations.
+	// IsSupported reports whether this message supports setting unknown fields.
+	// If false, Set operations are ignored by messages which do not store
+	// unknown fields, and panic for read-only messages
+	// (see the protoreadonly package).
 	IsSupported() bool
 }
 
@@ -171,7 +172,7 @@ type RawFields []byte
 func (b RawFields) IsValid() bool {
 	var want FieldNumber
 	for len(b) > 0 {
-		got, _, n := wire.ConsumeField(b)
+		got, _, n := protowire.ConsumeField(b)
 		if n < 0 || (want > 0 && got != want) {
 			return false
 		}
@@ -7,6 +7,7 @@ package protoiface
 import (
 	"github.com/golang/protobuf/v2/internal/pragma"
 	"github.com/golang/protobuf/v2/reflect/protoreflect"
+	"github.com/golang/protobuf/v2/reflect/protoregistry"
 )
 
 // Methoder is an optional interface implemented by generated messages to
@@ -25,7 +26,9 @@ type Methods struct {
 	MarshalAppend func(b []byte, m protoreflect.ProtoMessage, opts MarshalOptions) ([]byte, error)
 
 	// Size returns the size in bytes of the wire-format encoding of m.
-	Size func(m protoreflect.ProtoMessage) int
+	// If opts.UseCachedSize is set, it may return the size cached by a
+	// previous call instead of recomputing it.
+	Size func(m protoreflect.ProtoMessage, opts MarshalOptions) int
 
 	// Unmarshal parses the wire-format message in b and places the result in m.
 	// It does not reset m or perform required field checks.
@@ -51,6 +54,7 @@ const (
 type MarshalOptions struct {
 	AllowPartial  bool
 	Deterministic bool
+	Canonical     bool
 	UseCachedSize bool
 
 	pragma.NoUnkeyedLiterals
@@ -62,6 +66,12 @@ type MarshalOptions struct {
 type UnmarshalOptions struct {
 	AllowPartial   bool
 	DiscardUnknown bool
+	Merge          bool
+	Resolver       *protoregistry.Types
+	RecursionLimit int
+	MaxSize        int
+	AliasBuffer    bool
+	AliasStrings   bool
 
 	pragma.NoUnkeyedLiterals
 }
@@ -0,0 +1,550 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package dynamicpb creates protocol buffer messages using runtime type
+// information, without generated Go types.
+//
+// A dynamic message is constructed from any protoreflect.MessageDescriptor,
+// such as one created by protodesc.NewFile from a descriptor received at
+// runtime. Its fields are accessed through the protoreflect interfaces,
+// and it may be used with proto.Marshal, proto.Unmarshal, and the
+// encoding packages like any generated message.
+//
+// All messages within a dynamic message (the values of message fields,
+// list elements, and map values) are themselves dynamic messages.
+package dynamicpb
+
+import (
+	"container/list"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/golang/protobuf/v2/encoding/protowire"
+	"github.com/golang/protobuf/v2/internal/prototype"
+	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+)
+
+// NewMessageType creates a new MessageType for dynamic messages
+// with the given descriptor.
+func NewMessageType(desc pref.MessageDescriptor) pref.MessageType {
+	return prototype.GoMessage(desc, func(mt pref.MessageType) pref.Message {
+		return newMessage(mt)
+	})
+}
+
+// NewMessage creates a new empty dynamic message with the given descriptor.
+func NewMessage(desc pref.MessageDescriptor) *Message {
+	return newMessage(NewMessageType(desc))
+}
+
+// Message is a dynamic message. It implements both proto.Message
+// and protoreflect.Message.
+//
+// Operations which are safe for concurrent use through the protoreflect
+// interfaces are also safe for concurrent use on a Message.
+type Message struct {
+	typ     pref.MessageType
+	known   map[pref.FieldNumber]pref.Value
+	ext     map[pref.FieldNumber]pref.ExtensionType
+	unknown pref.RawFields
+}
+
+func newMessage(mt pref.MessageType) *Message {
+	m := &Message{typ: mt, known: make(map[pref.FieldNumber]pref.Value)}
+	// Lists and maps are allocated upfront so that the empty values returned
+	// by KnownFields.Get are mutable, without Get mutating the message.
+	fields := mt.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if fd := fields.Get(i); fd.Cardinality() == pref.Repeated {
+			m.known[fd.Number()] = newRepeated(fd)
+		}
+	}
+	return m
+}
+
+// newRepeated returns an empty list or map for the repeated field fd.
+func newRepeated(fd pref.FieldDescriptor) pref.Value {
+	if fd.IsMap() {
+		return pref.ValueOf(&dynamicMap{fd: fd, m: make(map[interface{}]pref.Value)})
+	}
+	return pref.ValueOf(&dynamicList{fd: fd})
+}
+
+// ProtoReflect returns the message itself.
+func (m *Message) ProtoReflect() pref.Message { return m }
+
+// Type returns the type of the message.
+func (m *Message) Type() pref.MessageType { return m.typ }
+
+// KnownFields returns an interface to access and mutate the known fields.
+func (m *Message) KnownFields() pref.KnownFields { return (*knownFields)(m) }
+
+// UnknownFields returns an interface to access and mutate the unknown fields.
+func (m *Message) UnknownFields() pref.UnknownFields { return (*unknownFields)(m) }
+
+// Interface returns the message itself.
+func (m *Message) Interface() pref.ProtoMessage { return m }
+
+// fieldOf returns the descriptor of the known field or registered
+// extension field numbered n, or nil if none.
+func (m *Message) fieldOf(n pref.FieldNumber) pref.FieldDescriptor {
+	if fd := m.typ.Fields().ByNumber(n); fd != nil {
+		return fd
+	}
+	if xt := m.ext[n]; xt != nil {
+		return xt
+	}
+	return nil
+}
+
+type knownFields Message
+
+func (fs *knownFields) Len() (n int) {
+	fs.Range(func(pref.FieldNumber, pref.Value) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+func (fs *knownFields) Has(n pref.FieldNumber) bool {
+	fd := (*Message)(fs).fieldOf(n)
+	if fd == nil {
+		return false
+	}
+	v, ok := fs.known[n]
+	return ok && isPopulated(fd, v)
+}
+
+func (fs *knownFields) Get(n pref.FieldNumber) pref.Value {
+	fd := (*Message)(fs).fieldOf(n)
+	if fd == nil {
+		return pref.Value{}
+	}
+	if v, ok := fs.known[n]; ok {
+		return v
+	}
+	switch fd.Kind() {
+	case pref.MessageKind, pref.GroupKind:
+		return pref.Value{}
+	default:
+		return fd.Default()
+	}
+}
+
+func (fs *knownFields) Set(n pref.FieldNumber, v pref.Value) {
+	fd := (*Message)(fs).fieldOf(n)
+	if fd == nil {
+		panic(fmt.Sprintf("invalid field: %d", n))
+	}
+	if err := checkValue(fd, v); err != nil {
+		panic(fmt.Sprintf("invalid value for field %v: %v", fd.FullName(), err))
+	}
+	if od := fd.OneofType(); od != nil {
+		for i, ofs := 0, od.Fields(); i < ofs.Len(); i++ {
+			delete(fs.known, ofs.Get(i).Number())
+		}
+	}
+	fs.known[n] = v
+}
+
+func (fs *knownFields) Clear(n pref.FieldNumber) {
+	fd := (*Message)(fs).fieldOf(n)
+	switch {
+	case fd == nil:
+	case fd.Cardinality() == pref.Repeated:
+		fs.known[n] = newRepeatedOf(fd)
+	default:
+		delete(fs.known, n)
+	}
+}
+
+// newRepeatedOf returns an empty list or map for the repeated field or
+// extension field fd. The values of extension fields are created by their
+// extension type, which need not be a dynamic extension type.
+func newRepeatedOf(fd pref.FieldDescriptor) pref.Value {
+	if xt, ok := fd.(pref.ExtensionType); ok {
+		return xt.New()
+	}
+	return newRepeated(fd)
+}
+
+func (fs *knownFields) WhichOneof(s pref.Name) pref.FieldNumber {
+	od := fs.typ.Oneofs().ByName(s)
+	if od == nil {
+		return 0
+	}
+	for i, ofs := 0, od.Fields(); i < ofs.Len(); i++ {
+		if n := ofs.Get(i).Number(); fs.Has(n) {
+			return n
+		}
+	}
+	return 0
+}
+
+func (fs *knownFields) Range(f func(pref.FieldNumber, pref.Value) bool) {
+	for n, v := range fs.known {
+		if fd := (*Message)(fs).fieldOf(n); fd != nil && isPopulated(fd, v) {
+			if !f(n, v) {
+				return
+			}
+		}
+	}
+}
+
+func (fs *knownFields) NewMessage(n pref.FieldNumber) pref.Message {
+	fd := (*Message)(fs).fieldOf(n)
+	if fd == nil || fd.Cardinality() == pref.Repeated || (fd.Kind() != pref.MessageKind && fd.Kind() != pref.GroupKind) {
+		panic(fmt.Sprintf("invalid field: %d is not a singular message", n))
+	}
+	if xt, ok := fd.(pref.ExtensionType); ok {
+		return xt.New().Message()
+	}
+	return NewMessage(fd.MessageType())
+}
+
+func (fs *knownFields) ExtensionTypes() pref.ExtensionFieldTypes {
+	return (*extensionTypes)(fs)
+}
+
+// isPopulated reports whether v is a populated value of the field fd.
+func isPopulated(fd pref.FieldDescriptor, v pref.Value) bool {
+	switch {
+	case fd.IsMap():
+		return v.Map().Len() > 0
+	case fd.Cardinality() == pref.Repeated:
+		return v.List().Len() > 0
+	case fd.Syntax() == pref.Proto3 && fd.OneofType() == nil && fd.ExtendedType() == nil:
+		return !isZero(fd.Kind(), v)
+	default:
+		return true
+	}
+}
+
+// isZero reports whether v is the zero value of a scalar of kind k.
+func isZero(k pref.Kind, v pref.Value) bool {
+	switch k {
+	case pref.MessageKind, pref.GroupKind:
+		return false
+	case pref.BoolKind:
+		return !v.Bool()
+	case pref.EnumKind:
+		return v.Enum() == 0
+	case pref.Int32Kind, pref.Sint32Kind, pref.Sfixed32Kind,
+		pref.Int64Kind, pref.Sint64Kind, pref.Sfixed64Kind:
+		return v.Int() == 0
+	case pref.Uint32Kind, pref.Fixed32Kind, pref.Uint64Kind, pref.Fixed64Kind:
+		return v.Uint() == 0
+	case pref.FloatKind, pref.DoubleKind:
+		return math.Float64bits(v.Float()) == 0
+	case pref.StringKind:
+		return len(v.String()) == 0
+	case pref.BytesKind:
+		return len(v.Bytes()) == 0
+	default:
+		return false
+	}
+}
+
+// checkValue reports an error if v is not a valid value for the field fd.
+func checkValue(fd pref.FieldDescriptor, v pref.Value) error {
+	switch {
+	case fd.IsMap():
+		if _, ok := v.Interface().(pref.Map); !ok {
+			return fmt.Errorf("got %T, want map", v.Interface())
+		}
+	case fd.Cardinality() == pref.Repeated:
+		if _, ok := v.Interface().(pref.List); !ok {
+			return fmt.Errorf("got %T, want list", v.Interface())
+		}
+	default:
+		return checkScalar(fd, v)
+	}
+	return nil
+}
+
+// checkScalar reports an error if v is not a valid singular value
+// of the kind of fd.
+func checkScalar(fd pref.FieldDescriptor, v pref.Value) error {
+	var ok bool
+	switch x := v.Interface().(type) {
+	case bool:
+		ok = fd.Kind() == pref.BoolKind
+	case pref.EnumNumber:
+		ok = fd.Kind() == pref.EnumKind
+	case int32:
+		ok = fd.Kind() == pref.Int32Kind || fd.Kind() == pref.Sint32Kind || fd.Kind() == pref.Sfixed32Kind
+	case int64:
+		ok = fd.Kind() == pref.Int64Kind || fd.Kind() == pref.Sint64Kind || fd.Kind() == pref.Sfixed64Kind
+	case uint32:
+		ok = fd.Kind() == pref.Uint32Kind || fd.Kind() == pref.Fixed32Kind
+	case uint64:
+		ok = fd.Kind() == pref.Uint64Kind || fd.Kind() == pref.Fixed64Kind
+	case float32:
+		ok = fd.Kind() == pref.FloatKind
+	case float64:
+		ok = fd.Kind() == pref.DoubleKind
+	case string:
+		ok = fd.Kind() == pref.StringKind
+	case []byte:
+		ok = fd.Kind() == pref.BytesKind
+	case pref.Message:
+		ok = (fd.Kind() == pref.MessageKind || fd.Kind() == pref.GroupKind) &&
+			x.Type().FullName() == fd.MessageType().FullName()
+	}
+	if !ok {
+		return fmt.Errorf("got %T, want %v", v.Interface(), fd.Kind())
+	}
+	return nil
+}
+
+type extensionTypes Message
+
+func (xs *extensionTypes) Len() int { return len(xs.ext) }
+
+func (xs *extensionTypes) Register(xt pref.ExtensionType) {
+	if xt.ExtendedType().FullName() != xs.typ.FullName() {
+		panic("extended type mismatch")
+	}
+	n := xt.Number()
+	if !xs.typ.ExtensionRanges().Has(n) {
+		panic("invalid extension field number")
+	}
+	if xs.ext[n] != nil {
+		panic("extension number conflict")
+	}
+	if xs.ByName(xt.FullName()) != nil {
+		panic("extension name conflict")
+	}
+	if xs.ext == nil {
+		xs.ext = make(map[pref.FieldNumber]pref.ExtensionType)
+	}
+	xs.ext[n] = xt
+	if xt.Cardinality() == pref.Repeated {
+		xs.known[n] = xt.New()
+	}
+}
+
+func (xs *extensionTypes) Remove(xt pref.ExtensionType) {
+	n := xt.Number()
+	if xt2 := xs.ext[n]; xt2 == nil || xt2.FullName() != xt.FullName() {
+		return
+	}
+	if (*knownFields)(xs).Has(n) {
+		panic("value for extension field still populated")
+	}
+	delete(xs.known, n)
+	delete(xs.ext, n)
+}
+
+func (xs *extensionTypes) ByNumber(n pref.FieldNumber) pref.ExtensionType {
+	return xs.ext[n]
+}
+
+func (xs *extensionTypes) ByName(s pref.FullName) pref.ExtensionType {
+	for _, xt := range xs.ext {
+		if xt.FullName() == s {
+			return xt
+		}
+	}
+	return nil
+}
+
+func (xs *extensionTypes) Range(f func(pref.ExtensionType) bool) {
+	for _, xt := range xs.ext {
+		if !f(xt) {
+			return
+		}
+	}
+}
+
+// unknownFields implements the UnknownFields of a message
+// as the raw bytes of all unknown fields.
+type unknownFields Message
+
+func (fs *unknownFields) Len() int {
+	seen := map[pref.FieldNumber]bool{}
+	for b := fs.unknown; len(b) > 0; {
+		num, _, n := protowire.ConsumeField(b)
+		seen[num] = true
+		b = b[n:]
+	}
+	return len(seen)
+}
+
+func (fs *unknownFields) Get(num pref.FieldNumber) (raw pref.RawFields) {
+	for b := fs.unknown; len(b) > 0; {
+		num2, _, n := protowire.ConsumeField(b)
+		if num == num2 {
+			raw = append(raw, b[:n]...)
+		}
+		b = b[n:]
+	}
+	return raw
+}
+
+func (fs *unknownFields) Set(num pref.FieldNumber, raw pref.RawFields) {
+	num2, _, _ := protowire.ConsumeTag(raw)
+	if len(raw) > 0 && (!raw.IsValid() || num != num2) {
+		panic("invalid raw fields")
+	}
+
+	// Remove all current fields of num, then append the new ones.
+	var out pref.RawFields
+	for b := fs.unknown; len(b) > 0; {
+		num2, _, n := protowire.ConsumeField(b)
+		if num != num2 {
+			out = append(out, b[:n]...)
+		}
+		b = b[n:]
+	}
+	fs.unknown = append(out, raw...)
+}
+
+func (fs *unknownFields) Range(f func(pref.FieldNumber, pref.RawFields) bool) {
+	type entry struct {
+		num pref.FieldNumber
+		raw pref.RawFields
+	}
+
+	// Collect the fields of each number, ordered by their last occurrence.
+	// This ranges over a snapshot of the current state such that mutations
+	// while ranging are not observable.
+	l := list.New()
+	m := map[pref.FieldNumber]*list.Element{}
+	for b := fs.unknown; len(b) > 0; {
+		num, _, n := protowire.ConsumeField(b)
+		e, ok := m[num]
+		if !ok {
+			e = l.PushBack(&entry{num: num})
+			m[num] = e
+		}
+		x := e.Value.(*entry)
+		x.raw = append(x.raw, b[:n]...)
+		l.MoveToBack(e)
+		b = b[n:]
+	}
+	for e := l.Front(); e != nil; e = e.Next() {
+		x := e.Value.(*entry)
+		if !f(x.num, x.raw) {
+			return
+		}
+	}
+}
+
+func (fs *unknownFields) IsSupported() bool { return true }
+
+// dynamicList is the list of a repeated field of a dynamic message.
+type dynamicList struct {
+	fd   pref.FieldDescriptor
+	list []pref.Value
+}
+
+func (l *dynamicList) Len() int             { return len(l.list) }
+func (l *dynamicList) Get(i int) pref.Value { return l.list[i] }
+func (l *dynamicList) Set(i int, v pref.Value) {
+	if err := checkScalar(l.fd, v); err != nil {
+		panic(fmt.Sprintf("invalid value for field %v: %v", l.fd.FullName(), err))
+	}
+	l.list[i] = v
+}
+func (l *dynamicList) Append(v pref.Value) {
+	if err := checkScalar(l.fd, v); err != nil {
+		panic(fmt.Sprintf("invalid value for field %v: %v", l.fd.FullName(), err))
+	}
+	l.list = append(l.list, v)
+}
+func (l *dynamicList) Truncate(n int) {
+	for i := n; i < len(l.list); i++ {
+		l.list[i] = pref.Value{} // allow the elements to be garbage collected
+	}
+	l.list = l.list[:n]
+}
+func (l *dynamicList) NewMessage() pref.Message {
+	if md := l.fd.MessageType(); md != nil {
+		return NewMessage(md)
+	}
+	panic(fmt.Sprintf("invalid list: %v is not a message field", l.fd.FullName()))
+}
+
+// dynamicMap is the map of a map field of a dynamic message,
+// keyed by the Go values of the map keys.
+type dynamicMap struct {
+	fd pref.FieldDescriptor
+	m  map[interface{}]pref.Value
+}
+
+func (m *dynamicMap) Len() int                     { return len(m.m) }
+func (m *dynamicMap) Has(k pref.MapKey) bool       { _, ok := m.m[k.Interface()]; return ok }
+func (m *dynamicMap) Get(k pref.MapKey) pref.Value { return m.m[k.Interface()] }
+func (m *dynamicMap) Set(k pref.MapKey, v pref.Value) {
+	entry := m.fd.MessageType().Fields()
+	if err := checkScalar(entry.ByNumber(1), k.Value()); err != nil {
+		panic(fmt.Sprintf("invalid key for field %v: %v", m.fd.FullName(), err))
+	}
+	if err := checkScalar(entry.ByNumber(2), v); err != nil {
+		panic(fmt.Sprintf("invalid value for field %v: %v", m.fd.FullName(), err))
+	}
+	m.m[k.Interface()] = v
+}
+func (m *dynamicMap) Clear(k pref.MapKey) { delete(m.m, k.Interface()) }
+func (m *dynamicMap) Range(f func(pref.MapKey, pref.Value) bool) {
+	for k, v := range m.m {
+		if !f(pref.ValueOf(k).MapKey(), v) {
+			return
+		}
+	}
+}
+func (m *dynamicMap) NewMessage() pref.Message {
+	if md := m.fd.MessageType().Fields().ByNumber(2).MessageType(); md != nil {
+		return NewMessage(md)
+	}
+	panic(fmt.Sprintf("invalid map: %v does not have message values", m.fd.FullName()))
+}
+
+// NewExtensionType creates a new ExtensionType for extension fields with the
+// given descriptor, whose values are those of fields of a dynamic message.
+//
+// The Go type of a singular field is the same as that of protoreflect.Value
+// for the field kind, with protoreflect.EnumNumber for enums and *Message for
+// messages. The Go type of a repeated field is a protoreflect.List.
+func NewExtensionType(desc pref.ExtensionDescriptor) pref.ExtensionType {
+	if desc.ExtendedType() == nil {
+		panic("field descriptor does not extend a message")
+	}
+	return &extensionType{ExtensionDescriptor: desc}
+}
+
+type extensionType struct {
+	pref.ExtensionDescriptor
+}
+
+func (xt *extensionType) New() pref.Value {
+	switch {
+	case xt.Cardinality() == pref.Repeated:
+		return pref.ValueOf(&dynamicList{fd: xt})
+	case xt.Kind() == pref.MessageKind || xt.Kind() == pref.GroupKind:
+		return pref.ValueOf(NewMessage(xt.MessageType()))
+	default:
+		return xt.Default()
+	}
+}
+
+func (xt *extensionType) GoType() reflect.Type {
+	return reflect.TypeOf(xt.InterfaceOf(xt.New()))
+}
+
+func (xt *extensionType) ValueOf(v interface{}) pref.Value {
+	return pref.ValueOf(v)
+}
+
+func (xt *extensionType) InterfaceOf(v pref.Value) interface{} {
+	switch x := v.Interface().(type) {
+	case pref.Message:
+		return x.Interface()
+	default:
+		return x
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dynamicpb_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/v2/encoding/jsonpb"
+	"github.com/golang/protobuf/v2/encoding/textpb"
+	"github.com/golang/protobuf/v2/internal/encoding/pack"
+	"github.com/golang/protobuf/v2/internal/scalar"
+	"github.com/golang/protobuf/v2/proto"
+	pref "github.com/golang/protobuf/v2/reflect/protoreflect"
+	preg "github.com/golang/protobuf/v2/reflect/protoregistry"
+	"github.com/golang/protobuf/v2/types/dynamicpb"
+
+	testpb "github.com/golang/protobuf/v2/internal/testprotos/test"
+	test3pb "github.com/golang/protobuf/v2/internal/testprotos/test3"
+)
+
+func populated() *testpb.TestAllTypes {
+	m := &testpb.TestAllTypes{
+		OptionalInt32:         scalar.Int32(1),
+		OptionalSint64:        scalar.Int64(-2),
+		OptionalFixed32:       scalar.Uint32(3),
+		OptionalDouble:        scalar.Float64(4.5),
+		OptionalBool:          scalar.Bool(true),
+		OptionalString:        scalar.String("string"),
+		OptionalBytes:         []byte("bytes"),
+		Optionalgroup:         &testpb.TestAllTypes_OptionalGroup{A: scalar.Int32(5)},
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: scalar.Int32(6)},
+		OptionalNestedEnum:    testpb.TestAllTypes_BAR.Enum(),
+		RepeatedInt32:         []int32{7, 8},
+		RepeatedString:        []string{"a", "b"},
+		RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{
+			{A: scalar.Int32(9)},
+			{Corecursive: &testpb.TestAllTypes{OptionalInt32: scalar.Int32(10)}},
+		},
+		RepeatedNestedEnum: []testpb.TestAllTypes_NestedEnum{testpb.TestAllTypes_FOO},
+		MapInt32Int32:      map[int32]int32{11: 12, 13: 14},
+		MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+			"k": {A: scalar.Int32(15)},
+		},
+		OneofField: &testpb.TestAllTypes_OneofString{OneofString: "oneof"},
+	}
+	m.ProtoReflect().UnknownFields().Set(100000, pack.Message{
+		pack.Tag{100000, pack.VarintType}, pack.Varint(16),
+	}.Marshal())
+	return m
+}
+
+func newDynamic(m proto.Message) *dynamicpb.Message {
+	return dynamicpb.NewMessage(m.ProtoReflect().Type())
+}
+
+func marshal(t *testing.T, m proto.Message) []byte {
+	t.Helper()
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	return b
+}
+
+func TestWire(t *testing.T) {
+	want := populated()
+	b := marshal(t, want)
+	got := newDynamic(want)
+	if err := proto.Unmarshal(b, got); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	if b2 := marshal(t, got); !bytes.Equal(b2, b) {
+		t.Errorf("Marshal(dynamic) = %x, want %x", b2, b)
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("Equal(dynamic, generated) = false, want true")
+	}
+	if got, want := proto.Size(got), len(b); got != want {
+		t.Errorf("Size(dynamic) = %v, want %v", got, want)
+	}
+
+	// The fields of nested messages are accessible through reflection.
+	fields := got.Type().Fields()
+	nested := got.KnownFields().Get(fields.ByName("repeated_nested_message").Number()).List().Get(1).Message()
+	corecursive := nested.KnownFields().Get(nested.Type().Fields().ByName("corecursive").Number()).Message()
+	if _, ok := corecursive.(*dynamicpb.Message); !ok {
+		t.Errorf("nested message is %T, want *dynamicpb.Message", corecursive)
+	}
+	if got, want := corecursive.KnownFields().Get(fields.ByName("optional_int32").Number()).Int(), int64(10); got != want {
+		t.Errorf("corecursive.optional_int32 = %v, want %v", got, want)
+	}
+	if got, want := got.KnownFields().WhichOneof("oneof_field"), fields.ByName("oneof_string").Number(); got != want {
+		t.Errorf("WhichOneof(oneof_field) = %v, want %v", got, want)
+	}
+}
+
+func TestText(t *testing.T) {
+	want := populated()
+	want.ProtoReflect().UnknownFields().Set(100000, nil)
+	m := newDynamic(want)
+	if err := proto.Unmarshal(marshal(t, want), m); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+
+	for _, test := range []struct {
+		desc      string
+		marshal   func(proto.Message) ([]byte, error)
+		unmarshal func(proto.Message, []byte) error
+	}{
+		{"textpb", textpb.Marshal, textpb.Unmarshal},
+		{"jsonpb", jsonpb.Marshal, jsonpb.Unmarshal},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			wantText, err := test.marshal(want)
+			if err != nil {
+				t.Fatalf("Marshal(generated) error: %v", err)
+			}
+			gotText, err := test.marshal(m)
+			if err != nil {
+				t.Fatalf("Marshal(dynamic) error: %v", err)
+			}
+			if !bytes.Equal(gotText, wantText) {
+				t.Errorf("Marshal(dynamic):\n%s\nwant:\n%s", gotText, wantText)
+			}
+			got := newDynamic(want)
+			if err := test.unmarshal(got, wantText); err != nil {
+				t.Fatalf("Unmarshal() error: %v", err)
+			}
+			if !proto.Equal(got, want) {
+				t.Errorf("Unmarshal(%s) = %v, want %v", wantText, got, want)
+			}
+		})
+	}
+}
+
+func TestProto3(t *testing.T) {
+	m := newDynamic(&test3pb.TestAllTypes{})
+	fields := m.Type().Fields()
+	num := fields.ByName("optional_int32").Number()
+	knownFields := m.KnownFields()
+
+	knownFields.Set(num, pref.ValueOf(int32(0)))
+	if knownFields.Has(num) {
+		t.Errorf("Has(optional_int32) = true after setting zero value, want false")
+	}
+	if got := knownFields.Len(); got != 0 {
+		t.Errorf("Len() = %v after setting zero value, want 0", got)
+	}
+	knownFields.Set(num, pref.ValueOf(int32(1)))
+	if !knownFields.Has(num) {
+		t.Errorf("Has(optional_int32) = false after setting non-zero value, want true")
+	}
+	if b, want := marshal(t, m), (pack.Message{pack.Tag{1, pack.VarintType}, pack.Varint(1)}.Marshal()); !bytes.Equal(b, want) {
+		t.Errorf("Marshal() = %x, want %x", b, want)
+	}
+
+	// Empty lists are mutable, and are not populated.
+	list := knownFields.Get(fields.ByName("repeated_int32").Number()).List()
+	if knownFields.Has(fields.ByName("repeated_int32").Number()) {
+		t.Errorf("Has(repeated_int32) = true, want false")
+	}
+	list.Append(pref.ValueOf(int32(2)))
+	if !knownFields.Has(fields.ByName("repeated_int32").Number()) {
+		t.Errorf("Has(repeated_int32) = false after Append, want true")
+	}
+}
+
+func TestExtensions(t *testing.T) {
+	var xts []pref.ExtensionType
+	for _, name := range []pref.FullName{
+		"goproto.proto.test.optional_int32_extension",
+		"goproto.proto.test.optional_nested_message_extension",
+		"goproto.proto.test.repeated_string_extension",
+	} {
+		xt, err := preg.GlobalTypes.FindExtensionByName(name)
+		if err != nil {
+			t.Fatalf("FindExtensionByName(%v) error: %v", name, err)
+		}
+		xts = append(xts, dynamicpb.NewExtensionType(xt))
+	}
+	resolver := preg.NewTypes()
+	for _, xt := range xts {
+		if err := resolver.Register(xt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := &testpb.TestAllExtensions{}
+	wantFields := want.ProtoReflect().KnownFields()
+	wantFields.ExtensionTypes().Register(testpb.E_OptionalInt32Extension.Type)
+	wantFields.ExtensionTypes().Register(testpb.E_OptionalNestedMessageExtension.Type)
+	wantFields.ExtensionTypes().Register(testpb.E_RepeatedStringExtension.Type)
+	wantFields.Set(1, pref.ValueOf(int32(1)))
+	nested := wantFields.NewMessage(18)
+	nested.KnownFields().Set(1, pref.ValueOf(int32(2)))
+	wantFields.Set(18, pref.ValueOf(nested))
+	wantFields.Get(44).List().Append(pref.ValueOf("a"))
+	b := marshal(t, want)
+
+	got := newDynamic(want)
+	if err := (proto.UnmarshalOptions{Resolver: resolver}).Unmarshal(b, got); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	if got.UnknownFields().Len() != 0 {
+		t.Errorf("extensions decoded as unknown fields: %x", got.UnknownFields().Get(1))
+	}
+	gotFields := got.KnownFields()
+	if got, want := gotFields.Len(), 3; got != want {
+		t.Errorf("Len() = %v, want %v", got, want)
+	}
+	if _, ok := gotFields.Get(18).Message().(*dynamicpb.Message); !ok {
+		t.Errorf("extension message is %T, want *dynamicpb.Message", gotFields.Get(18).Message())
+	}
+	if b2 := marshal(t, got); !bytes.Equal(b2, b) {
+		t.Errorf("Marshal(dynamic) = %x, want %x", b2, b)
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("Equal(dynamic, generated) = false, want true")
+	}
+
+	gotFields.Clear(44)
+	if gotFields.Has(44) {
+		t.Errorf("Has(44) = true after Clear, want false")
+	}
+	gotFields.Get(44).List().Append(pref.ValueOf("b"))
+	if got, want := gotFields.Get(44).List().Len(), 1; got != want {
+		t.Errorf("repeated extension length after Clear and Append = %v, want %v", got, want)
+	}
+}